@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -25,11 +26,15 @@ import (
 
 // AWSNodeManagerSpec defines the desired state of AWSNodeManager
 type AWSNodeManagerSpec struct {
+	CloudTarget `json:",inline"`
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:Type:=string
 	Region string `json:"region"`
-	// +kubebuilder:validation:Required
-	AutoScalingGroups []AutoScalingGroup `json:"autoScalingGroups"`
+	// +optional
+	AutoScalingGroups []AutoScalingGroup `json:"autoScalingGroups,omitempty"`
+	// TagSelector discovers AutoScalingGroups which have all of the tags in addition to AutoScalingGroups.
+	// +optional
+	TagSelector map[string]string `json:"tagSelector,omitempty"`
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:Type:=integer
 	Desired int32 `json:"desired"`
@@ -53,6 +58,40 @@ type AWSNodeManagerSpec struct {
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:Type=integer
 	DrainGracePeriodSeconds int64 `json:"drainGracePeriodSeconds"`
+	// +optional
+	RefreshStrategy RefreshStrategy `json:"refreshStrategy,omitempty"`
+	// +optional
+	// +nullable
+	InstanceRefresh *InstanceRefreshPreferences `json:"instanceRefresh,omitempty"`
+	// +optional
+	RefreshTrigger RefreshTrigger `json:"refreshTrigger,omitempty"`
+	// +optional
+	// +nullable
+	AMISource *AMISource `json:"amiSource,omitempty"`
+	// +optional
+	// +nullable
+	MaxNodeAge *metav1.Duration `json:"maxNodeAge,omitempty"`
+	// +optional
+	// +nullable
+	MaintenanceWindow *MaintenanceWindow `json:"maintenanceWindow,omitempty"`
+	// +optional
+	SuspendRefresh bool `json:"suspendRefresh,omitempty"`
+	// RefreshNow is set to the refresh-now annotation of AWSNodeRefresher.
+	// +optional
+	RefreshNow string `json:"refreshNow,omitempty"`
+	// +optional
+	// +nullable
+	RefreshTimeouts *PhaseTimeouts `json:"refreshTimeouts,omitempty"`
+	// +optional
+	// +nullable
+	// +kubebuilder:validation:XIntOrString
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+	// +optional
+	// +nullable
+	// +kubebuilder:validation:XIntOrString
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+	// +optional
+	ReplacementOrder ReplacementOrder `json:"replacementOrder,omitempty"`
 }
 
 // AWSNodeManagerStatus defines the observed state of AWSNodeManager
@@ -63,6 +102,9 @@ type AWSNodeManagerStatus struct {
 	NodeRefresher     *AWSNodeRefresherRef `json:"nodeRefresher"`
 	AWSNodes          []AWSNode            `json:"awsNodes,omitempty"`
 	NotJoinedAWSNodes []AWSNode            `json:"notJoinedAwsNodes,omitempty"`
+	// AutoScalingGroups are resolved from AutoScalingGroups and TagSelector in spec.
+	// +optional
+	AutoScalingGroups []AutoScalingGroup `json:"autoScalingGroups,omitempty"`
 	// +optinal
 	// +nullable
 	LastASGModifiedTime *metav1.Time `json:"lastASGModifiedTime,omitempty"`
@@ -70,10 +112,15 @@ type AWSNodeManagerStatus struct {
 	Revision int64 `json:"revision"`
 	// +kubebuilder:default=init
 	Phase AWSNodeManagerPhase `json:"phase"`
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // AWSNodeManager is the Schema for the awsnodemanagers API
@@ -101,6 +148,9 @@ func init() {
 type AWSNode struct {
 	// Node name in the Kubernetes cluster
 	Name string `json:"name"`
+	// ProviderID of the node in the Kubernetes cluster, which identifies the instance in the cloud provider
+	// +optional
+	ProviderID string `json:"providerID,omitempty"`
 	// InstanceID of EC2 instances
 	InstanceID           string      `json:"instanceID"`
 	AvailabilityZone     string      `json:"availabilityZone"`
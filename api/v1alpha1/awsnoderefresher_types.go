@@ -18,10 +18,12 @@ package v1alpha1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // AWSNodeRefresherSpec defines the desired state of AWSNodeRefresher
 type AWSNodeRefresherSpec struct {
+	CloudTarget `json:",inline"`
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:Type:=string
 	Region string `json:"region"`
@@ -36,7 +38,9 @@ type AWSNodeRefresherSpec struct {
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:Type:=string
 	Role NodeRole `json:"role"`
-	// +kubebuilder:validation:Required
+	// Schedule is required unless trigger is drift, amiSource, maxNodeAge or maintenanceWindow is specified.
+	// With amiSource or maxNodeAge, it is a maintenance window, and a refresh waits for the next schedule.
+	// +optional
 	// +kubebuilder:valitation:Type:=string
 	Schedule string `json:"schedule"`
 	// +optional
@@ -46,6 +50,51 @@ type AWSNodeRefresherSpec struct {
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:Type=integer
 	DrainGracePeriodSeconds int64 `json:"drainGracePeriodSeconds"`
+	// Strategy is how to replace nodes. Empty is the same as rolling.
+	// +optional
+	Strategy RefreshStrategy `json:"strategy,omitempty"`
+	// InstanceRefresh is used when strategy is instanceRefresh.
+	// +optional
+	// +nullable
+	InstanceRefresh *InstanceRefreshPreferences `json:"instanceRefresh,omitempty"`
+	// Trigger is what starts a refresh. Empty is the same as schedule.
+	// +optional
+	Trigger RefreshTrigger `json:"trigger,omitempty"`
+	// AMISource starts a refresh when the AMI which is published to it changes, instead of every schedule.
+	// +optional
+	// +nullable
+	AMISource *AMISource `json:"amiSource,omitempty"`
+	// MaxNodeAge replaces only nodes which are older than it, oldest first, instead of all nodes every schedule.
+	// +optional
+	// +nullable
+	MaxNodeAge *metav1.Duration `json:"maxNodeAge,omitempty"`
+	// MaintenanceWindow limits when nodes are replaced instead of schedule.
+	// +optional
+	// +nullable
+	MaintenanceWindow *MaintenanceWindow `json:"maintenanceWindow,omitempty"`
+	// Suspend stops starting refreshes, and pauses a running refresh between replacements.
+	// It takes effect at the next batch boundary, so nodes in the current batch are still drained and replaced.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+	// Timeouts are deadlines of phases in rolling strategy. The refresh fails when a phase does not finish in its deadline.
+	// Failed phase goes back to scheduled phase after the surplus nodes are removed, so the next schedule or trigger retries the refresh.
+	// +optional
+	// +nullable
+	Timeouts *PhaseTimeouts `json:"timeouts,omitempty"`
+	// MaxSurge is how many nodes are added over desired during a refresh, as a number or a percentage of desired.
+	// It is used instead of surplusNodes, and nodes are replaced in batches of maxSurge + maxUnavailable.
+	// +optional
+	// +nullable
+	// +kubebuilder:validation:XIntOrString
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+	// MaxUnavailable is how many nodes can be unavailable below desired during a refresh, as a number or a percentage of desired.
+	// +optional
+	// +nullable
+	// +kubebuilder:validation:XIntOrString
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+	// ReplacementOrder is how nodes to replace are chosen in rolling strategy. Empty is the same as oldestFirst.
+	// +optional
+	ReplacementOrder ReplacementOrder `json:"replacementOrder,omitempty"`
 }
 
 // AWSNodeRefresherStatus defines the observed state of AWSNodeRefresher
@@ -64,13 +113,55 @@ type AWSNodeRefresherStatus struct {
 	// +optinal
 	// +nullable
 	UpdateStartTime *metav1.Time `json:"updateStartTime"`
+	// ReplaceTargetNode is the replace target which is recorded before nodes are replaced in batches.
+	// Deprecated: It is moved to replaceTargetNodes on the next reconcile.
 	// +optional
 	// +nullable
 	ReplaceTargetNode *AWSNode `json:"replaceTargetNode,omitempty"`
+	// ReplaceTargetNodes are nodes which are drained and replaced in the current batch
+	// +optional
+	ReplaceTargetNodes []AWSNode `json:"replaceTargetNodes,omitempty"`
+	// PodDisruptionBudgets which block eviction of pods on the replace target nodes
+	// +optional
+	BlockingPodDisruptionBudgets []string `json:"blockingPodDisruptionBudgets,omitempty"`
+	// InstanceRefreshes are Instance Refreshes which are started for the AutoScalingGroups in instanceRefresh strategy
+	// +optional
+	InstanceRefreshes []InstanceRefreshStatus `json:"instanceRefreshes,omitempty"`
+	// DrainingNodes are nodes which are drained while their instances wait for the termination lifecycle hook
+	// +optional
+	DrainingNodes []DrainingNode `json:"drainingNodes,omitempty"`
+	// DriftedNodes are nodes which are out of date against their AutoScalingGroups in drift trigger
+	// +optional
+	DriftedNodes []DriftedNode `json:"driftedNodes,omitempty"`
+	// AMISource is the AMI which is observed in spec.amiSource
+	// +optional
+	// +nullable
+	AMISource *AMISourceStatus `json:"amiSource,omitempty"`
+	// LastRefreshNow is the value of the refresh-now annotation which has been handled.
+	// +optional
+	LastRefreshNow string `json:"lastRefreshNow,omitempty"`
+	// OnDemand is true while the refresh is started by the refresh-now annotation, then all nodes are replaced.
+	// +optional
+	OnDemand bool `json:"onDemand,omitempty"`
+	// PhaseStartTime is when the current phase of the refresh started, and it is compared with timeouts.
+	// +optional
+	// +nullable
+	PhaseStartTime *metav1.Time `json:"phaseStartTime,omitempty"`
+	// FailureReason describes why the last refresh failed.
+	// +optional
+	FailureReason string `json:"failureReason,omitempty"`
+	// SurplusRolledBack is true when the surplus instances of the failed refresh have been removed, then the next refresh can start.
+	// +optional
+	SurplusRolledBack bool `json:"surplusRolledBack,omitempty"`
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // AWSNodeRefresher is the Schema for the awsnoderefreshers API
@@ -95,6 +186,9 @@ func init() {
 	SchemeBuilder.Register(&AWSNodeRefresher{}, &AWSNodeRefresherList{})
 }
 
+// RefreshNowAnnotation starts a refresh immediately when a new value, for example the current timestamp, is set.
+const RefreshNowAnnotation = "operator.h3poteto.dev/refresh-now"
+
 type AWSNodeRefresherPhase string
 
 const (
@@ -106,4 +200,172 @@ const (
 	AWSNodeRefresherUpdateAWSWaiting = AWSNodeRefresherPhase("awsWaiting")
 	AWSNodeRefresherUpdateDecreasing = AWSNodeRefresherPhase("decreasing")
 	AWSNodeRefresherCompleted        = AWSNodeRefresherPhase("completed")
+	// AWSNodeRefresherPaused is the phase between replacements after the maintenance window is closed.
+	AWSNodeRefresherPaused = AWSNodeRefresherPhase("paused")
+	// AWSNodeRefresherFailed is the phase after a phase does not finish in its deadline.
+	// Surplus nodes are removed in this phase, and it stays until refresh-now is requested.
+	AWSNodeRefresherFailed = AWSNodeRefresherPhase("failed")
+	// AWSNodeRefresherInstanceRefreshing is the phase while Instance Refreshes replace nodes in instanceRefresh strategy.
+	AWSNodeRefresherInstanceRefreshing = AWSNodeRefresherPhase("instanceRefreshing")
+)
+
+// RefreshStrategy is how AWSNodeRefresher replaces nodes.
+// +kubebuilder:validation:Enum=rolling;instanceRefresh
+type RefreshStrategy string
+
+const (
+	// RefreshStrategyRolling increases, drains, replaces and decreases nodes one by one in the controller.
+	RefreshStrategyRolling = RefreshStrategy("rolling")
+	// RefreshStrategyInstanceRefresh starts Instance Refresh of EC2 Auto Scaling, and the controller only drains nodes and observes it.
+	RefreshStrategyInstanceRefresh = RefreshStrategy("instanceRefresh")
+)
+
+// ReplacementOrder is how AWSNodeRefresher chooses nodes to replace among the candidates.
+// +kubebuilder:validation:Enum=oldestFirst;azBalanced;leastPods;instanceTypeMismatch
+type ReplacementOrder string
+
+const (
+	// ReplacementOrderOldestFirst replaces the oldest nodes first.
+	ReplacementOrderOldestFirst = ReplacementOrder("oldestFirst")
+	// ReplacementOrderAZBalanced replaces nodes in the availability zone which has the most nodes first, to keep zones balanced.
+	ReplacementOrderAZBalanced = ReplacementOrder("azBalanced")
+	// ReplacementOrderLeastPods replaces nodes which run the fewest pods first, to evict as few pods as possible.
+	ReplacementOrderLeastPods = ReplacementOrder("leastPods")
+	// ReplacementOrderInstanceTypeMismatch replaces nodes whose instance type differs from the launch template of their AutoScalingGroups first.
+	ReplacementOrderInstanceTypeMismatch = ReplacementOrder("instanceTypeMismatch")
+)
+
+// RefreshTrigger is what starts a refresh of AWSNodeRefresher.
+// +kubebuilder:validation:Enum=schedule;drift
+type RefreshTrigger string
+
+const (
+	// RefreshTriggerSchedule starts a refresh on the schedule, and replaces all nodes.
+	RefreshTriggerSchedule = RefreshTrigger("schedule")
+	// RefreshTriggerDrift starts a refresh when any node is out of date against its AutoScalingGroup, and replaces only drifted nodes.
+	RefreshTriggerDrift = RefreshTrigger("drift")
 )
+
+// InstanceRefreshPreferences are preferences of Instance Refresh of EC2 Auto Scaling.
+// Unspecified values fall back to the defaults of EC2 Auto Scaling.
+type InstanceRefreshPreferences struct {
+	// MinHealthyPercentage is the percentage of the desired capacity which must remain healthy during the refresh.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	MinHealthyPercentage *int64 `json:"minHealthyPercentage,omitempty"`
+	// InstanceWarmupSeconds is the time until a new instance is considered healthy after it reaches InService.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	InstanceWarmupSeconds *int64 `json:"instanceWarmupSeconds,omitempty"`
+	// CheckpointPercentages pause the refresh when the percentages of instances are replaced. The last one must be 100.
+	// +optional
+	CheckpointPercentages []int64 `json:"checkpointPercentages,omitempty"`
+	// CheckpointDelaySeconds is the time to wait at each checkpoint.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	CheckpointDelaySeconds *int64 `json:"checkpointDelaySeconds,omitempty"`
+	// SkipMatching skips instances which already run the desired configuration.
+	// +optional
+	SkipMatching bool `json:"skipMatching,omitempty"`
+	// LifecycleHookName is a termination lifecycle hook of the AutoScalingGroups.
+	// When it is specified, nodes are drained while their instances wait for the hook, and the hook is completed after that.
+	// Otherwise EC2 Auto Scaling terminates instances without draining nodes.
+	// +optional
+	LifecycleHookName string `json:"lifecycleHookName,omitempty"`
+}
+
+// InstanceRefreshStatus is the state of an Instance Refresh of an AutoScalingGroup.
+type InstanceRefreshStatus struct {
+	AutoScalingGroupName string `json:"autoScalingGroupName"`
+	InstanceRefreshID    string `json:"instanceRefreshID"`
+	// Status is the status reported by EC2 Auto Scaling, for example InProgress or Successful.
+	// +optional
+	Status string `json:"status,omitempty"`
+	// +optional
+	StatusReason string `json:"statusReason,omitempty"`
+	// +optional
+	PercentageComplete int64 `json:"percentageComplete,omitempty"`
+	// +optional
+	InstancesToUpdate int64 `json:"instancesToUpdate,omitempty"`
+}
+
+// DrainingNode is a node which is drained before its instance is terminated.
+type DrainingNode struct {
+	AWSNode `json:",inline"`
+	// DrainStartTime is used to give up draining after drainGracePeriodSeconds.
+	DrainStartTime metav1.Time `json:"drainStartTime"`
+}
+
+// DriftedNode is a node whose instance does not match the current launch template or launch configuration of its AutoScalingGroup.
+type DriftedNode struct {
+	// Node name in the Kubernetes cluster
+	Name                 string `json:"name"`
+	InstanceID           string `json:"instanceID"`
+	AutoScalingGroupName string `json:"autoScalingGroupName"`
+	// Reason describes what is out of date, for example the version of the launch template.
+	Reason string `json:"reason"`
+}
+
+// AMISource is where AMIs of nodes are published.
+type AMISource struct {
+	// SSMParameter is the name of an SSM parameter whose value is an AMI ID, for example /aws/service/eks/optimized-ami/1.33/amazon-linux-2023/x86_64/standard/recommended/image_id.
+	// +kubebuilder:validation:Required
+	SSMParameter string `json:"ssmParameter"`
+}
+
+// AMISourceStatus is the AMI which is observed in AMISource.
+type AMISourceStatus struct {
+	// LastSeenValue is the latest value of the parameter.
+	LastSeenValue string `json:"lastSeenValue"`
+	// RefreshedValue is the value when the last refresh started. A refresh is pending while it differs from LastSeenValue.
+	// +optional
+	RefreshedValue string `json:"refreshedValue,omitempty"`
+}
+
+// MaintenanceWindow is a period when nodes can be replaced.
+type MaintenanceWindow struct {
+	// Schedule is a cron expression when the window opens.
+	// +kubebuilder:validation:Required
+	Schedule string `json:"schedule"`
+	// Duration is how long the window is open, for example 4h.
+	// +kubebuilder:validation:Required
+	Duration metav1.Duration `json:"duration"`
+	// TimeZone is an IANA time zone which schedule is evaluated in, for example Asia/Tokyo. Empty is UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+	// Blackouts are periods when nodes are not replaced even in the window, for example holiday freezes.
+	// +optional
+	Blackouts []BlackoutPeriod `json:"blackouts,omitempty"`
+}
+
+// BlackoutPeriod is a period when nodes are not replaced.
+type BlackoutPeriod struct {
+	// +kubebuilder:validation:Required
+	Start metav1.Time `json:"start"`
+	// End is exclusive.
+	// +kubebuilder:validation:Required
+	End metav1.Time `json:"end"`
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// PhaseTimeouts are deadlines of phases in a refresh. A phase without deadline waits forever.
+type PhaseTimeouts struct {
+	// Increase is the deadline until the surplus nodes join the cluster.
+	// +optional
+	// +nullable
+	Increase *metav1.Duration `json:"increase,omitempty"`
+	// Replace is the deadline until the drained node leaves the cluster.
+	// +optional
+	// +nullable
+	Replace *metav1.Duration `json:"replace,omitempty"`
+	// AWSWait is the deadline until the new node joins the cluster after the replacement.
+	// +optional
+	// +nullable
+	AWSWait *metav1.Duration `json:"awsWait,omitempty"`
+	// Decrease is the deadline until the surplus nodes leave the cluster.
+	// +optional
+	// +nullable
+	Decrease *metav1.Duration `json:"decrease,omitempty"`
+}
@@ -22,6 +22,7 @@ import (
 
 // AWSNodeReplenisherSpec defines the desired state of AWSNodeReplenisher
 type AWSNodeReplenisherSpec struct {
+	CloudTarget `json:",inline"`
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:Type:=string
 	Region string `json:"region"`
@@ -49,10 +50,15 @@ type AWSNodeReplenisherStatus struct {
 	Revision int64 `json:"revision"`
 	// +kubebuilder:default=init
 	Phase AWSNodeReplenisherPhase `json:"phase"`
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // AWSNodeReplenisher is the Schema for the awsnodereplenishers API
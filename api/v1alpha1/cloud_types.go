@@ -0,0 +1,79 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// CloudTarget specifies the cloud provider which owns node groups in AutoScalingGroups.
+// Names in AutoScalingGroups are treated as node groups of the cloud provider, for example Managed Instance Groups in GCP,
+// Virtual Machine Scale Sets in Azure and MachineDeployments in Cluster API.
+type CloudTarget struct {
+	// +optional
+	// +kubebuilder:validation:Type:=string
+	// +kubebuilder:validation:Enum=aws;gcp;azure;clusterapi
+	// +kubebuilder:default=aws
+	CloudProvider string `json:"cloudProvider,omitempty"`
+	// +optional
+	// +nullable
+	AWS *AWSTarget `json:"aws,omitempty"`
+	// +optional
+	// +nullable
+	GCP *GCPTarget `json:"gcp,omitempty"`
+	// +optional
+	// +nullable
+	Azure *AzureTarget `json:"azure,omitempty"`
+	// +optional
+	// +nullable
+	ClusterAPI *ClusterAPITarget `json:"clusterAPI,omitempty"`
+}
+
+// AWSTarget specifies how to access AWS APIs, for example an IAM role in another account.
+type AWSTarget struct {
+	// +optional
+	// +kubebuilder:validation:Type:=string
+	RoleARN string `json:"roleARN,omitempty"`
+	// ExternalID is passed to AssumeRole when the trust policy of the role requires it.
+	// +optional
+	// +kubebuilder:validation:Type:=string
+	ExternalID string `json:"externalID,omitempty"`
+	// Endpoint overrides the endpoint URL of AWS APIs, for example LocalStack.
+	// +optional
+	// +kubebuilder:validation:Type:=string
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+type GCPTarget struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Type:=string
+	Project string `json:"project"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Type:=string
+	Zone string `json:"zone"`
+}
+
+type AzureTarget struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Type:=string
+	SubscriptionID string `json:"subscriptionID"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Type:=string
+	ResourceGroup string `json:"resourceGroup"`
+}
+
+type ClusterAPITarget struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Type:=string
+	Namespace string `json:"namespace"`
+}
@@ -0,0 +1,43 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// Condition types which are reported in status.conditions of all resources.
+const (
+	// ConditionReady is true when the resource is synced and no operation is in progress.
+	ConditionReady = "Ready"
+	// ConditionRefreshing is true while nodes are being refreshed.
+	ConditionRefreshing = "Refreshing"
+	// ConditionReplenishing is true while nodes are being replenished.
+	ConditionReplenishing = "Replenishing"
+	// ConditionDegraded is true when the last sync failed.
+	ConditionDegraded = "Degraded"
+	// ConditionCloudAPIError is true when the last sync failed because of the cloud provider API.
+	ConditionCloudAPIError = "CloudAPIError"
+)
+
+// Condition reasons which are shared across resources.
+const (
+	ReasonInitializing = "Initializing"
+	ReasonSynced       = "Synced"
+	ReasonSyncFailed   = "SyncFailed"
+	ReasonSucceeded    = "Succeeded"
+	ReasonIdle         = "Idle"
+	ReasonRefreshing   = "Refreshing"
+	ReasonReplenishing = "Replenishing"
+	ReasonNotFound     = "NotFound"
+)
@@ -18,6 +18,18 @@ package v1alpha1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// CloudProviderAWS is a value of CloudProvider for Amazon Web Services.
+	CloudProviderAWS = "aws"
+	// CloudProviderGCP is a value of CloudProvider for Google Cloud Platform.
+	CloudProviderGCP = "gcp"
+	// CloudProviderAzure is a value of CloudProvider for Microsoft Azure.
+	CloudProviderAzure = "azure"
+	// CloudProviderClusterAPI is a value of CloudProvider for clusters which are managed by Cluster API.
+	CloudProviderClusterAPI = "clusterapi"
 )
 
 // NodeManagerSpec defines the desired state of NodeManager
@@ -25,10 +37,16 @@ type NodeManagerSpec struct {
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:Type:=string
 	// +kubebuilder:default=aws
-	// +kubebuilder:validation:Enum=aws
+	// +kubebuilder:validation:Enum=aws;gcp;azure;clusterapi
 	CloudProvider string `json:"cloudProvider"`
 	// +nullable
 	Aws *CloudAWS `json:"aws,omitempty"`
+	// +nullable
+	GCP *CloudGCP `json:"gcp,omitempty"`
+	// +nullable
+	Azure *CloudAzure `json:"azure,omitempty"`
+	// +nullable
+	ClusterAPI *CloudClusterAPI `json:"clusterAPI,omitempty"`
 }
 
 // NodeManagerStatus defines the observed state of NodeManager
@@ -39,9 +57,15 @@ type NodeManagerStatus struct {
 	WorkerAWSNodeManager *AWSNodeManagerRef `json:"workerAWSNodeManager,omitempty"`
 	MasterNodes          []string           `json:"masterNodes,omitempty"`
 	WorkerNodes          []string           `json:"workerNodes,omitempty"`
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // NodeManager is the Schema for the nodemanagers API
 type NodeManager struct {
@@ -69,6 +93,18 @@ type CloudAWS struct {
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:Type:=string
 	Region string `json:"region"`
+	// RoleARN is an IAM role which is assumed to manage AutoScalingGroups instead of credentials of the controller.
+	// +optional
+	// +kubebuilder:validation:Type:=string
+	RoleARN string `json:"roleARN,omitempty"`
+	// ExternalID is passed to AssumeRole when the trust policy of the role requires it.
+	// +optional
+	// +kubebuilder:validation:Type:=string
+	ExternalID string `json:"externalID,omitempty"`
+	// Endpoint overrides the endpoint URL of EC2, AutoScaling and STS, for example LocalStack.
+	// +optional
+	// +kubebuilder:validation:Type:=string
+	Endpoint string `json:"endpoint,omitempty"`
 	// +nullable
 	Masters *Nodes `json:"masters,omitempty"`
 	// +nullable
@@ -76,18 +112,47 @@ type CloudAWS struct {
 }
 
 type Nodes struct {
-	// +kubebuilder:validation:Required
-	AutoScalingGroups []AutoScalingGroup `json:"autoScalingGroups"`
+	// +optional
+	AutoScalingGroups []AutoScalingGroup `json:"autoScalingGroups,omitempty"`
+	// TagSelector discovers AutoScalingGroups which have all of the tags in addition to AutoScalingGroups.
+	// A tag with an empty value matches any value, for example k8s.io/cluster-autoscaler/enabled and kubernetes.io/cluster/<name>.
+	// +optional
+	TagSelector map[string]string `json:"tagSelector,omitempty"`
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:Type:=integer
 	Desired int32 `json:"desired"`
 	// +kubebuilder:validation:Required
-	// +kubebuilder:validation:Type:=integer
-	ASGModifyCoolTimeSeconds int64 `json:"asgModifyCoolTimeSeconds"`
-	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:Type:=boolean
 	// +kubebuilder:default=true
 	EnableReplenish bool `json:"enableReplenish"`
+	RefreshOptions  `json:",inline"`
+	// RefreshStrategy is how to replace nodes on refreshSchedule.
+	// rolling replaces nodes one by one in the controller, and instanceRefresh uses Instance Refresh of EC2 Auto Scaling.
+	// +optional
+	RefreshStrategy RefreshStrategy `json:"refreshStrategy,omitempty"`
+	// InstanceRefresh is used when refreshStrategy is instanceRefresh.
+	// +optional
+	// +nullable
+	InstanceRefresh *InstanceRefreshPreferences `json:"instanceRefresh,omitempty"`
+	// RefreshTrigger is what starts a refresh. schedule replaces all nodes on refreshSchedule,
+	// and drift replaces only nodes which are out of date against the launch template or launch configuration of the AutoScalingGroups.
+	// refreshSchedule is not required with drift.
+	// +optional
+	RefreshTrigger RefreshTrigger `json:"refreshTrigger,omitempty"`
+	// AMISource starts a refresh when the AMI which is published to it changes.
+	// refreshSchedule is optional with it, and works as a maintenance window when it is specified.
+	// +optional
+	// +nullable
+	AMISource *AMISource `json:"amiSource,omitempty"`
+}
+
+// RefreshOptions are options of node groups and their refreshes which are common to all cloud providers.
+type RefreshOptions struct {
+	// ASGModifyCoolTimeSeconds is how long the controller waits after it modifies node groups.
+	// It is named after AutoScalingGroups, but it applies to node groups of all cloud providers.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Type:=integer
+	ASGModifyCoolTimeSeconds int64 `json:"asgModifyCoolTimeSeconds"`
 	// +nullable
 	// +kubebuilder:validation:Type:=string
 	RefreshSchedule string `json:"refreshSchedule"`
@@ -98,6 +163,142 @@ type Nodes struct {
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:Type=integer
 	DrainGracePeriodSeconds int64 `json:"drainGracePeriodSeconds"`
+	// MaxNodeAge replaces nodes continuously, oldest first, when they are older than it, for example 168h.
+	// refreshSchedule is optional with it, and works as a maintenance window when it is specified.
+	// +optional
+	// +nullable
+	MaxNodeAge *metav1.Duration `json:"maxNodeAge,omitempty"`
+	// MaintenanceWindow limits when nodes are replaced, and a refresh is paused between replacements while it is closed.
+	// It is used instead of refreshSchedule.
+	// +optional
+	// +nullable
+	MaintenanceWindow *MaintenanceWindow `json:"maintenanceWindow,omitempty"`
+	// SuspendRefresh stops starting refreshes, and pauses a running refresh between replacements.
+	// It takes effect at the next batch boundary, so nodes in the current batch are still drained and replaced.
+	// +optional
+	SuspendRefresh bool `json:"suspendRefresh,omitempty"`
+	// RefreshNow starts a refresh immediately when a new value, for example the current timestamp, is set.
+	// +optional
+	RefreshNow string `json:"refreshNow,omitempty"`
+	// RefreshTimeouts are deadlines of phases in a refresh. The refresh fails and removes the surplus nodes when a phase does not finish in its deadline.
+	// A failed refresh is retried on the next schedule or trigger after the surplus nodes are removed.
+	// +optional
+	// +nullable
+	RefreshTimeouts *PhaseTimeouts `json:"refreshTimeouts,omitempty"`
+	// MaxSurge is how many nodes are added over desired during a refresh, as a number or a percentage of desired, for example 25%.
+	// It is used instead of surplusNodes. Nodes are replaced one by one unless maxSurge or maxUnavailable is specified.
+	// +optional
+	// +nullable
+	// +kubebuilder:validation:XIntOrString
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+	// MaxUnavailable is how many nodes can be unavailable below desired during a refresh, as a number or a percentage of desired.
+	// +optional
+	// +nullable
+	// +kubebuilder:validation:XIntOrString
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+	// ReplacementOrder is how nodes to replace are chosen in a refresh. oldestFirst replaces the oldest nodes first,
+	// azBalanced replaces nodes in the availability zone which has the most nodes first, leastPods replaces nodes which run the fewest pods first,
+	// and instanceTypeMismatch replaces nodes whose instance type differs from the launch template first. Ties are broken by the oldest node.
+	// instanceTypeMismatch is supported only on AWS.
+	// +optional
+	ReplacementOrder ReplacementOrder `json:"replacementOrder,omitempty"`
+}
+
+type CloudGCP struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Type:=string
+	Project string `json:"project"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Type:=string
+	Zone string `json:"zone"`
+	// +nullable
+	Masters *GCPNodes `json:"masters,omitempty"`
+	// +nullable
+	Workers *GCPNodes `json:"workers,omitempty"`
+}
+
+type GCPNodes struct {
+	// +kubebuilder:validation:Required
+	InstanceGroups []InstanceGroup `json:"instanceGroups"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Type:=integer
+	Desired int32 `json:"desired"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Type:=boolean
+	// +kubebuilder:default=true
+	EnableReplenish bool `json:"enableReplenish"`
+	RefreshOptions  `json:",inline"`
+}
+
+// InstanceGroup is a zonal Managed Instance Group in GCP.
+type InstanceGroup struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Type:=string
+	Name string `json:"name"`
+}
+
+type CloudAzure struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Type:=string
+	SubscriptionID string `json:"subscriptionID"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Type:=string
+	ResourceGroup string `json:"resourceGroup"`
+	// +nullable
+	Masters *AzureNodes `json:"masters,omitempty"`
+	// +nullable
+	Workers *AzureNodes `json:"workers,omitempty"`
+}
+
+type AzureNodes struct {
+	// +kubebuilder:validation:Required
+	ScaleSets []ScaleSet `json:"scaleSets"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Type:=integer
+	Desired int32 `json:"desired"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Type:=boolean
+	// +kubebuilder:default=true
+	EnableReplenish bool `json:"enableReplenish"`
+	RefreshOptions  `json:",inline"`
+}
+
+// ScaleSet is a Virtual Machine Scale Set in Azure.
+type ScaleSet struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Type:=string
+	Name string `json:"name"`
+}
+
+// CloudClusterAPI specifies MachineDeployments of Cluster API in the cluster where the controller runs.
+// Control plane machines are owned by a control plane provider instead of MachineDeployments, so only workers are supported.
+type CloudClusterAPI struct {
+	// Namespace of MachineDeployments and Machines
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Type:=string
+	Namespace string `json:"namespace"`
+	// +nullable
+	Workers *ClusterAPINodes `json:"workers,omitempty"`
+}
+
+type ClusterAPINodes struct {
+	// +kubebuilder:validation:Required
+	MachineDeployments []MachineDeployment `json:"machineDeployments"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Type:=integer
+	Desired int32 `json:"desired"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Type:=boolean
+	// +kubebuilder:default=true
+	EnableReplenish bool `json:"enableReplenish"`
+	RefreshOptions  `json:",inline"`
+}
+
+// MachineDeployment is a MachineDeployment in Cluster API.
+type MachineDeployment struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Type:=string
+	Name string `json:"name"`
 }
 
 type AutoScalingGroup struct {
@@ -21,9 +21,41 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AMISource) DeepCopyInto(out *AMISource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AMISource.
+func (in *AMISource) DeepCopy() *AMISource {
+	if in == nil {
+		return nil
+	}
+	out := new(AMISource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AMISourceStatus) DeepCopyInto(out *AMISourceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AMISourceStatus.
+func (in *AMISourceStatus) DeepCopy() *AMISourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AMISourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AWSNode) DeepCopyInto(out *AWSNode) {
 	*out = *in
@@ -117,11 +149,54 @@ func (in *AWSNodeManagerRef) DeepCopy() *AWSNodeManagerRef {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AWSNodeManagerSpec) DeepCopyInto(out *AWSNodeManagerSpec) {
 	*out = *in
+	in.CloudTarget.DeepCopyInto(&out.CloudTarget)
 	if in.AutoScalingGroups != nil {
 		in, out := &in.AutoScalingGroups, &out.AutoScalingGroups
 		*out = make([]AutoScalingGroup, len(*in))
 		copy(*out, *in)
 	}
+	if in.TagSelector != nil {
+		in, out := &in.TagSelector, &out.TagSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.InstanceRefresh != nil {
+		in, out := &in.InstanceRefresh, &out.InstanceRefresh
+		*out = new(InstanceRefreshPreferences)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AMISource != nil {
+		in, out := &in.AMISource, &out.AMISource
+		*out = new(AMISource)
+		**out = **in
+	}
+	if in.MaxNodeAge != nil {
+		in, out := &in.MaxNodeAge, &out.MaxNodeAge
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindow)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RefreshTimeouts != nil {
+		in, out := &in.RefreshTimeouts, &out.RefreshTimeouts
+		*out = new(PhaseTimeouts)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxSurge != nil {
+		in, out := &in.MaxSurge, &out.MaxSurge
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSNodeManagerSpec.
@@ -161,10 +236,22 @@ func (in *AWSNodeManagerStatus) DeepCopyInto(out *AWSNodeManagerStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.AutoScalingGroups != nil {
+		in, out := &in.AutoScalingGroups, &out.AutoScalingGroups
+		*out = make([]AutoScalingGroup, len(*in))
+		copy(*out, *in)
+	}
 	if in.LastASGModifiedTime != nil {
 		in, out := &in.LastASGModifiedTime, &out.LastASGModifiedTime
 		*out = (*in).DeepCopy()
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSNodeManagerStatus.
@@ -254,11 +341,47 @@ func (in *AWSNodeRefresherRef) DeepCopy() *AWSNodeRefresherRef {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AWSNodeRefresherSpec) DeepCopyInto(out *AWSNodeRefresherSpec) {
 	*out = *in
+	in.CloudTarget.DeepCopyInto(&out.CloudTarget)
 	if in.AutoScalingGroups != nil {
 		in, out := &in.AutoScalingGroups, &out.AutoScalingGroups
 		*out = make([]AutoScalingGroup, len(*in))
 		copy(*out, *in)
 	}
+	if in.InstanceRefresh != nil {
+		in, out := &in.InstanceRefresh, &out.InstanceRefresh
+		*out = new(InstanceRefreshPreferences)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AMISource != nil {
+		in, out := &in.AMISource, &out.AMISource
+		*out = new(AMISource)
+		**out = **in
+	}
+	if in.MaxNodeAge != nil {
+		in, out := &in.MaxNodeAge, &out.MaxNodeAge
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindow)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Timeouts != nil {
+		in, out := &in.Timeouts, &out.Timeouts
+		*out = new(PhaseTimeouts)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxSurge != nil {
+		in, out := &in.MaxSurge, &out.MaxSurge
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSNodeRefresherSpec.
@@ -298,6 +421,51 @@ func (in *AWSNodeRefresherStatus) DeepCopyInto(out *AWSNodeRefresherStatus) {
 		*out = new(AWSNode)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ReplaceTargetNodes != nil {
+		in, out := &in.ReplaceTargetNodes, &out.ReplaceTargetNodes
+		*out = make([]AWSNode, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BlockingPodDisruptionBudgets != nil {
+		in, out := &in.BlockingPodDisruptionBudgets, &out.BlockingPodDisruptionBudgets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.InstanceRefreshes != nil {
+		in, out := &in.InstanceRefreshes, &out.InstanceRefreshes
+		*out = make([]InstanceRefreshStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.DrainingNodes != nil {
+		in, out := &in.DrainingNodes, &out.DrainingNodes
+		*out = make([]DrainingNode, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DriftedNodes != nil {
+		in, out := &in.DriftedNodes, &out.DriftedNodes
+		*out = make([]DriftedNode, len(*in))
+		copy(*out, *in)
+	}
+	if in.AMISource != nil {
+		in, out := &in.AMISource, &out.AMISource
+		*out = new(AMISourceStatus)
+		**out = **in
+	}
+	if in.PhaseStartTime != nil {
+		in, out := &in.PhaseStartTime, &out.PhaseStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSNodeRefresherStatus.
@@ -387,6 +555,7 @@ func (in *AWSNodeReplenisherRef) DeepCopy() *AWSNodeReplenisherRef {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AWSNodeReplenisherSpec) DeepCopyInto(out *AWSNodeReplenisherSpec) {
 	*out = *in
+	in.CloudTarget.DeepCopyInto(&out.CloudTarget)
 	if in.AutoScalingGroups != nil {
 		in, out := &in.AutoScalingGroups, &out.AutoScalingGroups
 		*out = make([]AutoScalingGroup, len(*in))
@@ -425,6 +594,13 @@ func (in *AWSNodeReplenisherStatus) DeepCopyInto(out *AWSNodeReplenisherStatus)
 		in, out := &in.LastASGModifiedTime, &out.LastASGModifiedTime
 		*out = (*in).DeepCopy()
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSNodeReplenisherStatus.
@@ -437,6 +613,21 @@ func (in *AWSNodeReplenisherStatus) DeepCopy() *AWSNodeReplenisherStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSTarget) DeepCopyInto(out *AWSTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSTarget.
+func (in *AWSTarget) DeepCopy() *AWSTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AutoScalingGroup) DeepCopyInto(out *AutoScalingGroup) {
 	*out = *in
@@ -452,6 +643,59 @@ func (in *AutoScalingGroup) DeepCopy() *AutoScalingGroup {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureNodes) DeepCopyInto(out *AzureNodes) {
+	*out = *in
+	if in.ScaleSets != nil {
+		in, out := &in.ScaleSets, &out.ScaleSets
+		*out = make([]ScaleSet, len(*in))
+		copy(*out, *in)
+	}
+	in.RefreshOptions.DeepCopyInto(&out.RefreshOptions)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureNodes.
+func (in *AzureNodes) DeepCopy() *AzureNodes {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureNodes)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureTarget) DeepCopyInto(out *AzureTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureTarget.
+func (in *AzureTarget) DeepCopy() *AzureTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlackoutPeriod) DeepCopyInto(out *BlackoutPeriod) {
+	*out = *in
+	in.Start.DeepCopyInto(&out.Start)
+	in.End.DeepCopyInto(&out.End)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlackoutPeriod.
+func (in *BlackoutPeriod) DeepCopy() *BlackoutPeriod {
+	if in == nil {
+		return nil
+	}
+	out := new(BlackoutPeriod)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CloudAWS) DeepCopyInto(out *CloudAWS) {
 	*out = *in
@@ -477,6 +721,318 @@ func (in *CloudAWS) DeepCopy() *CloudAWS {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudAzure) DeepCopyInto(out *CloudAzure) {
+	*out = *in
+	if in.Masters != nil {
+		in, out := &in.Masters, &out.Masters
+		*out = new(AzureNodes)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Workers != nil {
+		in, out := &in.Workers, &out.Workers
+		*out = new(AzureNodes)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudAzure.
+func (in *CloudAzure) DeepCopy() *CloudAzure {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudAzure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudClusterAPI) DeepCopyInto(out *CloudClusterAPI) {
+	*out = *in
+	if in.Workers != nil {
+		in, out := &in.Workers, &out.Workers
+		*out = new(ClusterAPINodes)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudClusterAPI.
+func (in *CloudClusterAPI) DeepCopy() *CloudClusterAPI {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudClusterAPI)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudGCP) DeepCopyInto(out *CloudGCP) {
+	*out = *in
+	if in.Masters != nil {
+		in, out := &in.Masters, &out.Masters
+		*out = new(GCPNodes)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Workers != nil {
+		in, out := &in.Workers, &out.Workers
+		*out = new(GCPNodes)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudGCP.
+func (in *CloudGCP) DeepCopy() *CloudGCP {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudGCP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudTarget) DeepCopyInto(out *CloudTarget) {
+	*out = *in
+	if in.AWS != nil {
+		in, out := &in.AWS, &out.AWS
+		*out = new(AWSTarget)
+		**out = **in
+	}
+	if in.GCP != nil {
+		in, out := &in.GCP, &out.GCP
+		*out = new(GCPTarget)
+		**out = **in
+	}
+	if in.Azure != nil {
+		in, out := &in.Azure, &out.Azure
+		*out = new(AzureTarget)
+		**out = **in
+	}
+	if in.ClusterAPI != nil {
+		in, out := &in.ClusterAPI, &out.ClusterAPI
+		*out = new(ClusterAPITarget)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudTarget.
+func (in *CloudTarget) DeepCopy() *CloudTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterAPINodes) DeepCopyInto(out *ClusterAPINodes) {
+	*out = *in
+	if in.MachineDeployments != nil {
+		in, out := &in.MachineDeployments, &out.MachineDeployments
+		*out = make([]MachineDeployment, len(*in))
+		copy(*out, *in)
+	}
+	in.RefreshOptions.DeepCopyInto(&out.RefreshOptions)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAPINodes.
+func (in *ClusterAPINodes) DeepCopy() *ClusterAPINodes {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterAPINodes)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterAPITarget) DeepCopyInto(out *ClusterAPITarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAPITarget.
+func (in *ClusterAPITarget) DeepCopy() *ClusterAPITarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterAPITarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrainingNode) DeepCopyInto(out *DrainingNode) {
+	*out = *in
+	in.AWSNode.DeepCopyInto(&out.AWSNode)
+	in.DrainStartTime.DeepCopyInto(&out.DrainStartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrainingNode.
+func (in *DrainingNode) DeepCopy() *DrainingNode {
+	if in == nil {
+		return nil
+	}
+	out := new(DrainingNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftedNode) DeepCopyInto(out *DriftedNode) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftedNode.
+func (in *DriftedNode) DeepCopy() *DriftedNode {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftedNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GCPNodes) DeepCopyInto(out *GCPNodes) {
+	*out = *in
+	if in.InstanceGroups != nil {
+		in, out := &in.InstanceGroups, &out.InstanceGroups
+		*out = make([]InstanceGroup, len(*in))
+		copy(*out, *in)
+	}
+	in.RefreshOptions.DeepCopyInto(&out.RefreshOptions)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCPNodes.
+func (in *GCPNodes) DeepCopy() *GCPNodes {
+	if in == nil {
+		return nil
+	}
+	out := new(GCPNodes)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GCPTarget) DeepCopyInto(out *GCPTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCPTarget.
+func (in *GCPTarget) DeepCopy() *GCPTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(GCPTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceGroup) DeepCopyInto(out *InstanceGroup) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceGroup.
+func (in *InstanceGroup) DeepCopy() *InstanceGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceRefreshPreferences) DeepCopyInto(out *InstanceRefreshPreferences) {
+	*out = *in
+	if in.MinHealthyPercentage != nil {
+		in, out := &in.MinHealthyPercentage, &out.MinHealthyPercentage
+		*out = new(int64)
+		**out = **in
+	}
+	if in.InstanceWarmupSeconds != nil {
+		in, out := &in.InstanceWarmupSeconds, &out.InstanceWarmupSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.CheckpointPercentages != nil {
+		in, out := &in.CheckpointPercentages, &out.CheckpointPercentages
+		*out = make([]int64, len(*in))
+		copy(*out, *in)
+	}
+	if in.CheckpointDelaySeconds != nil {
+		in, out := &in.CheckpointDelaySeconds, &out.CheckpointDelaySeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceRefreshPreferences.
+func (in *InstanceRefreshPreferences) DeepCopy() *InstanceRefreshPreferences {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceRefreshPreferences)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceRefreshStatus) DeepCopyInto(out *InstanceRefreshStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceRefreshStatus.
+func (in *InstanceRefreshStatus) DeepCopy() *InstanceRefreshStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceRefreshStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineDeployment) DeepCopyInto(out *MachineDeployment) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineDeployment.
+func (in *MachineDeployment) DeepCopy() *MachineDeployment {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineDeployment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+	if in.Blackouts != nil {
+		in, out := &in.Blackouts, &out.Blackouts
+		*out = make([]BlackoutPeriod, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeManager) DeepCopyInto(out *NodeManager) {
 	*out = *in
@@ -544,6 +1100,21 @@ func (in *NodeManagerSpec) DeepCopyInto(out *NodeManagerSpec) {
 		*out = new(CloudAWS)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GCP != nil {
+		in, out := &in.GCP, &out.GCP
+		*out = new(CloudGCP)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Azure != nil {
+		in, out := &in.Azure, &out.Azure
+		*out = new(CloudAzure)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClusterAPI != nil {
+		in, out := &in.ClusterAPI, &out.ClusterAPI
+		*out = new(CloudClusterAPI)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeManagerSpec.
@@ -579,6 +1150,13 @@ func (in *NodeManagerStatus) DeepCopyInto(out *NodeManagerStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeManagerStatus.
@@ -599,6 +1177,24 @@ func (in *Nodes) DeepCopyInto(out *Nodes) {
 		*out = make([]AutoScalingGroup, len(*in))
 		copy(*out, *in)
 	}
+	if in.TagSelector != nil {
+		in, out := &in.TagSelector, &out.TagSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.RefreshOptions.DeepCopyInto(&out.RefreshOptions)
+	if in.InstanceRefresh != nil {
+		in, out := &in.InstanceRefresh, &out.InstanceRefresh
+		*out = new(InstanceRefreshPreferences)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AMISource != nil {
+		in, out := &in.AMISource, &out.AMISource
+		*out = new(AMISource)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Nodes.
@@ -610,3 +1206,93 @@ func (in *Nodes) DeepCopy() *Nodes {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PhaseTimeouts) DeepCopyInto(out *PhaseTimeouts) {
+	*out = *in
+	if in.Increase != nil {
+		in, out := &in.Increase, &out.Increase
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Replace != nil {
+		in, out := &in.Replace, &out.Replace
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.AWSWait != nil {
+		in, out := &in.AWSWait, &out.AWSWait
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Decrease != nil {
+		in, out := &in.Decrease, &out.Decrease
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PhaseTimeouts.
+func (in *PhaseTimeouts) DeepCopy() *PhaseTimeouts {
+	if in == nil {
+		return nil
+	}
+	out := new(PhaseTimeouts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RefreshOptions) DeepCopyInto(out *RefreshOptions) {
+	*out = *in
+	if in.MaxNodeAge != nil {
+		in, out := &in.MaxNodeAge, &out.MaxNodeAge
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindow)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RefreshTimeouts != nil {
+		in, out := &in.RefreshTimeouts, &out.RefreshTimeouts
+		*out = new(PhaseTimeouts)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxSurge != nil {
+		in, out := &in.MaxSurge, &out.MaxSurge
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RefreshOptions.
+func (in *RefreshOptions) DeepCopy() *RefreshOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(RefreshOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaleSet) DeepCopyInto(out *ScaleSet) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleSet.
+func (in *ScaleSet) DeepCopy() *ScaleSet {
+	if in == nil {
+		return nil
+	}
+	out := new(ScaleSet)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -31,10 +31,16 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+	"github.com/h3poteto/node-manager/pkg/cloud"
+	cloudaws "github.com/h3poteto/node-manager/pkg/cloud/aws"
+	cloudazure "github.com/h3poteto/node-manager/pkg/cloud/azure"
+	cloudclusterapi "github.com/h3poteto/node-manager/pkg/cloud/clusterapi"
+	cloudgcp "github.com/h3poteto/node-manager/pkg/cloud/gcp"
 	"github.com/h3poteto/node-manager/pkg/controllers/awsnodemanager"
 	"github.com/h3poteto/node-manager/pkg/controllers/awsnoderefresher"
 	"github.com/h3poteto/node-manager/pkg/controllers/awsnodereplenisher"
 	"github.com/h3poteto/node-manager/pkg/controllers/nodemanager"
+	"github.com/h3poteto/node-manager/pkg/webhooks"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -53,10 +59,18 @@ func init() {
 func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
+	var enableWebhook bool
+	var awsEndpoint string
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.BoolVar(&enableWebhook, "enable-webhook", false,
+		"Enable validating admission webhooks for custom resources. "+
+			"Enabling this requires TLS certificates for the webhook server.")
+	flag.StringVar(&awsEndpoint, "aws-endpoint", "",
+		"The endpoint URL of EC2, AutoScaling and STS, for example LocalStack. "+
+			"The endpoint in NodeManager takes precedence over it.")
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
@@ -81,6 +95,12 @@ func main() {
 		os.Exit(1)
 	}
 
+	providers := cloud.NewRegistry()
+	providers.Register(operatorv1alpha1.CloudProviderAWS, cloudaws.NewClientCache(awsEndpoint).NewProvider)
+	providers.Register(operatorv1alpha1.CloudProviderGCP, cloudgcp.NewClientCache().NewProvider)
+	providers.Register(operatorv1alpha1.CloudProviderAzure, cloudazure.NewClientCache().NewProvider)
+	providers.Register(operatorv1alpha1.CloudProviderClusterAPI, cloudclusterapi.NewFactory(mgr.GetClient()))
+
 	if err = (&nodemanager.NodeManagerReconciler{
 		Client:   mgr.GetClient(),
 		Log:      ctrl.Log.WithName("controllers").WithName("NodeManager"),
@@ -91,32 +111,53 @@ func main() {
 		os.Exit(1)
 	}
 	if err = (&awsnodemanager.AWSNodeManagerReconciler{
-		Client:   mgr.GetClient(),
-		Log:      ctrl.Log.WithName("controllers").WithName("AWSNodeManager"),
-		Recorder: mgr.GetEventRecorderFor("aws-node-manager"),
-		Scheme:   mgr.GetScheme(),
+		Client:    mgr.GetClient(),
+		Log:       ctrl.Log.WithName("controllers").WithName("AWSNodeManager"),
+		Recorder:  mgr.GetEventRecorderFor("aws-node-manager"),
+		Scheme:    mgr.GetScheme(),
+		Providers: providers,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AWSNodeManager")
 		os.Exit(1)
 	}
 	if err = (&awsnodereplenisher.AWSNodeReplenisherReconciler{
-		Client:   mgr.GetClient(),
-		Log:      ctrl.Log.WithName("controllers").WithName("AWSNodeReplenisher"),
-		Recorder: mgr.GetEventRecorderFor("aws-node-replenisher"),
-		Scheme:   mgr.GetScheme(),
+		Client:    mgr.GetClient(),
+		Log:       ctrl.Log.WithName("controllers").WithName("AWSNodeReplenisher"),
+		Recorder:  mgr.GetEventRecorderFor("aws-node-replenisher"),
+		Scheme:    mgr.GetScheme(),
+		Providers: providers,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AWSNodeReplenisher")
 		os.Exit(1)
 	}
 	if err = (&awsnoderefresher.AWSNodeRefresherReconciler{
-		Client:   mgr.GetClient(),
-		Log:      ctrl.Log.WithName("controllers").WithName("AWSNodeRefresher"),
-		Recorder: mgr.GetEventRecorderFor("aws-node-refresher"),
-		Scheme:   mgr.GetScheme(),
+		Client:    mgr.GetClient(),
+		Log:       ctrl.Log.WithName("controllers").WithName("AWSNodeRefresher"),
+		Recorder:  mgr.GetEventRecorderFor("aws-node-refresher"),
+		Scheme:    mgr.GetScheme(),
+		Providers: providers,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AWSNodeRefresher")
 		os.Exit(1)
 	}
+	if enableWebhook {
+		if err = webhooks.SetupNodeManagerWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "NodeManager")
+			os.Exit(1)
+		}
+		if err = webhooks.SetupAWSNodeManagerWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "AWSNodeManager")
+			os.Exit(1)
+		}
+		if err = webhooks.SetupAWSNodeReplenisherWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "AWSNodeReplenisher")
+			os.Exit(1)
+		}
+		if err = webhooks.SetupAWSNodeRefresherWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "AWSNodeRefresher")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	setupLog.Info("starting manager")
@@ -11,20 +11,19 @@ import (
 	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
 )
 
+const (
+	// maxAutoScalingGroupNamesPerRequest is the maximum number of names in a DescribeAutoScalingGroups request.
+	maxAutoScalingGroupNamesPerRequest = 100
+	// maxAutoScalingInstancesPerRequest is the maximum number of instance IDs in a DescribeAutoScalingInstances request.
+	maxAutoScalingInstancesPerRequest = 50
+)
+
 func (a *AWS) AddInstancesToAutoScalingGroups(groups []operatorv1alpha1.AutoScalingGroup, totalDesired int, currentNodesCount int) error {
 	if totalDesired <= currentNodesCount {
 		return NewDesiredInvalidErrorf("desired does not exceed current, totalDesired: %d, currentNodesCount: %d", totalDesired, currentNodesCount)
 	}
-	var asgNameList []*string
-	for i := range groups {
-		asgNameList = append(asgNameList, aws.String(groups[i].Name))
-	}
-	input := &autoscaling.DescribeAutoScalingGroupsInput{
-		AutoScalingGroupNames: asgNameList,
-	}
-	output, err := a.Autoscaling.DescribeAutoScalingGroups(input)
+	asgs, err := a.DescribeAutoScalingGroups(groups)
 	if err != nil {
-		klog.Errorf("failed to describe AutoScalingGroups: %v", err)
 		return err
 	}
 
@@ -32,7 +31,7 @@ func (a *AWS) AddInstancesToAutoScalingGroups(groups []operatorv1alpha1.AutoScal
 	sumASGInstances := 0
 	// safetyASGs have same value desired capacity and current instances count.
 	var safetyASGs []*autoscaling.Group
-	for _, asg := range output.AutoScalingGroups {
+	for _, asg := range asgs {
 		sumASGDesired += int(*asg.DesiredCapacity)
 		sumASGInstances += len(asg.Instances)
 		if int(*asg.DesiredCapacity) == len(asg.Instances) {
@@ -89,16 +88,8 @@ func (a *AWS) DeleteInstancesToAutoScalingGroups(groups []operatorv1alpha1.AutoS
 	}
 
 	// Check desired capacity of each AutScalingGroups
-	var asgNameList []*string
-	for i := range groups {
-		asgNameList = append(asgNameList, aws.String(groups[i].Name))
-	}
-	input := &autoscaling.DescribeAutoScalingGroupsInput{
-		AutoScalingGroupNames: asgNameList,
-	}
-	output, err := a.Autoscaling.DescribeAutoScalingGroups(input)
+	asgs, err := a.DescribeAutoScalingGroups(groups)
 	if err != nil {
-		klog.Errorf("failed to describe AutoScalingGroups: %v", err)
 		return err
 	}
 
@@ -107,7 +98,7 @@ func (a *AWS) DeleteInstancesToAutoScalingGroups(groups []operatorv1alpha1.AutoS
 	var safetyASGs []*autoscaling.Group
 	// unsafetyASGs have different value desired capacity and current instances count.
 	var unsafetyASGs []*autoscaling.Group
-	for _, asg := range output.AutoScalingGroups {
+	for _, asg := range asgs {
 		sumASGDesired += int(*asg.DesiredCapacity)
 		sumASGInstances += len(asg.Instances)
 		if int(*asg.DesiredCapacity) != len(asg.Instances) {
@@ -219,23 +210,43 @@ func allASGIsMinimized(asgs []*autoscaling.Group) bool {
 	return true
 }
 
+// DescribeAutoScalingGroups describes all of the AutoScalingGroups.
+// Names are split into chunks and every page of the results is read, so the results are not truncated even if there are many groups.
 func (a *AWS) DescribeAutoScalingGroups(groups []operatorv1alpha1.AutoScalingGroup) ([]*autoscaling.Group, error) {
-	var asgNames []*string
+	var asgNames []string
 	for _, asg := range groups {
-		asgNames = append(asgNames, aws.String(asg.Name))
+		asgNames = append(asgNames, asg.Name)
 	}
 
-	input := &autoscaling.DescribeAutoScalingGroupsInput{
-		AutoScalingGroupNames: asgNames,
-		MaxRecords:            nil,
-		NextToken:             nil,
+	var asgs []*autoscaling.Group
+	for _, names := range chunk(asgNames, maxAutoScalingGroupNamesPerRequest) {
+		input := &autoscaling.DescribeAutoScalingGroupsInput{
+			AutoScalingGroupNames: aws.StringSlice(names),
+		}
+		output, err := a.describeAllAutoScalingGroups(input)
+		if err != nil {
+			return nil, err
+		}
+		asgs = append(asgs, output...)
 	}
-	output, err := a.Autoscaling.DescribeAutoScalingGroups(input)
-	if err != nil {
-		klog.Errorf("failed to describe autoscaling group: %v", err)
-		return nil, err
+	return asgs, nil
+}
+
+// describeAllAutoScalingGroups follows NextToken until the last page.
+func (a *AWS) describeAllAutoScalingGroups(input *autoscaling.DescribeAutoScalingGroupsInput) ([]*autoscaling.Group, error) {
+	var asgs []*autoscaling.Group
+	for {
+		output, err := a.Autoscaling.DescribeAutoScalingGroups(input)
+		if err != nil {
+			klog.Errorf("failed to describe autoscaling group: %v", err)
+			return nil, err
+		}
+		asgs = append(asgs, output.AutoScalingGroups...)
+		if aws.StringValue(output.NextToken) == "" {
+			return asgs, nil
+		}
+		input.NextToken = output.NextToken
 	}
-	return output.AutoScalingGroups, nil
 }
 
 func (a *AWS) DetachInstanceFromASG(instanceID string, asgName string) error {
@@ -253,3 +264,91 @@ func (a *AWS) DetachInstanceFromASG(instanceID string, asgName string) error {
 	}
 	return nil
 }
+
+// DescribeAutoScalingGroupNames returns names of AutoScalingGroups which the instances belong to, keyed by instance IDs.
+// Instances which do not belong to any AutoScalingGroup are not included.
+func (a *AWS) DescribeAutoScalingGroupNames(instanceIDs []string) (map[string]string, error) {
+	groups := map[string]string{}
+	for _, ids := range chunk(instanceIDs, maxAutoScalingInstancesPerRequest) {
+		input := &autoscaling.DescribeAutoScalingInstancesInput{
+			InstanceIds: aws.StringSlice(ids),
+		}
+		for {
+			output, err := a.Autoscaling.DescribeAutoScalingInstances(input)
+			if err != nil {
+				klog.Errorf("failed to describe autoscaling instances: %v", err)
+				return nil, err
+			}
+			for _, instance := range output.AutoScalingInstances {
+				groups[aws.StringValue(instance.InstanceId)] = aws.StringValue(instance.AutoScalingGroupName)
+			}
+			if aws.StringValue(output.NextToken) == "" {
+				break
+			}
+			input.NextToken = output.NextToken
+		}
+	}
+	return groups, nil
+}
+
+// DiscoverAutoScalingGroups returns AutoScalingGroups which have all of the tags, sorted by name.
+// A tag with an empty value matches any value of the tag.
+func (a *AWS) DiscoverAutoScalingGroups(tags map[string]string) ([]operatorv1alpha1.AutoScalingGroup, error) {
+	var keys []string
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var filters []*autoscaling.Filter
+	var anyValueKeys []*string
+	for _, key := range keys {
+		if tags[key] == "" {
+			anyValueKeys = append(anyValueKeys, aws.String(key))
+			continue
+		}
+		filters = append(filters, &autoscaling.Filter{
+			Name:   aws.String("tag:" + key),
+			Values: []*string{aws.String(tags[key])},
+		})
+	}
+	// Values of a tag-key filter are ORed, so groups are filtered again by all keys below.
+	if len(anyValueKeys) > 0 {
+		filters = append(filters, &autoscaling.Filter{
+			Name:   aws.String("tag-key"),
+			Values: anyValueKeys,
+		})
+	}
+
+	input := &autoscaling.DescribeAutoScalingGroupsInput{
+		Filters: filters,
+	}
+	asgs, err := a.describeAllAutoScalingGroups(input)
+	if err != nil {
+		return nil, err
+	}
+	var groups []operatorv1alpha1.AutoScalingGroup
+	for _, asg := range asgs {
+		if hasAllTagKeys(asg.Tags, anyValueKeys) {
+			groups = append(groups, operatorv1alpha1.AutoScalingGroup{Name: aws.StringValue(asg.AutoScalingGroupName)})
+		}
+	}
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups, nil
+}
+
+func hasAllTagKeys(tags []*autoscaling.TagDescription, keys []*string) bool {
+	for _, key := range keys {
+		found := false
+		for _, tag := range tags {
+			if aws.StringValue(tag.Key) == aws.StringValue(key) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -2,7 +2,9 @@ package aws
 
 import (
 	"errors"
+	"fmt"
 	"log"
+	"reflect"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -14,10 +16,30 @@ import (
 type mockedAutoScalingAPI struct {
 	autoscalingiface.AutoScalingAPI
 	Resp              autoscaling.DescribeAutoScalingGroupsOutput
+	InstancesResp     autoscaling.DescribeAutoScalingInstancesOutput
 	RequestASGDesired map[string]int64
+	RequestFilters    []*autoscaling.Filter
+	// Pages are returned instead of Resp when they are set, keyed by NextToken of requests.
+	Pages                    map[string]autoscaling.DescribeAutoScalingGroupsOutput
+	RequestNames             [][]string
+	LaunchConfigurationsResp autoscaling.DescribeLaunchConfigurationsOutput
+}
+
+func (m *mockedAutoScalingAPI) DescribeLaunchConfigurations(in *autoscaling.DescribeLaunchConfigurationsInput) (*autoscaling.DescribeLaunchConfigurationsOutput, error) {
+	return &m.LaunchConfigurationsResp, nil
+}
+
+func (m *mockedAutoScalingAPI) DescribeAutoScalingInstances(in *autoscaling.DescribeAutoScalingInstancesInput) (*autoscaling.DescribeAutoScalingInstancesOutput, error) {
+	return &m.InstancesResp, nil
 }
 
 func (m *mockedAutoScalingAPI) DescribeAutoScalingGroups(in *autoscaling.DescribeAutoScalingGroupsInput) (*autoscaling.DescribeAutoScalingGroupsOutput, error) {
+	m.RequestFilters = in.Filters
+	m.RequestNames = append(m.RequestNames, aws.StringValueSlice(in.AutoScalingGroupNames))
+	if m.Pages != nil {
+		page := m.Pages[aws.StringValue(in.NextToken)]
+		return &page, nil
+	}
 	return &m.Resp, nil
 }
 
@@ -883,3 +905,216 @@ CASE:
 		}
 	}
 }
+
+func TestDiscoverAutoScalingGroups(t *testing.T) {
+	cases := []struct {
+		title           string
+		tags            map[string]string
+		asgs            []*autoscaling.Group
+		expectedFilters []*autoscaling.Filter
+		expectedGroups  []operatorv1alpha1.AutoScalingGroup
+	}{
+		{
+			title: "Tags have values",
+			tags: map[string]string{
+				"kubernetes.io/cluster/test": "owned",
+				"role":                       "worker",
+			},
+			asgs: []*autoscaling.Group{
+				{
+					AutoScalingGroupName: aws.String("asg-b"),
+				},
+				{
+					AutoScalingGroupName: aws.String("asg-a"),
+				},
+			},
+			expectedFilters: []*autoscaling.Filter{
+				{
+					Name:   aws.String("tag:kubernetes.io/cluster/test"),
+					Values: []*string{aws.String("owned")},
+				},
+				{
+					Name:   aws.String("tag:role"),
+					Values: []*string{aws.String("worker")},
+				},
+			},
+			expectedGroups: []operatorv1alpha1.AutoScalingGroup{
+				{
+					Name: "asg-a",
+				},
+				{
+					Name: "asg-b",
+				},
+			},
+		},
+		{
+			title: "Tags do not have values",
+			tags: map[string]string{
+				"k8s.io/cluster-autoscaler/enabled": "",
+				"k8s.io/cluster-autoscaler/test":    "",
+			},
+			asgs: []*autoscaling.Group{
+				{
+					AutoScalingGroupName: aws.String("asg-a"),
+					Tags: []*autoscaling.TagDescription{
+						{
+							Key:   aws.String("k8s.io/cluster-autoscaler/enabled"),
+							Value: aws.String("true"),
+						},
+						{
+							Key:   aws.String("k8s.io/cluster-autoscaler/test"),
+							Value: aws.String("owned"),
+						},
+					},
+				},
+				{
+					AutoScalingGroupName: aws.String("asg-b"),
+					Tags: []*autoscaling.TagDescription{
+						{
+							Key:   aws.String("k8s.io/cluster-autoscaler/enabled"),
+							Value: aws.String("true"),
+						},
+					},
+				},
+			},
+			expectedFilters: []*autoscaling.Filter{
+				{
+					Name:   aws.String("tag-key"),
+					Values: []*string{aws.String("k8s.io/cluster-autoscaler/enabled"), aws.String("k8s.io/cluster-autoscaler/test")},
+				},
+			},
+			expectedGroups: []operatorv1alpha1.AutoScalingGroup{
+				{
+					Name: "asg-a",
+				},
+			},
+		},
+		{
+			title: "No groups match",
+			tags: map[string]string{
+				"role": "worker",
+			},
+			asgs: nil,
+			expectedFilters: []*autoscaling.Filter{
+				{
+					Name:   aws.String("tag:role"),
+					Values: []*string{aws.String("worker")},
+				},
+			},
+			expectedGroups: nil,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		mocked := &mockedAutoScalingAPI{
+			Resp: autoscaling.DescribeAutoScalingGroupsOutput{
+				AutoScalingGroups: c.asgs,
+			},
+		}
+		a := &AWS{
+			Autoscaling: mocked,
+		}
+		groups, err := a.DiscoverAutoScalingGroups(c.tags)
+		if err != nil {
+			t.Errorf("CASE: %s : error has occur: %v", c.title, err)
+			continue
+		}
+		if !reflect.DeepEqual(mocked.RequestFilters, c.expectedFilters) {
+			t.Errorf("CASE: %s : filters are not matched, expected %v, returned %v", c.title, c.expectedFilters, mocked.RequestFilters)
+		}
+		if !reflect.DeepEqual(groups, c.expectedGroups) {
+			t.Errorf("CASE: %s : groups are not matched, expected %v, returned %v", c.title, c.expectedGroups, groups)
+		}
+	}
+}
+
+func TestDescribeAutoScalingGroups(t *testing.T) {
+	var manyGroups []operatorv1alpha1.AutoScalingGroup
+	for i := 0; i < 150; i++ {
+		manyGroups = append(manyGroups, operatorv1alpha1.AutoScalingGroup{Name: fmt.Sprintf("asg-%d", i)})
+	}
+	cases := []struct {
+		title                string
+		groups               []operatorv1alpha1.AutoScalingGroup
+		pages                map[string]autoscaling.DescribeAutoScalingGroupsOutput
+		expectedNames        []string
+		expectedRequestSizes []int
+	}{
+		{
+			title: "Results have multiple pages",
+			groups: []operatorv1alpha1.AutoScalingGroup{
+				{
+					Name: "asg-1",
+				},
+				{
+					Name: "asg-2",
+				},
+			},
+			pages: map[string]autoscaling.DescribeAutoScalingGroupsOutput{
+				"": {
+					AutoScalingGroups: []*autoscaling.Group{
+						{
+							AutoScalingGroupName: aws.String("asg-1"),
+						},
+					},
+					NextToken: aws.String("token-1"),
+				},
+				"token-1": {
+					AutoScalingGroups: []*autoscaling.Group{
+						{
+							AutoScalingGroupName: aws.String("asg-2"),
+						},
+					},
+				},
+			},
+			expectedNames:        []string{"asg-1", "asg-2"},
+			expectedRequestSizes: []int{2, 2},
+		},
+		{
+			title:  "Names are split into chunks",
+			groups: manyGroups,
+			pages: map[string]autoscaling.DescribeAutoScalingGroupsOutput{
+				"": {},
+			},
+			expectedNames:        nil,
+			expectedRequestSizes: []int{100, 50},
+		},
+		{
+			title:                "No groups",
+			groups:               nil,
+			pages:                map[string]autoscaling.DescribeAutoScalingGroupsOutput{},
+			expectedNames:        nil,
+			expectedRequestSizes: nil,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		mocked := &mockedAutoScalingAPI{
+			Pages: c.pages,
+		}
+		a := &AWS{
+			Autoscaling: mocked,
+		}
+		asgs, err := a.DescribeAutoScalingGroups(c.groups)
+		if err != nil {
+			t.Errorf("CASE: %s : error has occur: %v", c.title, err)
+			continue
+		}
+		var names []string
+		for _, asg := range asgs {
+			names = append(names, aws.StringValue(asg.AutoScalingGroupName))
+		}
+		if !reflect.DeepEqual(names, c.expectedNames) {
+			t.Errorf("CASE: %s : names are not matched, expected %v, returned %v", c.title, c.expectedNames, names)
+		}
+		var sizes []int
+		for _, requested := range mocked.RequestNames {
+			sizes = append(sizes, len(requested))
+		}
+		if !reflect.DeepEqual(sizes, c.expectedRequestSizes) {
+			t.Errorf("CASE: %s : requests are not matched, expected %v, returned %v", c.title, c.expectedRequestSizes, sizes)
+		}
+	}
+}
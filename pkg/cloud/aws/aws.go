@@ -2,23 +2,57 @@ package aws
 
 import (
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
+
+	"github.com/h3poteto/node-manager/pkg/cloud"
 )
 
 type AWS struct {
 	EC2         ec2iface.EC2API
 	Autoscaling autoscalingiface.AutoScalingAPI
+	SSM         ssmiface.SSMAPI
 }
 
-func New(sess *session.Session, region string) *AWS {
-	e := ec2.New(sess, aws.NewConfig().WithRegion(region))
-	asg := autoscaling.New(sess, aws.NewConfig().WithRegion(region))
+// New creates clients for the region of the config.
+// When RoleARN is specified, the clients use temporary credentials of the role which are issued by STS with credentials of the session.
+// Endpoint overrides endpoints of all services including STS, so the clients can talk to a local stand-in of AWS.
+func New(sess *session.Session, config cloud.Config) *AWS {
+	c := aws.NewConfig().WithRegion(config.Region)
+	if config.Endpoint != "" {
+		c = c.WithEndpoint(config.Endpoint)
+	}
+	if config.RoleARN != "" {
+		c = c.WithCredentials(stscreds.NewCredentials(sess.Copy(c), config.RoleARN, func(p *stscreds.AssumeRoleProvider) {
+			if config.ExternalID != "" {
+				p.ExternalID = aws.String(config.ExternalID)
+			}
+		}))
+	}
+	e := ec2.New(sess, c)
+	asg := autoscaling.New(sess, c)
 	return &AWS{
 		Autoscaling: asg,
 		EC2:         e,
+		SSM:         ssm.New(sess, c),
+	}
+}
+
+// chunk splits values into slices which have at most size elements, because AWS APIs limit the number of IDs in a request.
+func chunk(values []string, size int) [][]string {
+	var chunks [][]string
+	for size < len(values) {
+		chunks = append(chunks, values[:size])
+		values = values[size:]
+	}
+	if len(values) > 0 {
+		chunks = append(chunks, values)
 	}
+	return chunks
 }
@@ -0,0 +1,53 @@
+package aws
+
+import (
+	"log"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/h3poteto/node-manager/pkg/cloud"
+)
+
+func TestNew(t *testing.T) {
+	cases := []struct {
+		title            string
+		config           cloud.Config
+		expectedEndpoint string
+	}{
+		{
+			title: "Default endpoint",
+			config: cloud.Config{
+				Region: "us-east-1",
+			},
+			expectedEndpoint: "https://ec2.us-east-1.amazonaws.com",
+		},
+		{
+			title: "Custom endpoint",
+			config: cloud.Config{
+				Region:   "us-east-1",
+				Endpoint: "http://localhost:4566",
+			},
+			expectedEndpoint: "http://localhost:4566",
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		sess, err := session.NewSession()
+		if err != nil {
+			t.Fatal(err)
+		}
+		a := New(sess, c.config)
+		if endpoint := a.EC2.(*ec2.EC2).Endpoint; endpoint != c.expectedEndpoint {
+			t.Errorf("CASE: %s : endpoint of EC2 is not matched, expected %s, returned %s", c.title, c.expectedEndpoint, endpoint)
+		}
+		if c.config.Endpoint != "" {
+			if endpoint := a.Autoscaling.(*autoscaling.AutoScaling).Endpoint; endpoint != c.expectedEndpoint {
+				t.Errorf("CASE: %s : endpoint of AutoScaling is not matched, expected %s, returned %s", c.title, c.expectedEndpoint, endpoint)
+			}
+		}
+	}
+}
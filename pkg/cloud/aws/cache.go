@@ -0,0 +1,80 @@
+package aws
+
+import (
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"k8s.io/klog/v2"
+
+	"github.com/h3poteto/node-manager/pkg/cloud"
+)
+
+// ClientCache keeps clients of AWS for each region and role, so credentials are not loaded on every reconcile.
+// It is safe for concurrent use, and clients of the SDK are also safe to share between reconciles.
+type ClientCache struct {
+	mu      sync.Mutex
+	sess    *session.Session
+	clients map[clientKey]*AWS
+	// defaultEndpoint is used when the config does not have Endpoint.
+	defaultEndpoint string
+	// newSession is replaced in tests.
+	newSession func() (*session.Session, error)
+}
+
+// clientKey identifies credentials and a region of a client.
+// Clients for different roles are never shared, so each NodeManager which assumes its own role has an isolated client.
+type clientKey struct {
+	region     string
+	roleARN    string
+	externalID string
+	endpoint   string
+}
+
+func NewClientCache(defaultEndpoint string) *ClientCache {
+	return &ClientCache{
+		clients:         map[clientKey]*AWS{},
+		defaultEndpoint: defaultEndpoint,
+		newSession:      newSharedSession,
+	}
+}
+
+func newSharedSession() (*session.Session, error) {
+	return session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigEnable,
+	})
+}
+
+// NewProvider is a cloud.Factory for AWS, which returns the cached client for the config.
+func (c *ClientCache) NewProvider(config cloud.Config) (cloud.Provider, error) {
+	return c.Client(config)
+}
+
+// Client returns the client for the region and the role of the config, and creates it at the first time.
+// A session is shared by all clients, and it is created again on the next call when it fails.
+func (c *ClientCache) Client(config cloud.Config) (*AWS, error) {
+	if config.Endpoint == "" {
+		config.Endpoint = c.defaultEndpoint
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := clientKey{
+		region:     config.Region,
+		roleARN:    config.RoleARN,
+		externalID: config.ExternalID,
+		endpoint:   config.Endpoint,
+	}
+	if client, ok := c.clients[key]; ok {
+		return client, nil
+	}
+	if c.sess == nil {
+		sess, err := c.newSession()
+		if err != nil {
+			klog.Errorf("failed to create aws session: %v", err)
+			return nil, err
+		}
+		c.sess = sess
+	}
+	client := New(c.sess, config)
+	c.clients[key] = client
+	return client, nil
+}
@@ -0,0 +1,83 @@
+package aws
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/h3poteto/node-manager/pkg/cloud"
+)
+
+func TestClientCache(t *testing.T) {
+	sessions := 0
+	c := NewClientCache("")
+	c.newSession = func() (*session.Session, error) {
+		sessions++
+		if sessions == 1 {
+			return nil, errors.New("failed to load credentials")
+		}
+		return session.NewSession()
+	}
+
+	if _, err := c.Client(cloud.Config{Region: "us-east-1"}); err == nil {
+		t.Fatalf("error of the session should be returned")
+	}
+	east, err := c.Client(cloud.Config{Region: "us-east-1"})
+	if err != nil {
+		t.Fatalf("session should be created again: %v", err)
+	}
+	cached, err := c.Client(cloud.Config{Region: "us-east-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if east != cached {
+		t.Errorf("client should be reused for the same region")
+	}
+	west, err := c.Client(cloud.Config{Region: "us-west-2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if west == east {
+		t.Errorf("client should be created for each region")
+	}
+	role, err := c.Client(cloud.Config{Region: "us-east-1", RoleARN: "arn:aws:iam::123456789012:role/node-manager"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if role == east {
+		t.Errorf("client should be isolated for each role")
+	}
+	local, err := c.Client(cloud.Config{Region: "us-east-1", Endpoint: "http://localhost:4566"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if local == east {
+		t.Errorf("client should be created for each endpoint")
+	}
+	if sessions != 2 {
+		t.Errorf("session should be shared by clients, but created %d times", sessions)
+	}
+}
+
+func TestClientCacheDefaultEndpoint(t *testing.T) {
+	c := NewClientCache("http://localhost:4566")
+	c.newSession = func() (*session.Session, error) {
+		return session.NewSession()
+	}
+	a, err := c.Client(cloud.Config{Region: "us-east-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if endpoint := a.EC2.(*ec2.EC2).Endpoint; endpoint != "http://localhost:4566" {
+		t.Errorf("default endpoint should be used, but returned %s", endpoint)
+	}
+	a, err = c.Client(cloud.Config{Region: "us-east-1", Endpoint: "http://localhost:5000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if endpoint := a.EC2.(*ec2.EC2).Endpoint; endpoint != "http://localhost:5000" {
+		t.Errorf("endpoint of the config should take precedence, but returned %s", endpoint)
+	}
+}
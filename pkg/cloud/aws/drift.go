@@ -0,0 +1,183 @@
+package aws
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/klog/v2"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+	"github.com/h3poteto/node-manager/pkg/cloud"
+)
+
+var _ cloud.DriftDetector = &AWS{}
+
+// launchSource is the configuration which an AutoScalingGroup launches new instances from.
+type launchSource struct {
+	launchTemplateID        string
+	launchTemplateName      string
+	launchTemplateVersion   string
+	launchConfigurationName string
+	imageID                 string
+	instanceType            string
+}
+
+// DriftedInstances compares instances in service with the launch template or the launch configuration of their AutoScalingGroups.
+// An instance is drifted when it is launched from another version of the launch template or another launch configuration,
+// or when it runs another AMI, for example the launch template resolves the AMI from an SSM parameter.
+func (a *AWS) DriftedInstances(groups []operatorv1alpha1.AutoScalingGroup) (map[string]string, error) {
+	asgs, err := a.DescribeAutoScalingGroups(groups)
+	if err != nil {
+		return nil, err
+	}
+	drifted := map[string]string{}
+	images := map[string]string{}
+	for _, asg := range asgs {
+		source, err := a.currentLaunchSource(asg)
+		if err != nil {
+			return nil, err
+		}
+		if source == nil {
+			klog.Warningf("AutoScalingGroup %s does not have a launch template nor a launch configuration, so drift is not detected", aws.StringValue(asg.AutoScalingGroupName))
+			continue
+		}
+		for _, instance := range asg.Instances {
+			if aws.StringValue(instance.LifecycleState) != autoscaling.LifecycleStateInService {
+				continue
+			}
+			id := aws.StringValue(instance.InstanceId)
+			if reason := source.driftOf(instance); reason != "" {
+				drifted[id] = reason
+				continue
+			}
+			if source.imageID != "" {
+				images[id] = source.imageID
+			}
+		}
+	}
+	if len(images) == 0 {
+		return drifted, nil
+	}
+
+	var ids []string
+	for id := range images {
+		ids = append(ids, id)
+	}
+	for _, chunked := range chunk(ids, maxInstanceIDsPerRequest) {
+		input := &ec2.DescribeInstancesInput{
+			InstanceIds: aws.StringSlice(chunked),
+		}
+		for {
+			output, err := a.EC2.DescribeInstances(input)
+			if err != nil {
+				klog.Errorf("failed to describe ec2 instances: %v", err)
+				return nil, err
+			}
+			for _, r := range output.Reservations {
+				for _, instance := range r.Instances {
+					id := aws.StringValue(instance.InstanceId)
+					if image := aws.StringValue(instance.ImageId); image != images[id] {
+						drifted[id] = fmt.Sprintf("AMI %s is not the current AMI %s", image, images[id])
+					}
+				}
+			}
+			if aws.StringValue(output.NextToken) == "" {
+				break
+			}
+			input.NextToken = output.NextToken
+		}
+	}
+	return drifted, nil
+}
+
+// currentLaunchSource resolves the launch template version or the launch configuration of the AutoScalingGroup, and the AMI of it.
+// It returns nil when the AutoScalingGroup has neither of them.
+func (a *AWS) currentLaunchSource(asg *autoscaling.Group) (*launchSource, error) {
+	spec := asg.LaunchTemplate
+	if spec == nil && asg.MixedInstancesPolicy != nil && asg.MixedInstancesPolicy.LaunchTemplate != nil {
+		spec = asg.MixedInstancesPolicy.LaunchTemplate.LaunchTemplateSpecification
+	}
+	if spec != nil {
+		version := aws.StringValue(spec.Version)
+		if version == "" {
+			version = "$Default"
+		}
+		input := &ec2.DescribeLaunchTemplateVersionsInput{
+			Versions: aws.StringSlice([]string{version}),
+			// Return the AMI which is resolved from an SSM parameter instead of the parameter.
+			ResolveAlias: aws.Bool(true),
+		}
+		if spec.LaunchTemplateId != nil {
+			input.LaunchTemplateId = spec.LaunchTemplateId
+		} else {
+			input.LaunchTemplateName = spec.LaunchTemplateName
+		}
+		output, err := a.EC2.DescribeLaunchTemplateVersions(input)
+		if err != nil {
+			klog.Errorf("failed to describe launch template versions of %s: %v", aws.StringValue(asg.AutoScalingGroupName), err)
+			return nil, err
+		}
+		if len(output.LaunchTemplateVersions) == 0 {
+			return nil, fmt.Errorf("launch template version %s of %s does not exist", version, aws.StringValue(asg.AutoScalingGroupName))
+		}
+		v := output.LaunchTemplateVersions[0]
+		source := &launchSource{
+			launchTemplateID:      aws.StringValue(v.LaunchTemplateId),
+			launchTemplateName:    aws.StringValue(v.LaunchTemplateName),
+			launchTemplateVersion: strconv.FormatInt(aws.Int64Value(v.VersionNumber), 10),
+		}
+		if v.LaunchTemplateData != nil {
+			source.imageID = aws.StringValue(v.LaunchTemplateData.ImageId)
+			source.instanceType = aws.StringValue(v.LaunchTemplateData.InstanceType)
+		}
+		return source, nil
+	}
+
+	name := aws.StringValue(asg.LaunchConfigurationName)
+	if name == "" {
+		return nil, nil
+	}
+	output, err := a.Autoscaling.DescribeLaunchConfigurations(&autoscaling.DescribeLaunchConfigurationsInput{
+		LaunchConfigurationNames: aws.StringSlice([]string{name}),
+	})
+	if err != nil {
+		klog.Errorf("failed to describe launch configuration %s: %v", name, err)
+		return nil, err
+	}
+	source := &launchSource{
+		launchConfigurationName: name,
+	}
+	if len(output.LaunchConfigurations) > 0 {
+		source.imageID = aws.StringValue(output.LaunchConfigurations[0].ImageId)
+		source.instanceType = aws.StringValue(output.LaunchConfigurations[0].InstanceType)
+	}
+	return source, nil
+}
+
+// driftOf returns the reason why the instance does not match the source, or empty when it matches.
+// AMIs are compared separately, because autoscaling.Instance does not have them.
+func (s *launchSource) driftOf(instance *autoscaling.Instance) string {
+	if s.launchConfigurationName != "" {
+		if name := aws.StringValue(instance.LaunchConfigurationName); name != s.launchConfigurationName {
+			return fmt.Sprintf("launch configuration %q is not the current launch configuration %q", name, s.launchConfigurationName)
+		}
+		return ""
+	}
+	spec := instance.LaunchTemplate
+	if spec == nil {
+		return fmt.Sprintf("instance is not launched from the current launch template %s", s.launchTemplateName)
+	}
+	if id := aws.StringValue(spec.LaunchTemplateId); id != "" && id != s.launchTemplateID {
+		return fmt.Sprintf("launch template %s is not the current launch template %s", id, s.launchTemplateID)
+	}
+	if name := aws.StringValue(spec.LaunchTemplateName); name != "" && name != s.launchTemplateName {
+		return fmt.Sprintf("launch template %s is not the current launch template %s", name, s.launchTemplateName)
+	}
+	if version := aws.StringValue(spec.Version); version != s.launchTemplateVersion {
+		return fmt.Sprintf("launch template version %s is not the current version %s", version, s.launchTemplateVersion)
+	}
+	return ""
+}
@@ -0,0 +1,121 @@
+package aws
+
+import (
+	"log"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+)
+
+func TestDriftedInstancesWithLaunchConfiguration(t *testing.T) {
+	cases := []struct {
+		title     string
+		instances []*autoscaling.Instance
+		images    map[string]string
+		expected  []string
+	}{
+		{
+			title: "All instances are up to date",
+			instances: []*autoscaling.Instance{
+				newTestGroupInstance("i-1", "lc-2", autoscaling.LifecycleStateInService),
+				newTestGroupInstance("i-2", "lc-2", autoscaling.LifecycleStateInService),
+			},
+			images:   map[string]string{"i-1": "ami-2", "i-2": "ami-2"},
+			expected: []string{},
+		},
+		{
+			title: "An instance is launched from the old launch configuration",
+			instances: []*autoscaling.Instance{
+				newTestGroupInstance("i-1", "lc-1", autoscaling.LifecycleStateInService),
+				newTestGroupInstance("i-2", "lc-2", autoscaling.LifecycleStateInService),
+			},
+			images:   map[string]string{"i-2": "ami-2"},
+			expected: []string{"i-1"},
+		},
+		{
+			title: "An instance runs the old AMI",
+			instances: []*autoscaling.Instance{
+				newTestGroupInstance("i-1", "lc-2", autoscaling.LifecycleStateInService),
+				newTestGroupInstance("i-2", "lc-2", autoscaling.LifecycleStateInService),
+			},
+			images:   map[string]string{"i-1": "ami-1", "i-2": "ami-2"},
+			expected: []string{"i-1"},
+		},
+		{
+			title: "Instances which are not in service are ignored",
+			instances: []*autoscaling.Instance{
+				newTestGroupInstance("i-1", "lc-1", autoscaling.LifecycleStateTerminating),
+				newTestGroupInstance("i-2", "lc-2", autoscaling.LifecycleStateInService),
+			},
+			images:   map[string]string{"i-2": "ami-2"},
+			expected: []string{},
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		asg := &mockedAutoScalingAPI{
+			Resp: autoscaling.DescribeAutoScalingGroupsOutput{
+				AutoScalingGroups: []*autoscaling.Group{
+					{
+						AutoScalingGroupName:    aws.String("asg-1"),
+						LaunchConfigurationName: aws.String("lc-2"),
+						Instances:               c.instances,
+					},
+				},
+			},
+			LaunchConfigurationsResp: autoscaling.DescribeLaunchConfigurationsOutput{
+				LaunchConfigurations: []*autoscaling.LaunchConfiguration{
+					{
+						LaunchConfigurationName: aws.String("lc-2"),
+						ImageId:                 aws.String("ami-2"),
+					},
+				},
+			},
+		}
+		instances := ec2.DescribeInstancesOutput{}
+		for id, image := range c.images {
+			instances.Reservations = append(instances.Reservations, &ec2.Reservation{
+				Instances: []*ec2.Instance{
+					{
+						InstanceId: aws.String(id),
+						ImageId:    aws.String(image),
+					},
+				},
+			})
+		}
+		a := &AWS{
+			Autoscaling: asg,
+			EC2:         &mockedEC2API{Resp: instances},
+		}
+
+		drifted, err := a.DriftedInstances([]operatorv1alpha1.AutoScalingGroup{{Name: "asg-1"}})
+		if err != nil {
+			t.Errorf("CASE: %s : %v", c.title, err)
+			continue
+		}
+		ids := []string{}
+		for _, i := range c.instances {
+			id := aws.StringValue(i.InstanceId)
+			if drifted[id] != "" {
+				ids = append(ids, id)
+			}
+		}
+		if len(drifted) != len(ids) || !reflect.DeepEqual(ids, c.expected) {
+			t.Errorf("CASE: %s : drifted instances are not matched, expected %v, but returned %v", c.title, c.expected, drifted)
+		}
+	}
+}
+
+func newTestGroupInstance(instanceID, launchConfiguration, lifecycleState string) *autoscaling.Instance {
+	return &autoscaling.Instance{
+		InstanceId:              aws.String(instanceID),
+		LaunchConfigurationName: aws.String(launchConfiguration),
+		LifecycleState:          aws.String(lifecycleState),
+	}
+}
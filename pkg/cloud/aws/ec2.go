@@ -12,6 +12,12 @@ import (
 	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
 )
 
+// AutoScalingGroupNameTag is the tag which EC2 Auto Scaling sets on instances it launches.
+const AutoScalingGroupNameTag = "aws:autoscaling:groupName"
+
+// maxInstanceIDsPerRequest is the number of instance IDs in a DescribeInstances request, which keeps the request small enough.
+const maxInstanceIDsPerRequest = 200
+
 func (a *AWS) DeleteInstance(node *operatorv1alpha1.AWSNode) error {
 	input := &ec2.TerminateInstancesInput{
 		DryRun: nil,
@@ -27,74 +33,116 @@ func (a *AWS) DeleteInstance(node *operatorv1alpha1.AWSNode) error {
 	return nil
 }
 
+// DescribeInstance finds the EC2 instance of the node by the instance ID, which is known from InstanceID or ProviderID of the node.
+// When both are empty, it falls back to find the instance by the node name, which equals private DNS name in default settings of EKS and kops.
 func (a *AWS) DescribeInstance(node *operatorv1alpha1.AWSNode) (*ec2.Instance, error) {
-	input := &ec2.DescribeInstancesInput{
-		DryRun: nil,
-		Filters: []*ec2.Filter{
-			{
-				Name: aws.String("instance-id"),
-				Values: []*string{
-					aws.String(node.Name),
-				},
-			},
-		},
+	filter := &ec2.Filter{
+		Name:   aws.String("instance-id"),
+		Values: []*string{},
 	}
-	output, err := a.EC2.DescribeInstances(input)
-	if err != nil {
-		klog.Errorf("failed to describe aws instances: %v", err)
-		return nil, err
+	key := instanceIDOfNode(node)
+	if key != "" {
+		filter.Values = append(filter.Values, aws.String(key))
+	} else {
+		klog.Warningf("could not find instance ID of node %s, so finding the instance by private DNS name", node.Name)
+		key = node.Name
+		filter.Name = aws.String("private-dns-name")
+		filter.Values = append(filter.Values, aws.String(node.Name))
 	}
-	if len(output.Reservations) < 1 || len(output.Reservations[0].Instances) < 1 {
-		err := fmt.Errorf("could not find aws instance %s", node.Name)
-		klog.Error(err)
-		return nil, err
+	input := &ec2.DescribeInstancesInput{
+		DryRun:  nil,
+		Filters: []*ec2.Filter{filter},
 	}
-	instance := output.Reservations[0].Instances[0]
-	return instance, nil
+	// Pages of filtered results may be empty even if following pages have the instance.
+	for {
+		output, err := a.EC2.DescribeInstances(input)
+		if err != nil {
+			klog.Errorf("failed to describe aws instances: %v", err)
+			return nil, err
+		}
+		for _, r := range output.Reservations {
+			if len(r.Instances) > 0 {
+				return r.Instances[0], nil
+			}
+		}
+		if aws.StringValue(output.NextToken) == "" {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+	err := fmt.Errorf("could not find aws instance %s", key)
+	klog.Error(err)
+	return nil, err
 }
 
+// GetAWSNodes returns the instances. AutoScalingGroupName of the instances which do not have the tag of AutoScalingGroup is resolved from AutoScalingGroups.
 func (a *AWS) GetAWSNodes(instanceIDs []*string) ([]operatorv1alpha1.AWSNode, error) {
-	input := &ec2.DescribeInstancesInput{
-		DryRun:      nil,
-		InstanceIds: instanceIDs,
+	var nodes []operatorv1alpha1.AWSNode
+	var untagged []string
+	for _, ids := range chunk(aws.StringValueSlice(instanceIDs), maxInstanceIDsPerRequest) {
+		input := &ec2.DescribeInstancesInput{
+			DryRun:      nil,
+			InstanceIds: aws.StringSlice(ids),
+		}
+		for {
+			output, err := a.EC2.DescribeInstances(input)
+			if err != nil {
+				klog.Errorf("failed to describe ec2 instances: %v", err)
+				return nil, err
+			}
+			for _, r := range output.Reservations {
+				for _, instance := range r.Instances {
+					n := ConvertInstanceToAWSNode(instance)
+					if n.AutoScalingGroupName == "" {
+						untagged = append(untagged, n.InstanceID)
+					}
+					nodes = append(nodes, *n)
+				}
+			}
+			if aws.StringValue(output.NextToken) == "" {
+				break
+			}
+			input.NextToken = output.NextToken
+		}
 	}
-	output, err := a.EC2.DescribeInstances(input)
+	if len(untagged) == 0 {
+		return nodes, nil
+	}
+	groups, err := a.DescribeAutoScalingGroupNames(untagged)
 	if err != nil {
-		klog.Errorf("failed to describe ec2 instances: %v", err)
 		return nil, err
 	}
-	var nodes []operatorv1alpha1.AWSNode
-	for _, r := range output.Reservations {
-		for _, instance := range r.Instances {
-			n, err := ConvertInstanceToAWSNode(instance)
-			if err != nil {
-				continue
-			}
-			nodes = append(nodes, *n)
+	for i := range nodes {
+		if nodes[i].AutoScalingGroupName == "" {
+			nodes[i].AutoScalingGroupName = groups[nodes[i].InstanceID]
 		}
 	}
 	return nodes, nil
 }
 
-func ConvertInstanceToAWSNode(instance *ec2.Instance) (*operatorv1alpha1.AWSNode, error) {
-	tag := findTag(instance.Tags, "Name")
-	// Normally auto scaling group name is filled in name tag of instances.
-	if tag == nil {
-		return nil, NewCouldNotFoundNameTagError("could not find Name tag in aws instances %s", *instance.InstanceId)
+// ConvertInstanceToAWSNode converts the instance to AWSNode.
+// AutoScalingGroupName is read from the tag which is set by EC2 Auto Scaling, so it is empty when the instance does not have the tag.
+func ConvertInstanceToAWSNode(instance *ec2.Instance) *operatorv1alpha1.AWSNode {
+	n := &operatorv1alpha1.AWSNode{
+		Name:         aws.StringValue(instance.PrivateDnsName),
+		InstanceID:   aws.StringValue(instance.InstanceId),
+		InstanceType: aws.StringValue(instance.InstanceType),
+	}
+	if instance.Placement != nil {
+		n.AvailabilityZone = aws.StringValue(instance.Placement.AvailabilityZone)
+	}
+	if instance.LaunchTime != nil {
+		n.CreationTimestamp = metav1.NewTime(instance.LaunchTime.In(time.Local))
+	}
+	if tag := findTag(instance.Tags, AutoScalingGroupNameTag); tag != nil {
+		n.AutoScalingGroupName = aws.StringValue(tag.Value)
 	}
-	return &operatorv1alpha1.AWSNode{
-		Name:                 *instance.PrivateDnsName,
-		InstanceID:           *instance.InstanceId,
-		AvailabilityZone:     *instance.Placement.AvailabilityZone,
-		InstanceType:         *instance.InstanceType,
-		AutoScalingGroupName: *tag.Value,
-		CreationTimestamp:    metav1.NewTime(instance.LaunchTime.In(time.Local)),
-	}, nil
+	return n
 }
 
 func findTag(tags []*ec2.Tag, key string) *ec2.Tag {
 	for i := range tags {
-		if *tags[i].Key == key {
+		if aws.StringValue(tags[i].Key) == key {
 			return tags[i]
 		}
 	}
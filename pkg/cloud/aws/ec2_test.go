@@ -1,13 +1,14 @@
 package aws
 
 import (
-	"errors"
+	"fmt"
 	"log"
 	"reflect"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -17,20 +18,31 @@ import (
 
 type mockedEC2API struct {
 	ec2iface.EC2API
-	Resp ec2.DescribeInstancesOutput
+	Resp  ec2.DescribeInstancesOutput
+	Input *ec2.DescribeInstancesInput
+	// Pages are returned instead of Resp when they are set, keyed by NextToken of requests.
+	Pages              map[string]ec2.DescribeInstancesOutput
+	RequestInstanceIDs [][]string
 }
 
 func (m *mockedEC2API) DescribeInstances(in *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error) {
+	m.Input = in
+	m.RequestInstanceIDs = append(m.RequestInstanceIDs, aws.StringValueSlice(in.InstanceIds))
+	if m.Pages != nil {
+		page := m.Pages[aws.StringValue(in.NextToken)]
+		return &page, nil
+	}
 	return &m.Resp, nil
 }
 
 func TestGetAWSNodes(t *testing.T) {
 	creationTimestamp := time.Now().Add(-1 * time.Hour)
 	cases := []struct {
-		title            string
-		instanceIDs      []*string
-		describeResponse ec2.DescribeInstancesOutput
-		expectedNodes    []operatorv1alpha1.AWSNode
+		title                        string
+		instanceIDs                  []*string
+		describeResponse             ec2.DescribeInstancesOutput
+		autoScalingInstancesResponse autoscaling.DescribeAutoScalingInstancesOutput
+		expectedNodes                []operatorv1alpha1.AWSNode
 	}{
 		{
 			title: "Multiple instances",
@@ -55,7 +67,7 @@ func TestGetAWSNodes(t *testing.T) {
 								PrivateIpAddress: aws.String("172.32.16.0"),
 								Tags: []*ec2.Tag{
 									&ec2.Tag{
-										Key:   aws.String("Name"),
+										Key:   aws.String("aws:autoscaling:groupName"),
 										Value: aws.String("asg-1"),
 									},
 								},
@@ -71,7 +83,7 @@ func TestGetAWSNodes(t *testing.T) {
 								PrivateIpAddress: aws.String("172.32.16.1"),
 								Tags: []*ec2.Tag{
 									&ec2.Tag{
-										Key:   aws.String("Name"),
+										Key:   aws.String("aws:autoscaling:groupName"),
 										Value: aws.String("asg-1"),
 									},
 								},
@@ -87,7 +99,7 @@ func TestGetAWSNodes(t *testing.T) {
 								PrivateIpAddress: aws.String("172.32.16.2"),
 								Tags: []*ec2.Tag{
 									&ec2.Tag{
-										Key:   aws.String("Name"),
+										Key:   aws.String("aws:autoscaling:groupName"),
 										Value: aws.String("asg-1"),
 									},
 								},
@@ -129,6 +141,50 @@ func TestGetAWSNodes(t *testing.T) {
 				},
 			},
 		},
+		{
+			title: "Instance without AutoScalingGroup tag",
+			instanceIDs: []*string{
+				aws.String("instanceId-1"),
+			},
+			describeResponse: ec2.DescribeInstancesOutput{
+				Reservations: []*ec2.Reservation{
+					&ec2.Reservation{
+						Instances: []*ec2.Instance{
+							&ec2.Instance{
+								InstanceId:   aws.String("instanceId-1"),
+								InstanceType: aws.String(ec2.InstanceTypeT3Small),
+								LaunchTime:   aws.Time(creationTimestamp),
+								Placement: &ec2.Placement{
+									AvailabilityZone: aws.String("us-east-1a"),
+								},
+								PrivateDnsName:   aws.String("ip-172-32-16-0"),
+								PrivateIpAddress: aws.String("172.32.16.0"),
+							},
+						},
+					},
+				},
+			},
+			autoScalingInstancesResponse: autoscaling.DescribeAutoScalingInstancesOutput{
+				AutoScalingInstances: []*autoscaling.InstanceDetails{
+					{
+						InstanceId:           aws.String("instanceId-1"),
+						AutoScalingGroupName: aws.String("asg-1"),
+					},
+				},
+			},
+			expectedNodes: []operatorv1alpha1.AWSNode{
+				{
+					Name:                 "ip-172-32-16-0",
+					InstanceID:           "instanceId-1",
+					AvailabilityZone:     "us-east-1a",
+					InstanceType:         ec2.InstanceTypeT3Small,
+					AutoScalingGroupName: "asg-1",
+					CreationTimestamp: metav1.Time{
+						Time: creationTimestamp.In(time.Local),
+					},
+				},
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -138,6 +194,9 @@ func TestGetAWSNodes(t *testing.T) {
 			EC2: &mockedEC2API{
 				Resp: c.describeResponse,
 			},
+			Autoscaling: &mockedAutoScalingAPI{
+				InstancesResp: c.autoScalingInstancesResponse,
+			},
 		}
 		node, err := a.GetAWSNodes(c.instanceIDs)
 		if err != nil {
@@ -150,29 +209,210 @@ func TestGetAWSNodes(t *testing.T) {
 	}
 }
 
+func TestGetAWSNodesWithPagination(t *testing.T) {
+	var manyInstanceIDs []*string
+	for i := 0; i < 250; i++ {
+		manyInstanceIDs = append(manyInstanceIDs, aws.String(fmt.Sprintf("instanceId-%d", i)))
+	}
+	cases := []struct {
+		title                string
+		instanceIDs          []*string
+		pages                map[string]ec2.DescribeInstancesOutput
+		expectedInstanceIDs  []string
+		expectedRequestSizes []int
+	}{
+		{
+			title: "Results have multiple pages",
+			instanceIDs: []*string{
+				aws.String("instanceId-1"),
+				aws.String("instanceId-2"),
+			},
+			pages: map[string]ec2.DescribeInstancesOutput{
+				"": {
+					Reservations: []*ec2.Reservation{
+						{
+							Instances: []*ec2.Instance{
+								newTestInstance("instanceId-1"),
+							},
+						},
+					},
+					NextToken: aws.String("token-1"),
+				},
+				"token-1": {
+					Reservations: []*ec2.Reservation{
+						{
+							Instances: []*ec2.Instance{
+								newTestInstance("instanceId-2"),
+							},
+						},
+					},
+				},
+			},
+			expectedInstanceIDs:  []string{"instanceId-1", "instanceId-2"},
+			expectedRequestSizes: []int{2, 2},
+		},
+		{
+			title:       "Instance IDs are split into chunks",
+			instanceIDs: manyInstanceIDs,
+			pages: map[string]ec2.DescribeInstancesOutput{
+				"": {},
+			},
+			expectedInstanceIDs:  nil,
+			expectedRequestSizes: []int{200, 50},
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		m := &mockedEC2API{
+			Pages: c.pages,
+		}
+		a := &AWS{
+			EC2: m,
+		}
+		nodes, err := a.GetAWSNodes(c.instanceIDs)
+		if err != nil {
+			t.Errorf("CASE: %s : error has occur: %v", c.title, err)
+			continue
+		}
+		var instanceIDs []string
+		for _, n := range nodes {
+			instanceIDs = append(instanceIDs, n.InstanceID)
+		}
+		if !reflect.DeepEqual(instanceIDs, c.expectedInstanceIDs) {
+			t.Errorf("CASE: %s : instances are not matched, expected %v, returned %v", c.title, c.expectedInstanceIDs, instanceIDs)
+		}
+		var sizes []int
+		for _, requested := range m.RequestInstanceIDs {
+			sizes = append(sizes, len(requested))
+		}
+		if !reflect.DeepEqual(sizes, c.expectedRequestSizes) {
+			t.Errorf("CASE: %s : requests are not matched, expected %v, returned %v", c.title, c.expectedRequestSizes, sizes)
+		}
+	}
+}
+
+// newTestInstance returns an instance which has the tag of AutoScalingGroup, so it does not require DescribeAutoScalingInstances.
+func newTestInstance(instanceID string) *ec2.Instance {
+	return &ec2.Instance{
+		InstanceId: aws.String(instanceID),
+		Tags: []*ec2.Tag{
+			{
+				Key:   aws.String(AutoScalingGroupNameTag),
+				Value: aws.String("asg-1"),
+			},
+		},
+	}
+}
+
+func TestDescribeInstance(t *testing.T) {
+	cases := []struct {
+		title          string
+		node           *operatorv1alpha1.AWSNode
+		expectedFilter *ec2.Filter
+	}{
+		{
+			title: "Node has instance ID",
+			node: &operatorv1alpha1.AWSNode{
+				Name:       "node-1",
+				InstanceID: "i-0123456789abcdef0",
+				ProviderID: "aws:///us-east-1a/i-0fedcba9876543210",
+			},
+			expectedFilter: &ec2.Filter{
+				Name:   aws.String("instance-id"),
+				Values: []*string{aws.String("i-0123456789abcdef0")},
+			},
+		},
+		{
+			title: "Node has providerID",
+			node: &operatorv1alpha1.AWSNode{
+				Name:       "node-1",
+				ProviderID: "aws:///us-east-1a/i-0123456789abcdef0",
+			},
+			expectedFilter: &ec2.Filter{
+				Name:   aws.String("instance-id"),
+				Values: []*string{aws.String("i-0123456789abcdef0")},
+			},
+		},
+		{
+			title: "Node does not have providerID",
+			node: &operatorv1alpha1.AWSNode{
+				Name: "ip-172-32-16-0.ec2.internal",
+			},
+			expectedFilter: &ec2.Filter{
+				Name:   aws.String("private-dns-name"),
+				Values: []*string{aws.String("ip-172-32-16-0.ec2.internal")},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		m := &mockedEC2API{
+			Resp: ec2.DescribeInstancesOutput{
+				Reservations: []*ec2.Reservation{
+					{
+						Instances: []*ec2.Instance{
+							{
+								InstanceId: aws.String("i-0123456789abcdef0"),
+							},
+						},
+					},
+				},
+			},
+		}
+		a := &AWS{
+			EC2: m,
+		}
+		_, err := a.DescribeInstance(c.node)
+		if err != nil {
+			t.Errorf("CASE: %s : error has occur: %v", c.title, err)
+			continue
+		}
+		if len(m.Input.Filters) != 1 || !reflect.DeepEqual(m.Input.Filters[0], c.expectedFilter) {
+			t.Errorf("CASE: %s : filter is not matched, expected %v, returned %v", c.title, c.expectedFilter, m.Input.Filters)
+		}
+	}
+}
+
 func TestConvertInstanceToAWSNode(t *testing.T) {
 	creationTimestamp := time.Now().Add(-1 * time.Hour)
 	cases := []struct {
-		title         string
-		instance      *ec2.Instance
-		awsNode       *operatorv1alpha1.AWSNode
-		expectedError error
+		title    string
+		instance *ec2.Instance
+		awsNode  *operatorv1alpha1.AWSNode
 	}{
 		{
-			title: "Does not include Name tag",
+			title: "Does not include AutoScalingGroup tag",
 			instance: &ec2.Instance{
 				InstanceId:   aws.String("instanceId-1"),
 				InstanceType: aws.String(ec2.InstanceTypeT3Small),
+				LaunchTime:   aws.Time(creationTimestamp),
 				Placement: &ec2.Placement{
 					AvailabilityZone: aws.String("us-east-1a"),
 				},
 				PrivateDnsName:   aws.String("ip-172-32-16-0"),
 				PrivateIpAddress: aws.String("172.32.16.0"),
+				Tags: []*ec2.Tag{
+					&ec2.Tag{
+						Key:   aws.String("Name"),
+						Value: aws.String("worker"),
+					},
+				},
+			},
+			awsNode: &operatorv1alpha1.AWSNode{
+				Name:                 "ip-172-32-16-0",
+				InstanceID:           "instanceId-1",
+				AvailabilityZone:     "us-east-1a",
+				InstanceType:         ec2.InstanceTypeT3Small,
+				AutoScalingGroupName: "",
+				CreationTimestamp: metav1.Time{
+					Time: creationTimestamp.In(time.Local),
+				},
 			},
-			expectedError: NewCouldNotFoundNameTagError("could not find Name tag in aws instances %s", "instanceId-1"),
 		},
 		{
-			title: "Include Name tag",
+			title: "Include AutoScalingGroup tag",
 			instance: &ec2.Instance{
 				InstanceId:   aws.String("instanceId-1"),
 				InstanceType: aws.String(ec2.InstanceTypeT3Small),
@@ -185,6 +425,10 @@ func TestConvertInstanceToAWSNode(t *testing.T) {
 				Tags: []*ec2.Tag{
 					&ec2.Tag{
 						Key:   aws.String("Name"),
+						Value: aws.String("worker"),
+					},
+					&ec2.Tag{
+						Key:   aws.String("aws:autoscaling:groupName"),
 						Value: aws.String("asg-1"),
 					},
 				},
@@ -199,20 +443,13 @@ func TestConvertInstanceToAWSNode(t *testing.T) {
 					Time: creationTimestamp.In(time.Local),
 				},
 			},
-			expectedError: nil,
 		},
 	}
 
 	for _, c := range cases {
 		log.Printf("Running CASE: %s", c.title)
 
-		node, err := ConvertInstanceToAWSNode(c.instance)
-		if err != nil {
-			if !errors.Is(err, c.expectedError) {
-				t.Errorf("CASE: %s : error is not matched, expected %v, returned %v", c.title, c.expectedError, err)
-			}
-			continue
-		}
+		node := ConvertInstanceToAWSNode(c.instance)
 		if !reflect.DeepEqual(*node, *c.awsNode) {
 			t.Errorf("CASE: %s : node is not matched, expected %+v, returned %+v", c.title, *c.awsNode, *node)
 		}
@@ -1,57 +1,30 @@
 package aws
 
-import "fmt"
+import (
+	"fmt"
 
-type InstanceNotYetJoinError struct {
-	Msg string
-}
+	"github.com/h3poteto/node-manager/pkg/cloud"
+)
 
-func NewInstanceNotYetJoinErrorf(format string, a ...interface{}) *InstanceNotYetJoinError {
-	return &InstanceNotYetJoinError{
-		Msg: fmt.Sprintf(format, a...),
-	}
-}
+// InstanceNotYetJoinError and DesiredInvalidError are shared with other cloud providers.
+type InstanceNotYetJoinError = cloud.InstanceNotYetJoinError
 
-func (err *InstanceNotYetJoinError) Error() string {
-	return err.Msg
-}
+var NewInstanceNotYetJoinErrorf = cloud.NewInstanceNotYetJoinErrorf
 
-func (err *InstanceNotYetJoinError) Is(target error) bool {
-	return err.Error() == target.Error()
-}
+type DesiredInvalidError = cloud.DesiredInvalidError
 
-type DesiredInvalidError struct {
-	Msg string
-}
+var NewDesiredInvalidErrorf = cloud.NewDesiredInvalidErrorf
 
-func NewDesiredInvalidErrorf(format string, a ...interface{}) *DesiredInvalidError {
-	return &DesiredInvalidError{
-		Msg: fmt.Sprintf(format, a...),
-	}
-}
-
-func (err *DesiredInvalidError) Error() string {
-	return err.Msg
-}
-
-func (err *DesiredInvalidError) Is(target error) bool {
-	return err.Error() == target.Error()
-}
-
-type CouldNotFoundNameTagError struct {
+type InvalidProviderIDError struct {
 	Msg string
 }
 
-func NewCouldNotFoundNameTagError(format string, a ...interface{}) *CouldNotFoundNameTagError {
-	return &CouldNotFoundNameTagError{
+func NewInvalidProviderIDErrorf(format string, a ...interface{}) *InvalidProviderIDError {
+	return &InvalidProviderIDError{
 		Msg: fmt.Sprintf(format, a...),
 	}
 }
 
-func (err *CouldNotFoundNameTagError) Error() string {
+func (err *InvalidProviderIDError) Error() string {
 	return err.Msg
 }
-
-func (err *CouldNotFoundNameTagError) Is(target error) bool {
-	return err.Error() == target.Error()
-}
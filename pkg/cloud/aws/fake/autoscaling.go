@@ -0,0 +1,291 @@
+package fake
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
+)
+
+// defaultMaxRecords is the page size of DescribeAutoScalingGroups when MaxRecords is not specified.
+const defaultMaxRecords = 50
+
+// AutoScaling implements autoscalingiface.AutoScalingAPI on the simulator. Methods which are not implemented panic.
+type AutoScaling struct {
+	autoscalingiface.AutoScalingAPI
+	cloud *Cloud
+}
+
+var _ autoscalingiface.AutoScalingAPI = &AutoScaling{}
+
+func (c *Cloud) AutoScaling() *AutoScaling {
+	return &AutoScaling{cloud: c}
+}
+
+func (a *AutoScaling) DescribeAutoScalingGroups(in *autoscaling.DescribeAutoScalingGroupsInput) (*autoscaling.DescribeAutoScalingGroupsOutput, error) {
+	c := a.cloud
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	names := stringSet(in.AutoScalingGroupNames)
+	var matched []*group
+	for _, g := range c.groups {
+		if len(names) > 0 && !names[g.Name] {
+			continue
+		}
+		if !matchGroupFilters(g, in.Filters) {
+			continue
+		}
+		matched = append(matched, g)
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].Name < matched[j].Name })
+
+	start := 0
+	if token := aws.StringValue(in.NextToken); token != "" {
+		var err error
+		start, err = strconv.Atoi(token)
+		if err != nil || start > len(matched) {
+			return nil, validationErrorf("The token '%s' is invalid.", token)
+		}
+	}
+	size := defaultMaxRecords
+	if in.MaxRecords != nil {
+		size = int(*in.MaxRecords)
+	}
+	end := start + size
+	out := &autoscaling.DescribeAutoScalingGroupsOutput{}
+	if end < len(matched) {
+		out.NextToken = aws.String(strconv.Itoa(end))
+	} else {
+		end = len(matched)
+	}
+	for _, g := range matched[start:end] {
+		out.AutoScalingGroups = append(out.AutoScalingGroups, c.convertGroup(g))
+	}
+	return out, nil
+}
+
+func (a *AutoScaling) DescribeAutoScalingGroupsPages(in *autoscaling.DescribeAutoScalingGroupsInput, fn func(*autoscaling.DescribeAutoScalingGroupsOutput, bool) bool) error {
+	input := *in
+	for {
+		out, err := a.DescribeAutoScalingGroups(&input)
+		if err != nil {
+			return err
+		}
+		last := out.NextToken == nil
+		if !fn(out, last) || last {
+			return nil
+		}
+		input.NextToken = out.NextToken
+	}
+}
+
+func (a *AutoScaling) DescribeAutoScalingInstances(in *autoscaling.DescribeAutoScalingInstancesInput) (*autoscaling.DescribeAutoScalingInstancesOutput, error) {
+	c := a.cloud
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	out := &autoscaling.DescribeAutoScalingInstancesOutput{}
+	for _, id := range aws.StringValueSlice(in.InstanceIds) {
+		g := c.groupOf(id)
+		if g == nil {
+			continue
+		}
+		i := c.instances[id]
+		state := autoscaling.LifecycleStateInService
+		if contains(g.waitingIDs, id) {
+			state = autoscaling.LifecycleStateTerminatingWait
+		}
+		out.AutoScalingInstances = append(out.AutoScalingInstances, &autoscaling.InstanceDetails{
+			AutoScalingGroupName: aws.String(g.Name),
+			AvailabilityZone:     aws.String(i.availabilityZone),
+			HealthStatus:         aws.String("HEALTHY"),
+			InstanceId:           aws.String(i.id),
+			InstanceType:         aws.String(i.instanceType),
+			LifecycleState:       aws.String(state),
+		})
+	}
+	return out, nil
+}
+
+// UpdateAutoScalingGroup updates sizes of the group, and launches or terminates instances immediately.
+func (a *AutoScaling) UpdateAutoScalingGroup(in *autoscaling.UpdateAutoScalingGroupInput) (*autoscaling.UpdateAutoScalingGroupOutput, error) {
+	c := a.cloud
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	g, err := c.findGroup(aws.StringValue(in.AutoScalingGroupName))
+	if err != nil {
+		return nil, err
+	}
+	min, max, desired := g.MinSize, g.MaxSize, g.DesiredCapacity
+	if in.MinSize != nil {
+		min = *in.MinSize
+	}
+	if in.MaxSize != nil {
+		max = *in.MaxSize
+	}
+	if in.DesiredCapacity != nil {
+		desired = *in.DesiredCapacity
+	}
+	if desired < min || desired > max {
+		return nil, validationErrorf("Desired capacity:%d must be between the specified min size:%d and max size:%d", desired, min, max)
+	}
+	g.MinSize, g.MaxSize, g.DesiredCapacity = min, max, desired
+	if err := c.reconcile(g); err != nil {
+		return nil, err
+	}
+	return &autoscaling.UpdateAutoScalingGroupOutput{}, nil
+}
+
+// DetachInstances removes instances from the group without terminating them.
+// The group launches new instances instead of them unless ShouldDecrementDesiredCapacity is true.
+func (a *AutoScaling) DetachInstances(in *autoscaling.DetachInstancesInput) (*autoscaling.DetachInstancesOutput, error) {
+	c := a.cloud
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	g, err := c.findGroup(aws.StringValue(in.AutoScalingGroupName))
+	if err != nil {
+		return nil, err
+	}
+	ids := aws.StringValueSlice(in.InstanceIds)
+	for _, id := range ids {
+		if c.groupOf(id) != g {
+			return nil, validationErrorf("The instance %s is not part of Auto Scaling group %s.", id, g.Name)
+		}
+	}
+	decrement := aws.BoolValue(in.ShouldDecrementDesiredCapacity)
+	if decrement && g.DesiredCapacity-int64(len(ids)) < g.MinSize {
+		return nil, validationErrorf("The number of instances to detach would cause the desired capacity to fall below the minimum size %d of group %s.", g.MinSize, g.Name)
+	}
+	for _, id := range ids {
+		g.instanceIDs = remove(g.instanceIDs, id)
+	}
+	if decrement {
+		g.DesiredCapacity -= int64(len(ids))
+	}
+	if err := c.reconcile(g); err != nil {
+		return nil, err
+	}
+	return &autoscaling.DetachInstancesOutput{}, nil
+}
+
+// TerminateInstanceInAutoScalingGroup terminates the instance, and the group launches a new instance unless ShouldDecrementDesiredCapacity is true.
+func (a *AutoScaling) TerminateInstanceInAutoScalingGroup(in *autoscaling.TerminateInstanceInAutoScalingGroupInput) (*autoscaling.TerminateInstanceInAutoScalingGroupOutput, error) {
+	c := a.cloud
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	id := aws.StringValue(in.InstanceId)
+	g := c.groupOf(id)
+	if g == nil {
+		return nil, validationErrorf("Instance Id not found - No managed instance found for instance ID: %s", id)
+	}
+	decrement := aws.BoolValue(in.ShouldDecrementDesiredCapacity)
+	if decrement && g.DesiredCapacity-1 < g.MinSize {
+		return nil, validationErrorf("Currently, desiredSize equals minSize (%d). Terminating instance without replacement will violate group's min size constraint. Either set shouldDecrementDesiredCapacity flag to false or lower group's min size.", g.MinSize)
+	}
+	if err := c.terminate(c.instances[id]); err != nil {
+		return nil, err
+	}
+	if decrement {
+		g.DesiredCapacity--
+	}
+	if err := c.reconcile(g); err != nil {
+		return nil, err
+	}
+	return &autoscaling.TerminateInstanceInAutoScalingGroupOutput{
+		Activity: &autoscaling.Activity{
+			AutoScalingGroupName: aws.String(g.Name),
+			StatusCode:           aws.String(autoscaling.ScalingActivityStatusCodeInProgress),
+		},
+	}, nil
+}
+
+func (c *Cloud) findGroup(name string) (*group, error) {
+	g, ok := c.groups[name]
+	if !ok {
+		return nil, validationErrorf("AutoScalingGroup name not found - AutoScalingGroup %s not found", name)
+	}
+	return g, nil
+}
+
+func (c *Cloud) convertGroup(g *group) *autoscaling.Group {
+	out := &autoscaling.Group{
+		AutoScalingGroupName: aws.String(g.Name),
+		AvailabilityZones:    aws.StringSlice(g.AvailabilityZones),
+		DesiredCapacity:      aws.Int64(g.DesiredCapacity),
+		MaxSize:              aws.Int64(g.MaxSize),
+		MinSize:              aws.Int64(g.MinSize),
+		LaunchTemplate: &autoscaling.LaunchTemplateSpecification{
+			LaunchTemplateId:   aws.String(g.launchTemplateID),
+			LaunchTemplateName: aws.String(g.Name),
+			Version:            aws.String("$Latest"),
+		},
+	}
+	for _, id := range g.instanceIDs {
+		out.Instances = append(out.Instances, convertGroupInstance(g, c.instances[id], autoscaling.LifecycleStateInService))
+	}
+	for _, id := range g.waitingIDs {
+		out.Instances = append(out.Instances, convertGroupInstance(g, c.instances[id], autoscaling.LifecycleStateTerminatingWait))
+	}
+	var keys []string
+	for k := range g.Tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		out.Tags = append(out.Tags, &autoscaling.TagDescription{
+			Key:          aws.String(k),
+			Value:        aws.String(g.Tags[k]),
+			ResourceId:   aws.String(g.Name),
+			ResourceType: aws.String("auto-scaling-group"),
+		})
+	}
+	return out
+}
+
+func convertGroupInstance(g *group, i *instance, lifecycleState string) *autoscaling.Instance {
+	return &autoscaling.Instance{
+		AvailabilityZone: aws.String(i.availabilityZone),
+		HealthStatus:     aws.String("Healthy"),
+		InstanceId:       aws.String(i.id),
+		InstanceType:     aws.String(i.instanceType),
+		LaunchTemplate: &autoscaling.LaunchTemplateSpecification{
+			LaunchTemplateId:   aws.String(g.launchTemplateID),
+			LaunchTemplateName: aws.String(g.Name),
+			Version:            aws.String(strconv.FormatInt(i.launchTemplateVersion, 10)),
+		},
+		LifecycleState: aws.String(lifecycleState),
+	}
+}
+
+// matchGroupFilters supports tag-key, tag-value and tag:<key> filters. Values in a filter are ORed, and filters are ANDed.
+func matchGroupFilters(g *group, filters []*autoscaling.Filter) bool {
+	for _, f := range filters {
+		values := stringSet(f.Values)
+		name := aws.StringValue(f.Name)
+		matched := false
+		switch {
+		case name == "tag-key":
+			for k := range g.Tags {
+				matched = matched || values[k]
+			}
+		case name == "tag-value":
+			for _, v := range g.Tags {
+				matched = matched || values[v]
+			}
+		case strings.HasPrefix(name, "tag:"):
+			v, ok := g.Tags[strings.TrimPrefix(name, "tag:")]
+			matched = ok && values[v]
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,201 @@
+package fake
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+)
+
+// EC2 implements ec2iface.EC2API on the simulator. Methods which are not implemented panic.
+type EC2 struct {
+	ec2iface.EC2API
+	cloud *Cloud
+}
+
+var _ ec2iface.EC2API = &EC2{}
+
+func (c *Cloud) EC2() *EC2 {
+	return &EC2{cloud: c}
+}
+
+// DescribeInstances supports InstanceIds and instance-id, private-dns-name, instance-state-name, tag-key and tag:<key> filters.
+// Each instance is returned in its own reservation.
+func (e *EC2) DescribeInstances(in *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error) {
+	c := e.cloud
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var matched []*instance
+	if len(in.InstanceIds) > 0 {
+		for _, id := range aws.StringValueSlice(in.InstanceIds) {
+			i, ok := c.instances[id]
+			if !ok {
+				return nil, awserr.New("InvalidInstanceID.NotFound", fmt.Sprintf("The instance ID '%s' does not exist", id), nil)
+			}
+			matched = append(matched, i)
+		}
+	} else {
+		for _, i := range c.instances {
+			matched = append(matched, i)
+		}
+		sort.Slice(matched, func(i, j int) bool { return matched[i].id < matched[j].id })
+	}
+
+	var filtered []*instance
+	for _, i := range matched {
+		if matchInstanceFilters(i, in.Filters) {
+			filtered = append(filtered, i)
+		}
+	}
+
+	start := 0
+	if token := aws.StringValue(in.NextToken); token != "" {
+		var err error
+		start, err = strconv.Atoi(token)
+		if err != nil || start > len(filtered) {
+			return nil, awserr.New("InvalidParameterValue", fmt.Sprintf("The token '%s' is invalid.", token), nil)
+		}
+	}
+	end := len(filtered)
+	out := &ec2.DescribeInstancesOutput{}
+	if in.MaxResults != nil && start+int(*in.MaxResults) < end {
+		end = start + int(*in.MaxResults)
+		out.NextToken = aws.String(strconv.Itoa(end))
+	}
+	for _, i := range filtered[start:end] {
+		out.Reservations = append(out.Reservations, &ec2.Reservation{
+			Instances: []*ec2.Instance{convertInstance(i)},
+		})
+	}
+	return out, nil
+}
+
+// TerminateInstances terminates the instances. AutoScalingGroups launch new instances instead of them, like replacing unhealthy instances.
+func (e *EC2) TerminateInstances(in *ec2.TerminateInstancesInput) (*ec2.TerminateInstancesOutput, error) {
+	c := e.cloud
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	out := &ec2.TerminateInstancesOutput{}
+	for _, id := range aws.StringValueSlice(in.InstanceIds) {
+		if _, ok := c.instances[id]; !ok {
+			return nil, awserr.New("InvalidInstanceID.NotFound", fmt.Sprintf("The instance ID '%s' does not exist", id), nil)
+		}
+	}
+	for _, id := range aws.StringValueSlice(in.InstanceIds) {
+		i := c.instances[id]
+		previous := i.state
+		g := c.groupOf(id)
+		if err := c.terminate(i); err != nil {
+			return nil, err
+		}
+		if g != nil {
+			if err := c.reconcile(g); err != nil {
+				return nil, err
+			}
+		}
+		out.TerminatingInstances = append(out.TerminatingInstances, &ec2.InstanceStateChange{
+			InstanceId:    aws.String(id),
+			PreviousState: &ec2.InstanceState{Name: aws.String(previous)},
+			CurrentState:  &ec2.InstanceState{Name: aws.String(i.state)},
+		})
+	}
+	return out, nil
+}
+
+// DescribeLaunchTemplateVersions returns versions of the launch template of a group, which is specified by LaunchTemplateId or LaunchTemplateName.
+// Versions can be numbers, $Latest or $Default, and $Default is the same as $Latest in the simulator.
+func (e *EC2) DescribeLaunchTemplateVersions(in *ec2.DescribeLaunchTemplateVersionsInput) (*ec2.DescribeLaunchTemplateVersionsOutput, error) {
+	c := e.cloud
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var g *group
+	for _, candidate := range c.groups {
+		if candidate.launchTemplateID == aws.StringValue(in.LaunchTemplateId) || candidate.Name == aws.StringValue(in.LaunchTemplateName) {
+			g = candidate
+			break
+		}
+	}
+	if g == nil {
+		return nil, awserr.New("InvalidLaunchTemplateId.NotFound", "The specified launch template does not exist.", nil)
+	}
+	out := &ec2.DescribeLaunchTemplateVersionsOutput{}
+	for _, v := range aws.StringValueSlice(in.Versions) {
+		number := g.latestLaunchTemplateVersion()
+		if v != "$Latest" && v != "$Default" {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || n < 1 || n > number {
+				return nil, awserr.New("InvalidLaunchTemplateId.VersionNotFound", fmt.Sprintf("Could not find launch template version %s", v), nil)
+			}
+			number = n
+		}
+		version := g.launchTemplateVersions[number-1]
+		out.LaunchTemplateVersions = append(out.LaunchTemplateVersions, &ec2.LaunchTemplateVersion{
+			LaunchTemplateId:   aws.String(g.launchTemplateID),
+			LaunchTemplateName: aws.String(g.Name),
+			VersionNumber:      aws.Int64(number),
+			DefaultVersion:     aws.Bool(number == g.latestLaunchTemplateVersion()),
+			LaunchTemplateData: &ec2.ResponseLaunchTemplateData{
+				ImageId:      aws.String(version.imageID),
+				InstanceType: aws.String(version.instanceType),
+			},
+		})
+	}
+	return out, nil
+}
+
+func convertInstance(i *instance) *ec2.Instance {
+	out := &ec2.Instance{
+		ImageId:          aws.String(i.imageID),
+		InstanceId:       aws.String(i.id),
+		InstanceType:     aws.String(i.instanceType),
+		LaunchTime:       aws.Time(i.launchTime),
+		Placement:        &ec2.Placement{AvailabilityZone: aws.String(i.availabilityZone)},
+		PrivateDnsName:   aws.String(i.privateDNSName),
+		PrivateIpAddress: aws.String(i.privateIP),
+		State:            &ec2.InstanceState{Name: aws.String(i.state)},
+	}
+	var keys []string
+	for k := range i.tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		out.Tags = append(out.Tags, &ec2.Tag{Key: aws.String(k), Value: aws.String(i.tags[k])})
+	}
+	return out
+}
+
+func matchInstanceFilters(i *instance, filters []*ec2.Filter) bool {
+	for _, f := range filters {
+		values := stringSet(f.Values)
+		name := aws.StringValue(f.Name)
+		matched := false
+		switch {
+		case name == "instance-id":
+			matched = values[i.id]
+		case name == "private-dns-name":
+			matched = values[i.privateDNSName]
+		case name == "instance-state-name":
+			matched = values[i.state]
+		case name == "tag-key":
+			for k := range i.tags {
+				matched = matched || values[k]
+			}
+		case strings.HasPrefix(name, "tag:"):
+			v, ok := i.tags[strings.TrimPrefix(name, "tag:")]
+			matched = ok && values[v]
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,347 @@
+// Package fake provides an in-process simulator of EC2 and EC2 Auto Scaling for tests.
+// Unlike mocks which stub each call, it keeps AutoScalingGroups and instances as state,
+// so a whole cycle of refreshing or replenishing nodes can be driven against it.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DefaultInstanceType is used when InstanceType of the AutoScalingGroup is empty.
+	DefaultInstanceType = ec2.InstanceTypeT3Medium
+	// DefaultImageID is used when ImageID of the AutoScalingGroup is empty.
+	DefaultImageID          = "ami-0123456789abcdef0"
+	autoScalingGroupNameTag = "aws:autoscaling:groupName"
+)
+
+// AutoScalingGroup is a definition of an AutoScalingGroup in the simulator.
+type AutoScalingGroup struct {
+	Name              string
+	MinSize           int64
+	MaxSize           int64
+	DesiredCapacity   int64
+	AvailabilityZones []string
+	InstanceType      string
+	ImageID           string
+	Tags              map[string]string
+	// NodeLabels are set to nodes of the instances when nodes are registered, for example node-role.kubernetes.io/node.
+	NodeLabels map[string]string
+	// TerminationLifecycleHook is the name of a lifecycle hook for terminating instances.
+	// Instances which are replaced by Instance Refresh wait in Terminating:Wait until the lifecycle action is completed.
+	TerminationLifecycleHook string
+}
+
+type group struct {
+	AutoScalingGroup
+	// instanceIDs are instances which are in service, in the order of launching.
+	instanceIDs []string
+	// waitingIDs are instances which wait for the termination lifecycle hook.
+	waitingIDs []string
+	refreshes  []*instanceRefresh
+	// launchTemplateID is the ID of the launch template of the group, whose name is the same as the group.
+	launchTemplateID string
+	// launchTemplateVersions are versions of the launch template. The version number is the index + 1, and the last one is $Latest.
+	launchTemplateVersions []launchTemplateVersion
+}
+
+type launchTemplateVersion struct {
+	imageID      string
+	instanceType string
+}
+
+type instance struct {
+	id               string
+	privateDNSName   string
+	privateIP        string
+	availabilityZone string
+	instanceType     string
+	imageID          string
+	launchTime       time.Time
+	state            string
+	tags             map[string]string
+	// launchTemplateVersion is the version of the launch template which the instance is launched from.
+	launchTemplateVersion int64
+}
+
+// Cloud holds the state of the simulator. EC2, AutoScaling and SSM return clients which share the state.
+type Cloud struct {
+	// Now returns the launch time of instances. It can be replaced to age instances in tests.
+	Now func() time.Time
+
+	mu        sync.Mutex
+	region    string
+	groups    map[string]*group
+	instances map[string]*instance
+	launched  int
+	refreshed int
+	nodes     client.Client
+	// parameters are SSM parameters keyed by their names.
+	parameters map[string]string
+}
+
+func New(region string) *Cloud {
+	return &Cloud{
+		Now:        time.Now,
+		region:     region,
+		groups:     map[string]*group{},
+		instances:  map[string]*instance{},
+		parameters: map[string]string{},
+	}
+}
+
+// RegisterNodes creates a node in the client for every instance which is launched after this call, and deletes it when the instance is terminated.
+// It behaves like kubelet joining the cluster immediately.
+func (c *Cloud) RegisterNodes(nodes client.Client) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nodes = nodes
+}
+
+// AddAutoScalingGroup creates an AutoScalingGroup and launches instances as many as DesiredCapacity.
+func (c *Cloud) AddAutoScalingGroup(g AutoScalingGroup) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.groups[g.Name]; ok {
+		return awserr.New("AlreadyExists", fmt.Sprintf("AutoScalingGroup by this name already exists - A group with the name %s already exists", g.Name), nil)
+	}
+	if g.DesiredCapacity < g.MinSize || g.DesiredCapacity > g.MaxSize {
+		return validationErrorf("Desired capacity:%d must be between the specified min size:%d and max size:%d", g.DesiredCapacity, g.MinSize, g.MaxSize)
+	}
+	if len(g.AvailabilityZones) == 0 {
+		g.AvailabilityZones = []string{c.region + "a"}
+	}
+	if g.InstanceType == "" {
+		g.InstanceType = DefaultInstanceType
+	}
+	if g.ImageID == "" {
+		g.ImageID = DefaultImageID
+	}
+	c.groups[g.Name] = &group{
+		AutoScalingGroup: g,
+		launchTemplateID: fmt.Sprintf("lt-%017x", len(c.groups)+1),
+	}
+	c.groups[g.Name].newLaunchTemplateVersion()
+	return c.reconcile(c.groups[g.Name])
+}
+
+// SetInstanceType changes the instance type of new instances in the AutoScalingGroup with a new version of its launch template.
+func (c *Cloud) SetInstanceType(groupName string, instanceType string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	g, err := c.findGroup(groupName)
+	if err != nil {
+		return err
+	}
+	g.InstanceType = instanceType
+	g.newLaunchTemplateVersion()
+	return nil
+}
+
+// SetImageID changes the AMI of new instances in the AutoScalingGroup with a new version of its launch template.
+func (c *Cloud) SetImageID(groupName string, imageID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	g, err := c.findGroup(groupName)
+	if err != nil {
+		return err
+	}
+	g.ImageID = imageID
+	g.newLaunchTemplateVersion()
+	return nil
+}
+
+// InstanceIDs returns IDs of instances in service of the AutoScalingGroup.
+func (c *Cloud) InstanceIDs(groupName string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	g, ok := c.groups[groupName]
+	if !ok {
+		return nil
+	}
+	return append([]string{}, g.instanceIDs...)
+}
+
+// reconcile launches or terminates instances until the number of instances reaches DesiredCapacity, like EC2 Auto Scaling does.
+func (c *Cloud) reconcile(g *group) error {
+	for int64(len(g.instanceIDs)) < g.DesiredCapacity {
+		if err := c.launch(g); err != nil {
+			return err
+		}
+	}
+	for int64(len(g.instanceIDs)) > g.DesiredCapacity {
+		if err := c.terminate(c.instances[c.scaleInTarget(g)]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// launch starts an instance in the availability zone which has the fewest instances of the group.
+func (c *Cloud) launch(g *group) error {
+	c.launched++
+	n := c.launched
+	ip := fmt.Sprintf("10.0.%d.%d", n/256, n%256)
+	i := &instance{
+		id:               fmt.Sprintf("i-%017x", n),
+		privateDNSName:   fmt.Sprintf("ip-10-0-%d-%d.%s.compute.internal", n/256, n%256, c.region),
+		privateIP:        ip,
+		availabilityZone: c.balancedZone(g),
+		instanceType:     g.InstanceType,
+		imageID:          g.ImageID,
+		launchTime:       c.Now(),
+		state:            ec2.InstanceStateNameRunning,
+		tags:             map[string]string{autoScalingGroupNameTag: g.Name},
+		// Instances are launched from $Latest.
+		launchTemplateVersion: g.latestLaunchTemplateVersion(),
+	}
+	for k, v := range g.Tags {
+		i.tags[k] = v
+	}
+	c.instances[i.id] = i
+	g.instanceIDs = append(g.instanceIDs, i.id)
+	if c.nodes == nil {
+		return nil
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   i.privateDNSName,
+			Labels: g.NodeLabels,
+		},
+		Spec: corev1.NodeSpec{
+			ProviderID: fmt.Sprintf("aws:///%s/%s", i.availabilityZone, i.id),
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{
+					Type:   corev1.NodeReady,
+					Status: corev1.ConditionTrue,
+				},
+			},
+		},
+	}
+	return c.nodes.Create(context.Background(), node)
+}
+
+// terminate terminates the instance and removes it from its group. The terminated instance is still described, like EC2.
+func (c *Cloud) terminate(i *instance) error {
+	if i.state == ec2.InstanceStateNameTerminated {
+		return nil
+	}
+	i.state = ec2.InstanceStateNameTerminated
+	if g, ok := c.groups[i.tags[autoScalingGroupNameTag]]; ok {
+		g.instanceIDs = remove(g.instanceIDs, i.id)
+		g.waitingIDs = remove(g.waitingIDs, i.id)
+	}
+	if c.nodes == nil {
+		return nil
+	}
+	node := &corev1.Node{}
+	node.Name = i.privateDNSName
+	if err := c.nodes.Delete(context.Background(), node); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// balancedZone returns the availability zone which has the fewest instances of the group.
+func (c *Cloud) balancedZone(g *group) string {
+	counts := c.zoneCounts(g)
+	zone := g.AvailabilityZones[0]
+	for _, z := range g.AvailabilityZones {
+		if counts[z] < counts[zone] {
+			zone = z
+		}
+	}
+	return zone
+}
+
+// scaleInTarget chooses an instance to terminate on scale-in with a simplified default termination policy of EC2 Auto Scaling,
+// which is the oldest instance in the availability zone which has the most instances.
+func (c *Cloud) scaleInTarget(g *group) string {
+	counts := c.zoneCounts(g)
+	zones := append([]string{}, g.AvailabilityZones...)
+	sort.SliceStable(zones, func(i, j int) bool { return counts[zones[i]] > counts[zones[j]] })
+	target := ""
+	for _, id := range g.instanceIDs {
+		i := c.instances[id]
+		if i.availabilityZone != zones[0] {
+			continue
+		}
+		if target == "" || i.launchTime.Before(c.instances[target].launchTime) {
+			target = id
+		}
+	}
+	return target
+}
+
+func (c *Cloud) zoneCounts(g *group) map[string]int {
+	counts := map[string]int{}
+	for _, id := range g.instanceIDs {
+		counts[c.instances[id].availabilityZone]++
+	}
+	return counts
+}
+
+func (g *group) newLaunchTemplateVersion() {
+	g.launchTemplateVersions = append(g.launchTemplateVersions, launchTemplateVersion{
+		imageID:      g.ImageID,
+		instanceType: g.InstanceType,
+	})
+}
+
+func (g *group) latestLaunchTemplateVersion() int64 {
+	return int64(len(g.launchTemplateVersions))
+}
+
+func (c *Cloud) groupOf(instanceID string) *group {
+	for _, g := range c.groups {
+		if contains(g.instanceIDs, instanceID) || contains(g.waitingIDs, instanceID) {
+			return g
+		}
+	}
+	return nil
+}
+
+func contains(ids []string, id string) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
+func remove(ids []string, id string) []string {
+	var result []string
+	for _, i := range ids {
+		if i != id {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+func validationErrorf(format string, a ...interface{}) error {
+	return awserr.New("ValidationError", fmt.Sprintf(format, a...), nil)
+}
+
+func stringSet(values []*string) map[string]bool {
+	set := map[string]bool{}
+	for _, v := range values {
+		set[aws.StringValue(v)] = true
+	}
+	return set
+}
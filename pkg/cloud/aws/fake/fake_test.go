@@ -0,0 +1,466 @@
+package fake
+
+import (
+	"context"
+	"log"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+	cloudaws "github.com/h3poteto/node-manager/pkg/cloud/aws"
+)
+
+func TestUpdateAutoScalingGroup(t *testing.T) {
+	cases := []struct {
+		title             string
+		desired           int64
+		expectedError     bool
+		expectedInstances int
+		expectedZones     map[string]int
+	}{
+		{
+			title:             "Scale out across zones",
+			desired:           5,
+			expectedInstances: 5,
+			expectedZones:     map[string]int{"us-east-1a": 3, "us-east-1b": 2},
+		},
+		{
+			title:             "Scale in from the largest zone",
+			desired:           1,
+			expectedInstances: 1,
+			expectedZones:     map[string]int{"us-east-1b": 1},
+		},
+		{
+			title:             "Desired exceeds max",
+			desired:           6,
+			expectedError:     true,
+			expectedInstances: 3,
+			expectedZones:     map[string]int{"us-east-1a": 2, "us-east-1b": 1},
+		},
+		{
+			title:             "Desired is below min",
+			desired:           0,
+			expectedError:     true,
+			expectedInstances: 3,
+			expectedZones:     map[string]int{"us-east-1a": 2, "us-east-1b": 1},
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		f := New("us-east-1")
+		if err := f.AddAutoScalingGroup(AutoScalingGroup{
+			Name:              "asg-1",
+			MinSize:           1,
+			MaxSize:           5,
+			DesiredCapacity:   3,
+			AvailabilityZones: []string{"us-east-1a", "us-east-1b"},
+		}); err != nil {
+			t.Fatal(err)
+		}
+		_, err := f.AutoScaling().UpdateAutoScalingGroup(&autoscaling.UpdateAutoScalingGroupInput{
+			AutoScalingGroupName: aws.String("asg-1"),
+			DesiredCapacity:      aws.Int64(c.desired),
+		})
+		if c.expectedError {
+			if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != "ValidationError" {
+				t.Errorf("CASE: %s : ValidationError should be returned, but returned %v", c.title, err)
+			}
+		} else if err != nil {
+			t.Errorf("CASE: %s : error has occur: %v", c.title, err)
+		}
+		out, err := f.AutoScaling().DescribeAutoScalingGroups(&autoscaling.DescribeAutoScalingGroupsInput{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		asg := out.AutoScalingGroups[0]
+		if len(asg.Instances) != c.expectedInstances {
+			t.Errorf("CASE: %s : instances are not matched, expected %d, returned %d", c.title, c.expectedInstances, len(asg.Instances))
+		}
+		zones := map[string]int{}
+		for _, i := range asg.Instances {
+			zones[aws.StringValue(i.AvailabilityZone)]++
+		}
+		for zone, count := range c.expectedZones {
+			if zones[zone] != count {
+				t.Errorf("CASE: %s : instances in %s are not matched, expected %d, returned %d", c.title, zone, count, zones[zone])
+			}
+		}
+	}
+}
+
+func TestScaleInTerminatesOldestInstance(t *testing.T) {
+	f := New("us-east-1")
+	now := time.Now()
+	f.Now = func() time.Time {
+		now = now.Add(time.Minute)
+		return now
+	}
+	if err := f.AddAutoScalingGroup(AutoScalingGroup{Name: "asg-1", MinSize: 0, MaxSize: 3, DesiredCapacity: 3}); err != nil {
+		t.Fatal(err)
+	}
+	oldest := f.InstanceIDs("asg-1")[0]
+	if _, err := f.AutoScaling().UpdateAutoScalingGroup(&autoscaling.UpdateAutoScalingGroupInput{
+		AutoScalingGroupName: aws.String("asg-1"),
+		DesiredCapacity:      aws.Int64(2),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range f.InstanceIDs("asg-1") {
+		if id == oldest {
+			t.Errorf("the oldest instance %s should be terminated", oldest)
+		}
+	}
+}
+
+func TestDescribeAutoScalingGroupsPagination(t *testing.T) {
+	f := New("us-east-1")
+	for _, name := range []string{"asg-a", "asg-b", "asg-c"} {
+		if err := f.AddAutoScalingGroup(AutoScalingGroup{Name: name, MaxSize: 1, Tags: map[string]string{"role": "worker"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var names []string
+	err := f.AutoScaling().DescribeAutoScalingGroupsPages(&autoscaling.DescribeAutoScalingGroupsInput{
+		MaxRecords: aws.Int64(2),
+		Filters: []*autoscaling.Filter{
+			{
+				Name:   aws.String("tag:role"),
+				Values: []*string{aws.String("worker")},
+			},
+		},
+	}, func(page *autoscaling.DescribeAutoScalingGroupsOutput, lastPage bool) bool {
+		for _, asg := range page.AutoScalingGroups {
+			names = append(names, aws.StringValue(asg.AutoScalingGroupName))
+		}
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 3 {
+		t.Errorf("all groups should be described over pages, but returned %v", names)
+	}
+}
+
+// TestRefreshCycle drives the steps of refreshing a node through the AWS provider against the simulator.
+func TestRefreshCycle(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	nodes := fakeclient.NewClientBuilder().WithScheme(scheme).Build()
+	f := New("us-east-1")
+	f.RegisterNodes(nodes)
+	if err := f.AddAutoScalingGroup(AutoScalingGroup{
+		Name:            "asg-1",
+		MinSize:         0,
+		MaxSize:         3,
+		DesiredCapacity: 2,
+		NodeLabels:      map[string]string{"node-role.kubernetes.io/node": ""},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	provider := &cloudaws.AWS{
+		EC2:         f.EC2(),
+		Autoscaling: f.AutoScaling(),
+	}
+	groups := []operatorv1alpha1.AutoScalingGroup{{Name: "asg-1"}}
+
+	// increasing
+	if err := provider.ScaleUpNodeGroups(groups, 3, 2); err != nil {
+		t.Fatalf("failed to scale up: %v", err)
+	}
+	if count := countNodes(t, nodes); count != 3 {
+		t.Fatalf("new node should join, but there are %d nodes", count)
+	}
+
+	// replacing
+	old := f.InstanceIDs("asg-1")[0]
+	var list corev1.NodeList
+	if err := nodes.List(context.Background(), &list); err != nil {
+		t.Fatal(err)
+	}
+	var target *operatorv1alpha1.AWSNode
+	for _, n := range list.Items {
+		awsNode := &operatorv1alpha1.AWSNode{Name: n.Name, ProviderID: n.Spec.ProviderID}
+		instance, err := provider.InstanceForNode(awsNode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if instance.InstanceID == old {
+			target = instance
+		}
+	}
+	if target == nil {
+		t.Fatalf("could not find the node of instance %s", old)
+	}
+	if err := provider.TerminateInstance(target); err != nil {
+		t.Fatalf("failed to terminate instance: %v", err)
+	}
+	terminated, err := provider.InstanceTerminated(target)
+	if err != nil || !terminated {
+		t.Fatalf("instance should be terminated, but returned %v, %v", terminated, err)
+	}
+	if count := countNodes(t, nodes); count != 3 {
+		t.Fatalf("AutoScalingGroup should replace the terminated instance, but there are %d nodes", count)
+	}
+
+	// decreasing
+	if err := provider.ScaleDownNodeGroups(groups, 2, 3); err != nil {
+		t.Fatalf("failed to scale down: %v", err)
+	}
+	if count := countNodes(t, nodes); count != 2 {
+		t.Errorf("surplus node should be removed, but there are %d nodes", count)
+	}
+	for _, id := range f.InstanceIDs("asg-1") {
+		if id == old {
+			t.Errorf("refreshed instance %s should not be in service", old)
+		}
+	}
+}
+
+func TestInstanceRefreshWithLifecycleHook(t *testing.T) {
+	f := New("us-east-1")
+	if err := f.AddAutoScalingGroup(AutoScalingGroup{
+		Name:                     "asg-1",
+		MinSize:                  0,
+		MaxSize:                  4,
+		DesiredCapacity:          2,
+		InstanceType:             "t3.small",
+		TerminationLifecycleHook: "drain",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	provider := &cloudaws.AWS{
+		EC2:         f.EC2(),
+		Autoscaling: f.AutoScaling(),
+	}
+	old := f.InstanceIDs("asg-1")
+
+	id, err := provider.StartInstanceRefresh("asg-1", nil)
+	if err != nil {
+		t.Fatalf("failed to start instance refresh: %v", err)
+	}
+	adopted, err := provider.StartInstanceRefresh("asg-1", nil)
+	if err != nil || adopted != id {
+		t.Fatalf("running instance refresh %s should be adopted, but returned %s, %v", id, adopted, err)
+	}
+
+	for i := 0; i < 10; i++ {
+		refresh, err := provider.DescribeInstanceRefresh("asg-1", id)
+		if err != nil {
+			t.Fatalf("failed to describe instance refresh: %v", err)
+		}
+		if refresh.Finished {
+			if !refresh.Succeeded || refresh.PercentageComplete != 100 {
+				t.Errorf("instance refresh should succeed, but returned %+v", refresh)
+			}
+			break
+		}
+		waiting, err := provider.TerminationWaitingInstances("asg-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(waiting) != 1 {
+			t.Fatalf("one instance should wait for the lifecycle hook, but %v are waiting", waiting)
+		}
+		if err := provider.CompleteTerminationLifecycleAction("asg-1", "unknown", waiting[0]); err == nil {
+			t.Errorf("lifecycle action of unknown hook should not be completed")
+		}
+		if err := provider.CompleteTerminationLifecycleAction("asg-1", "drain", waiting[0]); err != nil {
+			t.Fatalf("failed to complete lifecycle action: %v", err)
+		}
+	}
+
+	current := f.InstanceIDs("asg-1")
+	if len(current) != 2 {
+		t.Fatalf("desired capacity should be kept, but there are %v", current)
+	}
+	for _, id := range current {
+		for _, o := range old {
+			if id == o {
+				t.Errorf("instance %s should be replaced", o)
+			}
+		}
+	}
+}
+
+func TestInstanceRefreshSkipMatching(t *testing.T) {
+	f := New("us-east-1")
+	if err := f.AddAutoScalingGroup(AutoScalingGroup{
+		Name:            "asg-1",
+		MinSize:         0,
+		MaxSize:         4,
+		DesiredCapacity: 2,
+		InstanceType:    "t3.small",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	provider := &cloudaws.AWS{
+		EC2:         f.EC2(),
+		Autoscaling: f.AutoScaling(),
+	}
+	preferences := &operatorv1alpha1.InstanceRefreshPreferences{SkipMatching: true}
+
+	id, err := provider.StartInstanceRefresh("asg-1", preferences)
+	if err != nil {
+		t.Fatalf("failed to start instance refresh: %v", err)
+	}
+	refresh, err := provider.DescribeInstanceRefresh("asg-1", id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !refresh.Succeeded || refresh.InstancesToUpdate != 0 {
+		t.Errorf("matching instances should not be replaced, but returned %+v", refresh)
+	}
+
+	if err := f.SetInstanceType("asg-1", "t3.medium"); err != nil {
+		t.Fatal(err)
+	}
+	id, err = provider.StartInstanceRefresh("asg-1", preferences)
+	if err != nil {
+		t.Fatalf("failed to start instance refresh: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		refresh, err = provider.DescribeInstanceRefresh("asg-1", id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if refresh.Finished {
+			break
+		}
+	}
+	if !refresh.Succeeded || refresh.PercentageComplete != 100 {
+		t.Errorf("instance refresh should succeed, but returned %+v", refresh)
+	}
+	if count := len(f.InstanceIDs("asg-1")); count != 2 {
+		t.Errorf("desired capacity should be kept, but there are %d instances", count)
+	}
+}
+
+func TestDriftedInstances(t *testing.T) {
+	f := New("us-east-1")
+	if err := f.AddAutoScalingGroup(AutoScalingGroup{
+		Name:            "asg-1",
+		MinSize:         0,
+		MaxSize:         3,
+		DesiredCapacity: 2,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	provider := &cloudaws.AWS{
+		EC2:         f.EC2(),
+		Autoscaling: f.AutoScaling(),
+	}
+	groups := []operatorv1alpha1.AutoScalingGroup{{Name: "asg-1"}}
+
+	drifted, err := provider.DriftedInstances(groups)
+	if err != nil {
+		t.Fatalf("failed to detect drift: %v", err)
+	}
+	if len(drifted) != 0 {
+		t.Errorf("instances should not be drifted, but returned %v", drifted)
+	}
+
+	if err := f.SetImageID("asg-1", "ami-new"); err != nil {
+		t.Fatal(err)
+	}
+	drifted, err = provider.DriftedInstances(groups)
+	if err != nil {
+		t.Fatalf("failed to detect drift: %v", err)
+	}
+	old := f.InstanceIDs("asg-1")
+	for _, id := range old {
+		if drifted[id] != "launch template version 1 is not the current version 2" {
+			t.Errorf("instance %s should be drifted by the launch template version, but returned %q", id, drifted[id])
+		}
+	}
+
+	// A new instance is launched from the latest launch template instead of the terminated one.
+	if _, err := f.EC2().TerminateInstances(&ec2.TerminateInstancesInput{InstanceIds: aws.StringSlice(old[:1])}); err != nil {
+		t.Fatal(err)
+	}
+	drifted, err = provider.DriftedInstances(groups)
+	if err != nil {
+		t.Fatalf("failed to detect drift: %v", err)
+	}
+	if len(drifted) != 1 || drifted[old[1]] == "" {
+		t.Errorf("only instance %s should be drifted, but returned %v", old[1], drifted)
+	}
+}
+
+func TestLaunchInstanceTypes(t *testing.T) {
+	f := New("us-east-1")
+	if err := f.AddAutoScalingGroup(AutoScalingGroup{
+		Name:            "asg-1",
+		MinSize:         0,
+		MaxSize:         3,
+		DesiredCapacity: 1,
+		InstanceType:    "t3.small",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	provider := &cloudaws.AWS{
+		EC2:         f.EC2(),
+		Autoscaling: f.AutoScaling(),
+	}
+	groups := []operatorv1alpha1.AutoScalingGroup{{Name: "asg-1"}}
+
+	types, err := provider.LaunchInstanceTypes(groups)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(types["asg-1"], []string{"t3.small"}) {
+		t.Errorf("instance types are not matched, expected [t3.small], but returned %v", types)
+	}
+
+	if err := f.SetInstanceType("asg-1", "t3.medium"); err != nil {
+		t.Fatal(err)
+	}
+	types, err = provider.LaunchInstanceTypes(groups)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(types["asg-1"], []string{"t3.medium"}) {
+		t.Errorf("instance types are not matched, expected [t3.medium], but returned %v", types)
+	}
+}
+
+func TestGetParameter(t *testing.T) {
+	f := New("us-east-1")
+	provider := &cloudaws.AWS{
+		SSM: f.SSM(),
+	}
+	name := "/aws/service/eks/optimized-ami/1.30/amazon-linux-2/recommended/image_id"
+
+	if _, err := provider.GetParameter(name); err == nil {
+		t.Errorf("missing parameter should return an error")
+	}
+	f.SetParameter(name, "ami-1")
+	value, err := provider.GetParameter(name)
+	if err != nil {
+		t.Fatalf("failed to get parameter: %v", err)
+	}
+	if value != "ami-1" {
+		t.Errorf("parameter is not matched, expected ami-1, but returned %s", value)
+	}
+}
+
+func countNodes(t *testing.T, c client.Client) int {
+	var list corev1.NodeList
+	if err := c.List(context.Background(), &list); err != nil {
+		t.Fatal(err)
+	}
+	return len(list.Items)
+}
@@ -0,0 +1,142 @@
+package fake
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+)
+
+type instanceRefresh struct {
+	id     string
+	status string
+	// targets are instances which were in service when the refresh started, and should be replaced.
+	targets  []string
+	replaced int
+	// waiting is the replaced instance which waits for the termination lifecycle hook.
+	waiting string
+}
+
+// StartInstanceRefresh starts to replace instances of the group. Only one refresh can run in a group at the same time.
+// With SkipMatching, instances which are launched from the latest version of the launch template are not replaced.
+func (a *AutoScaling) StartInstanceRefresh(in *autoscaling.StartInstanceRefreshInput) (*autoscaling.StartInstanceRefreshOutput, error) {
+	c := a.cloud
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	g, err := c.findGroup(aws.StringValue(in.AutoScalingGroupName))
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range g.refreshes {
+		if r.status == autoscaling.InstanceRefreshStatusInProgress {
+			return nil, awserr.New(autoscaling.ErrCodeInstanceRefreshInProgressFault, fmt.Sprintf("An Instance Refresh is already in progress and blocks the execution of this Instance Refresh for %s.", g.Name), nil)
+		}
+	}
+	skipMatching := in.Preferences != nil && aws.BoolValue(in.Preferences.SkipMatching)
+	c.refreshed++
+	r := &instanceRefresh{
+		id:     fmt.Sprintf("%08x-0000-0000-0000-000000000000", c.refreshed),
+		status: autoscaling.InstanceRefreshStatusInProgress,
+	}
+	for _, id := range g.instanceIDs {
+		if skipMatching && c.instances[id].launchTemplateVersion == g.latestLaunchTemplateVersion() {
+			continue
+		}
+		r.targets = append(r.targets, id)
+	}
+	if len(r.targets) == 0 {
+		r.status = autoscaling.InstanceRefreshStatusSuccessful
+	}
+	g.refreshes = append(g.refreshes, r)
+	return &autoscaling.StartInstanceRefreshOutput{
+		InstanceRefreshId: aws.String(r.id),
+	}, nil
+}
+
+// DescribeInstanceRefreshes returns refreshes of the group from the newest one.
+// Every call replaces the next instance of the refresh in progress, unless the previous instance waits for the lifecycle hook.
+func (a *AutoScaling) DescribeInstanceRefreshes(in *autoscaling.DescribeInstanceRefreshesInput) (*autoscaling.DescribeInstanceRefreshesOutput, error) {
+	c := a.cloud
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	g, err := c.findGroup(aws.StringValue(in.AutoScalingGroupName))
+	if err != nil {
+		return nil, err
+	}
+	ids := stringSet(in.InstanceRefreshIds)
+	out := &autoscaling.DescribeInstanceRefreshesOutput{}
+	for i := len(g.refreshes) - 1; i >= 0; i-- {
+		r := g.refreshes[i]
+		if r.status == autoscaling.InstanceRefreshStatusInProgress {
+			if err := c.stepRefresh(g, r); err != nil {
+				return nil, err
+			}
+		}
+		if len(ids) > 0 && !ids[r.id] {
+			continue
+		}
+		percentage := 100
+		if len(r.targets) > 0 {
+			percentage = r.replaced * 100 / len(r.targets)
+		}
+		out.InstanceRefreshes = append(out.InstanceRefreshes, &autoscaling.InstanceRefresh{
+			AutoScalingGroupName: aws.String(g.Name),
+			InstanceRefreshId:    aws.String(r.id),
+			Status:               aws.String(r.status),
+			PercentageComplete:   aws.Int64(int64(percentage)),
+			InstancesToUpdate:    aws.Int64(int64(len(r.targets) - r.replaced)),
+		})
+	}
+	return out, nil
+}
+
+// CompleteLifecycleAction terminates the instance which waits for the termination lifecycle hook.
+func (a *AutoScaling) CompleteLifecycleAction(in *autoscaling.CompleteLifecycleActionInput) (*autoscaling.CompleteLifecycleActionOutput, error) {
+	c := a.cloud
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	g, err := c.findGroup(aws.StringValue(in.AutoScalingGroupName))
+	if err != nil {
+		return nil, err
+	}
+	id := aws.StringValue(in.InstanceId)
+	if aws.StringValue(in.LifecycleHookName) != g.TerminationLifecycleHook || !contains(g.waitingIDs, id) {
+		return nil, validationErrorf("No active Lifecycle Action found with instance ID %s", id)
+	}
+	if err := c.terminate(c.instances[id]); err != nil {
+		return nil, err
+	}
+	return &autoscaling.CompleteLifecycleActionOutput{}, nil
+}
+
+// stepRefresh launches a new instance and terminates the next target, or lets it wait for the lifecycle hook.
+func (c *Cloud) stepRefresh(g *group, r *instanceRefresh) error {
+	if r.waiting != "" && contains(g.waitingIDs, r.waiting) {
+		return nil
+	}
+	r.waiting = ""
+	for r.replaced < len(r.targets) {
+		target := r.targets[r.replaced]
+		r.replaced++
+		// The target may have been terminated by others in the meantime.
+		if !contains(g.instanceIDs, target) {
+			continue
+		}
+		if err := c.launch(g); err != nil {
+			return err
+		}
+		if g.TerminationLifecycleHook != "" {
+			g.instanceIDs = remove(g.instanceIDs, target)
+			g.waitingIDs = append(g.waitingIDs, target)
+			r.waiting = target
+			return nil
+		}
+		return c.terminate(c.instances[target])
+	}
+	r.status = autoscaling.InstanceRefreshStatusSuccessful
+	return nil
+}
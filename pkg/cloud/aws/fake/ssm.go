@@ -0,0 +1,48 @@
+package fake
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
+)
+
+// SSM implements ssmiface.SSMAPI on the simulator. Methods which are not implemented panic.
+type SSM struct {
+	ssmiface.SSMAPI
+	cloud *Cloud
+}
+
+var _ ssmiface.SSMAPI = &SSM{}
+
+func (c *Cloud) SSM() *SSM {
+	return &SSM{cloud: c}
+}
+
+// SetParameter creates or overwrites the parameter, like publishing a new AMI.
+func (c *Cloud) SetParameter(name string, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.parameters[name] = value
+}
+
+func (s *SSM) GetParameter(in *ssm.GetParameterInput) (*ssm.GetParameterOutput, error) {
+	c := s.cloud
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	name := aws.StringValue(in.Name)
+	value, ok := c.parameters[name]
+	if !ok {
+		return nil, awserr.New(ssm.ErrCodeParameterNotFound, fmt.Sprintf("Parameter %s not found.", name), nil)
+	}
+	return &ssm.GetParameterOutput{
+		Parameter: &ssm.Parameter{
+			Name:  aws.String(name),
+			Type:  aws.String(ssm.ParameterTypeString),
+			Value: aws.String(value),
+		},
+	}, nil
+}
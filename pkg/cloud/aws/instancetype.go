@@ -0,0 +1,51 @@
+package aws
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+	"github.com/h3poteto/node-manager/pkg/cloud"
+)
+
+var _ cloud.InstanceTypeResolver = &AWS{}
+
+// LaunchInstanceTypes returns instance types which the AutoScalingGroups launch new instances with.
+// Overrides of a mixed instances policy are used when they have instance types, otherwise the type of the launch template or the launch configuration.
+// AutoScalingGroups which choose types by instance requirements are omitted.
+func (a *AWS) LaunchInstanceTypes(groups []operatorv1alpha1.AutoScalingGroup) (map[string][]string, error) {
+	asgs, err := a.DescribeAutoScalingGroups(groups)
+	if err != nil {
+		return nil, err
+	}
+	types := map[string][]string{}
+	for _, asg := range asgs {
+		name := aws.StringValue(asg.AutoScalingGroupName)
+		if overrides := overrideInstanceTypes(asg); len(overrides) > 0 {
+			types[name] = overrides
+			continue
+		}
+		source, err := a.currentLaunchSource(asg)
+		if err != nil {
+			return nil, err
+		}
+		if source == nil || source.instanceType == "" {
+			continue
+		}
+		types[name] = []string{source.instanceType}
+	}
+	return types, nil
+}
+
+func overrideInstanceTypes(asg *autoscaling.Group) []string {
+	if asg.MixedInstancesPolicy == nil || asg.MixedInstancesPolicy.LaunchTemplate == nil {
+		return nil
+	}
+	var types []string
+	for _, override := range asg.MixedInstancesPolicy.LaunchTemplate.Overrides {
+		if t := aws.StringValue(override.InstanceType); t != "" {
+			types = append(types, t)
+		}
+	}
+	return types
+}
@@ -0,0 +1,78 @@
+package aws
+
+import (
+	"log"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+)
+
+func TestLaunchInstanceTypes(t *testing.T) {
+	cases := []struct {
+		title    string
+		group    *autoscaling.Group
+		expected map[string][]string
+	}{
+		{
+			title: "Launch configuration",
+			group: &autoscaling.Group{
+				AutoScalingGroupName:    aws.String("asg-1"),
+				LaunchConfigurationName: aws.String("lc-1"),
+			},
+			expected: map[string][]string{"asg-1": {"m5.large"}},
+		},
+		{
+			title: "Overrides of mixed instances policy",
+			group: &autoscaling.Group{
+				AutoScalingGroupName: aws.String("asg-1"),
+				MixedInstancesPolicy: &autoscaling.MixedInstancesPolicy{
+					LaunchTemplate: &autoscaling.LaunchTemplate{
+						Overrides: []*autoscaling.LaunchTemplateOverrides{
+							{InstanceType: aws.String("m5.large")},
+							{InstanceType: aws.String("m5a.large")},
+						},
+					},
+				},
+			},
+			expected: map[string][]string{"asg-1": {"m5.large", "m5a.large"}},
+		},
+		{
+			title: "Neither launch template nor launch configuration",
+			group: &autoscaling.Group{
+				AutoScalingGroupName: aws.String("asg-1"),
+			},
+			expected: map[string][]string{},
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		a := &AWS{
+			Autoscaling: &mockedAutoScalingAPI{
+				Resp: autoscaling.DescribeAutoScalingGroupsOutput{
+					AutoScalingGroups: []*autoscaling.Group{c.group},
+				},
+				LaunchConfigurationsResp: autoscaling.DescribeLaunchConfigurationsOutput{
+					LaunchConfigurations: []*autoscaling.LaunchConfiguration{
+						{
+							LaunchConfigurationName: aws.String("lc-1"),
+							InstanceType:            aws.String("m5.large"),
+						},
+					},
+				},
+			},
+		}
+		types, err := a.LaunchInstanceTypes([]operatorv1alpha1.AutoScalingGroup{{Name: "asg-1"}})
+		if err != nil {
+			t.Errorf("CASE: %s : %v", c.title, err)
+			continue
+		}
+		if !reflect.DeepEqual(types, c.expected) {
+			t.Errorf("CASE: %s : instance types are not matched, expected %v, but returned %v", c.title, c.expected, types)
+		}
+	}
+}
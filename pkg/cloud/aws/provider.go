@@ -0,0 +1,100 @@
+package aws
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/klog/v2"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+	"github.com/h3poteto/node-manager/pkg/cloud"
+)
+
+var _ cloud.Provider = &AWS{}
+var _ cloud.NodeGroupDiscoverer = &AWS{}
+
+func (a *AWS) DescribeNodeGroups(groups []operatorv1alpha1.AutoScalingGroup) ([]cloud.NodeGroup, error) {
+	asgs, err := a.DescribeAutoScalingGroups(groups)
+	if err != nil {
+		return nil, err
+	}
+	var nodeGroups []cloud.NodeGroup
+	for _, asg := range asgs {
+		var instanceIDs []string
+		for _, instance := range asg.Instances {
+			instanceIDs = append(instanceIDs, aws.StringValue(instance.InstanceId))
+		}
+		nodeGroups = append(nodeGroups, cloud.NodeGroup{
+			Name:            aws.StringValue(asg.AutoScalingGroupName),
+			MinSize:         int(aws.Int64Value(asg.MinSize)),
+			MaxSize:         int(aws.Int64Value(asg.MaxSize)),
+			DesiredCapacity: int(aws.Int64Value(asg.DesiredCapacity)),
+			InstanceIDs:     instanceIDs,
+		})
+	}
+	return nodeGroups, nil
+}
+
+func (a *AWS) ScaleUpNodeGroups(groups []operatorv1alpha1.AutoScalingGroup, totalDesired int, currentNodesCount int) error {
+	return a.AddInstancesToAutoScalingGroups(groups, totalDesired, currentNodesCount)
+}
+
+func (a *AWS) ScaleDownNodeGroups(groups []operatorv1alpha1.AutoScalingGroup, totalDesired int, currentNodesCount int) error {
+	return a.DeleteInstancesToAutoScalingGroups(groups, totalDesired, currentNodesCount)
+}
+
+func (a *AWS) TerminateInstance(node *operatorv1alpha1.AWSNode) error {
+	return a.DeleteInstance(node)
+}
+
+func (a *AWS) DetachInstance(node *operatorv1alpha1.AWSNode) error {
+	asgName := node.AutoScalingGroupName
+	if asgName == "" {
+		groups, err := a.DescribeAutoScalingGroupNames([]string{node.InstanceID})
+		if err != nil {
+			return err
+		}
+		asgName = groups[node.InstanceID]
+	}
+	if asgName == "" {
+		klog.Warningf("instance %s does not belong to any AutoScalingGroup, so skip to detach it", node.InstanceID)
+		return nil
+	}
+	return a.DetachInstanceFromASG(node.InstanceID, asgName)
+}
+
+func (a *AWS) InstanceTerminated(node *operatorv1alpha1.AWSNode) (bool, error) {
+	instance, err := a.DescribeInstance(node)
+	if err != nil {
+		return false, err
+	}
+	klog.Infof("Instance %s state is %s", aws.StringValue(instance.InstanceId), aws.StringValue(instance.State.Name))
+	return aws.StringValue(instance.State.Name) == ec2.InstanceStateNameTerminated, nil
+}
+
+func (a *AWS) InstanceForNode(node *operatorv1alpha1.AWSNode) (*operatorv1alpha1.AWSNode, error) {
+	instance, err := a.DescribeInstance(node)
+	if err != nil {
+		return nil, err
+	}
+	n := ConvertInstanceToAWSNode(instance)
+	if n.AutoScalingGroupName == "" {
+		groups, err := a.DescribeAutoScalingGroupNames([]string{n.InstanceID})
+		if err != nil {
+			return nil, err
+		}
+		n.AutoScalingGroupName = groups[n.InstanceID]
+	}
+	if n.AutoScalingGroupName == "" {
+		klog.Warningf("instance %s does not belong to any AutoScalingGroup", n.InstanceID)
+		return nil, nil
+	}
+	return n, nil
+}
+
+func (a *AWS) DescribeInstances(instanceIDs []string) ([]operatorv1alpha1.AWSNode, error) {
+	return a.GetAWSNodes(aws.StringSlice(instanceIDs))
+}
+
+func (a *AWS) DiscoverNodeGroups(tags map[string]string) ([]operatorv1alpha1.AutoScalingGroup, error) {
+	return a.DiscoverAutoScalingGroups(tags)
+}
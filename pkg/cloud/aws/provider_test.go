@@ -0,0 +1,81 @@
+package aws
+
+import (
+	"log"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+
+	"github.com/h3poteto/node-manager/pkg/cloud"
+)
+
+func TestDescribeNodeGroups(t *testing.T) {
+	cases := []struct {
+		title              string
+		describeResponse   autoscaling.DescribeAutoScalingGroupsOutput
+		expectedNodeGroups []cloud.NodeGroup
+	}{
+		{
+			title: "Multiple groups",
+			describeResponse: autoscaling.DescribeAutoScalingGroupsOutput{
+				AutoScalingGroups: []*autoscaling.Group{
+					{
+						AutoScalingGroupName: aws.String("asg-a"),
+						MinSize:              aws.Int64(1),
+						MaxSize:              aws.Int64(5),
+						DesiredCapacity:      aws.Int64(2),
+						Instances: []*autoscaling.Instance{
+							{
+								InstanceId: aws.String("instance-1"),
+							},
+							{
+								InstanceId: aws.String("instance-2"),
+							},
+						},
+					},
+					{
+						AutoScalingGroupName: aws.String("asg-c"),
+						MinSize:              aws.Int64(0),
+						MaxSize:              aws.Int64(3),
+						DesiredCapacity:      aws.Int64(0),
+					},
+				},
+			},
+			expectedNodeGroups: []cloud.NodeGroup{
+				{
+					Name:            "asg-a",
+					MinSize:         1,
+					MaxSize:         5,
+					DesiredCapacity: 2,
+					InstanceIDs:     []string{"instance-1", "instance-2"},
+				},
+				{
+					Name:            "asg-c",
+					MinSize:         0,
+					MaxSize:         3,
+					DesiredCapacity: 0,
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		a := &AWS{
+			Autoscaling: &mockedAutoScalingAPI{
+				Resp: c.describeResponse,
+			},
+		}
+		groups, err := a.DescribeNodeGroups([]operatorv1alpha1.AutoScalingGroup{{Name: "asg-a"}, {Name: "asg-c"}})
+		if err != nil {
+			t.Errorf("CASE: %s : %v", c.title, err)
+			continue
+		}
+		if !reflect.DeepEqual(groups, c.expectedNodeGroups) {
+			t.Errorf("CASE: %s : node groups are not matched, expected %#v, but got %#v", c.title, c.expectedNodeGroups, groups)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package aws
+
+import (
+	"net/url"
+	"strings"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+)
+
+const providerIDScheme = "aws"
+
+// ParseProviderID parses spec.providerID of a Kubernetes node which is set by the AWS cloud provider, for example aws:///us-east-1a/i-0123456789abcdef0,
+// and returns the availability zone and the EC2 instance ID.
+func ParseProviderID(providerID string) (string, string, error) {
+	u, err := url.Parse(providerID)
+	if err != nil {
+		return "", "", NewInvalidProviderIDErrorf("could not parse providerID %s: %v", providerID, err)
+	}
+	if u.Scheme != providerIDScheme {
+		return "", "", NewInvalidProviderIDErrorf("providerID %s is not an AWS providerID", providerID)
+	}
+	// aws:///az/instance-id has an empty host, and aws://az/instance-id is also accepted by the AWS cloud provider.
+	segments := strings.Split(strings.Trim(u.Host+u.Path, "/"), "/")
+	instanceID := segments[len(segments)-1]
+	if !strings.HasPrefix(instanceID, "i-") {
+		return "", "", NewInvalidProviderIDErrorf("providerID %s does not have an EC2 instance ID", providerID)
+	}
+	az := ""
+	if len(segments) > 1 {
+		az = segments[len(segments)-2]
+	}
+	return az, instanceID, nil
+}
+
+// instanceIDOfNode returns the EC2 instance ID of the node.
+// It returns empty when the instance ID is unknown, and the instance should be found by the node name instead.
+func instanceIDOfNode(node *operatorv1alpha1.AWSNode) string {
+	if node.InstanceID != "" {
+		return node.InstanceID
+	}
+	if node.ProviderID == "" {
+		return ""
+	}
+	_, instanceID, err := ParseProviderID(node.ProviderID)
+	if err != nil {
+		return ""
+	}
+	return instanceID
+}
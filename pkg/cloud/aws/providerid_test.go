@@ -0,0 +1,71 @@
+package aws
+
+import (
+	"log"
+	"testing"
+)
+
+func TestParseProviderID(t *testing.T) {
+	cases := []struct {
+		title              string
+		providerID         string
+		expectedAZ         string
+		expectedInstanceID string
+		expectedErrorNil   bool
+	}{
+		{
+			title:              "ProviderID with availability zone",
+			providerID:         "aws:///us-east-1a/i-0123456789abcdef0",
+			expectedAZ:         "us-east-1a",
+			expectedInstanceID: "i-0123456789abcdef0",
+			expectedErrorNil:   true,
+		},
+		{
+			title:              "ProviderID with availability zone in host",
+			providerID:         "aws://us-east-1a/i-0123456789abcdef0",
+			expectedAZ:         "us-east-1a",
+			expectedInstanceID: "i-0123456789abcdef0",
+			expectedErrorNil:   true,
+		},
+		{
+			title:              "ProviderID without availability zone",
+			providerID:         "aws:////i-0123456789abcdef0",
+			expectedAZ:         "",
+			expectedInstanceID: "i-0123456789abcdef0",
+			expectedErrorNil:   true,
+		},
+		{
+			title:            "Fargate node",
+			providerID:       "aws:///us-east-1a/0123456789abcdef/fargate-ip-192-168-0-1.ec2.internal",
+			expectedErrorNil: false,
+		},
+		{
+			title:            "Other cloud provider",
+			providerID:       "gce://my-project/us-central1-a/instance-1",
+			expectedErrorNil: false,
+		},
+		{
+			title:            "Empty",
+			providerID:       "",
+			expectedErrorNil: false,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		az, instanceID, err := ParseProviderID(c.providerID)
+		if !c.expectedErrorNil {
+			if err == nil {
+				t.Errorf("CASE: %s : error should be returned", c.title)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("CASE: %s : %v", c.title, err)
+			continue
+		}
+		if az != c.expectedAZ || instanceID != c.expectedInstanceID {
+			t.Errorf("CASE: %s : expected %s and %s, but returned %s and %s", c.title, c.expectedAZ, c.expectedInstanceID, az, instanceID)
+		}
+	}
+}
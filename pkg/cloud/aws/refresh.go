@@ -0,0 +1,131 @@
+package aws
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"k8s.io/klog/v2"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+	"github.com/h3poteto/node-manager/pkg/cloud"
+)
+
+var _ cloud.InstanceRefresher = &AWS{}
+
+// StartInstanceRefresh starts a rolling Instance Refresh of the AutoScalingGroup.
+// An Instance Refresh which is already running is adopted, because only one refresh can run in an AutoScalingGroup,
+// and the controller may fail to record the ID after starting it.
+func (a *AWS) StartInstanceRefresh(group string, preferences *operatorv1alpha1.InstanceRefreshPreferences) (string, error) {
+	input := &autoscaling.StartInstanceRefreshInput{
+		AutoScalingGroupName: aws.String(group),
+		Strategy:             aws.String(autoscaling.RefreshStrategyRolling),
+		Preferences:          refreshPreferences(preferences),
+	}
+	out, err := a.Autoscaling.StartInstanceRefresh(input)
+	if err == nil {
+		return aws.StringValue(out.InstanceRefreshId), nil
+	}
+	if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != autoscaling.ErrCodeInstanceRefreshInProgressFault {
+		klog.Errorf("failed to start instance refresh of %s: %v", group, err)
+		return "", err
+	}
+	refreshes, err := a.Autoscaling.DescribeInstanceRefreshes(&autoscaling.DescribeInstanceRefreshesInput{
+		AutoScalingGroupName: aws.String(group),
+	})
+	if err != nil {
+		klog.Errorf("failed to describe instance refreshes of %s: %v", group, err)
+		return "", err
+	}
+	for _, r := range refreshes.InstanceRefreshes {
+		if !instanceRefreshFinished(aws.StringValue(r.Status)) {
+			klog.Infof("instance refresh %s is already running in %s", aws.StringValue(r.InstanceRefreshId), group)
+			return aws.StringValue(r.InstanceRefreshId), nil
+		}
+	}
+	return "", fmt.Errorf("instance refresh is in progress in %s, but could not find it", group)
+}
+
+func (a *AWS) DescribeInstanceRefresh(group string, id string) (*cloud.InstanceRefresh, error) {
+	out, err := a.Autoscaling.DescribeInstanceRefreshes(&autoscaling.DescribeInstanceRefreshesInput{
+		AutoScalingGroupName: aws.String(group),
+		InstanceRefreshIds:   []*string{aws.String(id)},
+	})
+	if err != nil {
+		klog.Errorf("failed to describe instance refresh %s of %s: %v", id, group, err)
+		return nil, err
+	}
+	if len(out.InstanceRefreshes) == 0 {
+		return nil, fmt.Errorf("instance refresh %s does not exist in %s", id, group)
+	}
+	r := out.InstanceRefreshes[0]
+	status := aws.StringValue(r.Status)
+	return &cloud.InstanceRefresh{
+		ID:                 aws.StringValue(r.InstanceRefreshId),
+		Status:             status,
+		StatusReason:       aws.StringValue(r.StatusReason),
+		PercentageComplete: int(aws.Int64Value(r.PercentageComplete)),
+		InstancesToUpdate:  int(aws.Int64Value(r.InstancesToUpdate)),
+		Finished:           instanceRefreshFinished(status),
+		Succeeded:          status == autoscaling.InstanceRefreshStatusSuccessful,
+	}, nil
+}
+
+func (a *AWS) TerminationWaitingInstances(group string) ([]string, error) {
+	asgs, err := a.DescribeAutoScalingGroups([]operatorv1alpha1.AutoScalingGroup{{Name: group}})
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, asg := range asgs {
+		for _, instance := range asg.Instances {
+			if aws.StringValue(instance.LifecycleState) == autoscaling.LifecycleStateTerminatingWait {
+				ids = append(ids, aws.StringValue(instance.InstanceId))
+			}
+		}
+	}
+	return ids, nil
+}
+
+func (a *AWS) CompleteTerminationLifecycleAction(group string, hook string, instanceID string) error {
+	_, err := a.Autoscaling.CompleteLifecycleAction(&autoscaling.CompleteLifecycleActionInput{
+		AutoScalingGroupName:  aws.String(group),
+		LifecycleHookName:     aws.String(hook),
+		InstanceId:            aws.String(instanceID),
+		LifecycleActionResult: aws.String("CONTINUE"),
+	})
+	if err != nil {
+		klog.Errorf("failed to complete lifecycle action %s of %s: %v", hook, instanceID, err)
+		return err
+	}
+	return nil
+}
+
+func refreshPreferences(preferences *operatorv1alpha1.InstanceRefreshPreferences) *autoscaling.RefreshPreferences {
+	if preferences == nil {
+		return nil
+	}
+	p := &autoscaling.RefreshPreferences{
+		MinHealthyPercentage: preferences.MinHealthyPercentage,
+		InstanceWarmup:       preferences.InstanceWarmupSeconds,
+		CheckpointDelay:      preferences.CheckpointDelaySeconds,
+		SkipMatching:         aws.Bool(preferences.SkipMatching),
+	}
+	if len(preferences.CheckpointPercentages) > 0 {
+		p.CheckpointPercentages = aws.Int64Slice(preferences.CheckpointPercentages)
+	}
+	return p
+}
+
+func instanceRefreshFinished(status string) bool {
+	switch status {
+	case autoscaling.InstanceRefreshStatusSuccessful,
+		autoscaling.InstanceRefreshStatusFailed,
+		autoscaling.InstanceRefreshStatusCancelled,
+		autoscaling.InstanceRefreshStatusRollbackFailed,
+		autoscaling.InstanceRefreshStatusRollbackSuccessful:
+		return true
+	}
+	return false
+}
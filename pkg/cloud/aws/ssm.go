@@ -0,0 +1,22 @@
+package aws
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"k8s.io/klog/v2"
+
+	"github.com/h3poteto/node-manager/pkg/cloud"
+)
+
+var _ cloud.ParameterReader = &AWS{}
+
+func (a *AWS) GetParameter(name string) (string, error) {
+	out, err := a.SSM.GetParameter(&ssm.GetParameterInput{
+		Name: aws.String(name),
+	})
+	if err != nil {
+		klog.Errorf("failed to get parameter %s: %v", name, err)
+		return "", err
+	}
+	return aws.StringValue(out.Parameter.Value), nil
+}
@@ -0,0 +1,133 @@
+package azure
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	// DefaultEndpoint is the endpoint of Azure Resource Manager.
+	DefaultEndpoint = "https://management.azure.com/"
+	// APIVersion is the version of Microsoft.Compute API.
+	APIVersion = "2023-09-01"
+)
+
+// Azure operates Virtual Machine Scale Sets through Azure Resource Manager API.
+type Azure struct {
+	Client         *http.Client
+	Endpoint       string
+	SubscriptionID string
+	ResourceGroup  string
+}
+
+func New(client *http.Client, endpoint, subscriptionID, resourceGroup string) *Azure {
+	if !strings.HasSuffix(endpoint, "/") {
+		endpoint += "/"
+	}
+	return &Azure{
+		Client:         client,
+		Endpoint:       endpoint,
+		SubscriptionID: subscriptionID,
+		ResourceGroup:  resourceGroup,
+	}
+}
+
+// scaleSetURL returns the URL of the scale set, or the resource under the scale set.
+func (a *Azure) scaleSetURL(name string, resource ...string) string {
+	u := fmt.Sprintf("%ssubscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/virtualMachineScaleSets", a.Endpoint, a.SubscriptionID, a.ResourceGroup)
+	if name != "" {
+		u += "/" + name
+	}
+	for _, r := range resource {
+		u += "/" + r
+	}
+	return u + "?api-version=" + APIVersion
+}
+
+func (a *Azure) do(method, url string, in interface{}, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	res, err := a.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode >= 300 {
+		return newAPIError(res.StatusCode, b)
+	}
+	if out == nil || len(b) == 0 {
+		return nil
+	}
+	return json.Unmarshal(b, out)
+}
+
+// APIError is an error response of Azure Resource Manager.
+type APIError struct {
+	StatusCode int
+	ErrorCode  string
+	Msg        string
+}
+
+func newAPIError(statusCode int, body []byte) *APIError {
+	var res struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		ErrorCode:  http.StatusText(statusCode),
+		Msg:        string(body),
+	}
+	if err := json.Unmarshal(body, &res); err != nil {
+		return apiErr
+	}
+	if res.Error.Code != "" {
+		apiErr.ErrorCode = res.Error.Code
+	}
+	if res.Error.Message != "" {
+		apiErr.Msg = res.Error.Message
+	}
+	return apiErr
+}
+
+func (err *APIError) Error() string {
+	return fmt.Sprintf("%s: %s", err.ErrorCode, err.Msg)
+}
+
+// Code returns the error code, which is reported in CloudAPIError condition.
+func (err *APIError) Code() string {
+	return err.ErrorCode
+}
+
+func (err *APIError) Message() string {
+	return err.Msg
+}
+
+func isNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
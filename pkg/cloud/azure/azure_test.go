@@ -0,0 +1,264 @@
+package azure
+
+import (
+	"errors"
+	"log"
+	"reflect"
+	"testing"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+)
+
+func TestDescribeNodeGroups(t *testing.T) {
+	fake := newFakeARM()
+	fake.pageSize = 2
+	fake.addScaleSet("vmss-a", 3, "0", "1", "2")
+	fake.addScaleSet("vmss-b", 0)
+	a, closeServer := fake.start()
+	defer closeServer()
+
+	groups, err := a.DescribeNodeGroups([]operatorv1alpha1.AutoScalingGroup{{Name: "vmss-a"}, {Name: "vmss-b"}})
+	if err != nil {
+		t.Fatalf("failed to describe node groups: %v", err)
+	}
+	if len(groups) != 2 {
+		t.Fatalf("node groups should be 2, but returned %d", len(groups))
+	}
+	if !reflect.DeepEqual(groups[0].InstanceIDs, []string{"vmss-a_0", "vmss-a_1", "vmss-a_2"}) {
+		t.Errorf("all pages of virtual machines should be listed, but returned %v", groups[0].InstanceIDs)
+	}
+	if groups[0].DesiredCapacity != 3 || groups[1].DesiredCapacity != 0 {
+		t.Errorf("desired capacity should be capacity of scale sets, but returned %d and %d", groups[0].DesiredCapacity, groups[1].DesiredCapacity)
+	}
+
+	_, err = a.DescribeNodeGroups([]operatorv1alpha1.AutoScalingGroup{{Name: "unknown"}})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code() != "ResourceNotFound" {
+		t.Errorf("unknown scale set should return ResourceNotFound APIError, but returned %v", err)
+	}
+}
+
+func TestScaleNodeGroups(t *testing.T) {
+	cases := []struct {
+		title            string
+		scaleUp          bool
+		totalDesired     int
+		currentNodes     int
+		expectedCapacity map[string][]int64
+		expectedErrorNil bool
+	}{
+		{
+			title:            "Scale up across multiple scale sets",
+			scaleUp:          true,
+			totalDesired:     5,
+			currentNodes:     4,
+			expectedCapacity: map[string][]int64{"vmss-a": {3}, "vmss-b": {2}},
+			expectedErrorNil: true,
+		},
+		{
+			title:            "Scale down across multiple scale sets",
+			scaleUp:          false,
+			totalDesired:     3,
+			currentNodes:     4,
+			expectedCapacity: map[string][]int64{"vmss-a": {1}, "vmss-b": {2}},
+			expectedErrorNil: true,
+		},
+		{
+			title:            "Desired does not exceed current",
+			scaleUp:          true,
+			totalDesired:     4,
+			currentNodes:     4,
+			expectedCapacity: map[string][]int64{},
+			expectedErrorNil: false,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		fake := newFakeARM()
+		fake.addScaleSet("vmss-a", 2, "0", "1")
+		fake.addScaleSet("vmss-b", 2, "0", "1")
+		a, closeServer := fake.start()
+
+		groups := []operatorv1alpha1.AutoScalingGroup{{Name: "vmss-a"}, {Name: "vmss-b"}}
+		var err error
+		if c.scaleUp {
+			err = a.ScaleUpNodeGroups(groups, c.totalDesired, c.currentNodes)
+		} else {
+			err = a.ScaleDownNodeGroups(groups, c.totalDesired, c.currentNodes)
+		}
+		closeServer()
+		if c.expectedErrorNil && err != nil {
+			t.Errorf("CASE: %s : %v", c.title, err)
+		}
+		if !c.expectedErrorNil && err == nil {
+			t.Errorf("CASE: %s : error should be returned", c.title)
+		}
+		if !reflect.DeepEqual(fake.updatedCapacity, c.expectedCapacity) {
+			t.Errorf("CASE: %s : updated capacity is not matched, expected %v, but got %v", c.title, c.expectedCapacity, fake.updatedCapacity)
+		}
+	}
+}
+
+func TestTerminateInstance(t *testing.T) {
+	fake := newFakeARM()
+	fake.addScaleSet("vmss-a", 2, "0", "1")
+	a, closeServer := fake.start()
+	defer closeServer()
+
+	node := &operatorv1alpha1.AWSNode{Name: "vmss-a000000", InstanceID: "vmss-a_0", AutoScalingGroupName: "vmss-a"}
+	terminated, err := a.InstanceTerminated(node)
+	if err != nil || terminated {
+		t.Errorf("running instance should not be terminated, but returned %v, %v", terminated, err)
+	}
+	if err := a.TerminateInstance(node); err != nil {
+		t.Fatalf("failed to terminate instance: %v", err)
+	}
+	if !reflect.DeepEqual(fake.deleted, []string{"vmss-a_0"}) {
+		t.Errorf("instance should be deleted, but deleted %v", fake.deleted)
+	}
+	if fake.scaleSets["vmss-a"].capacity != 2 {
+		t.Errorf("capacity should be restored to create a new instance, but it is %d", fake.scaleSets["vmss-a"].capacity)
+	}
+	terminated, err = a.InstanceTerminated(node)
+	if err != nil || !terminated {
+		t.Errorf("deleted instance should be terminated, but returned %v, %v", terminated, err)
+	}
+}
+
+func TestTerminateInstanceRestoreFailure(t *testing.T) {
+	fake := newFakeARM()
+	fake.addScaleSet("vmss-a", 2, "0", "1")
+	fake.conflictUpdate = true
+	a, closeServer := fake.start()
+	defer closeServer()
+
+	node := &operatorv1alpha1.AWSNode{Name: "vmss-a000000", InstanceID: "vmss-a_0", AutoScalingGroupName: "vmss-a"}
+	if err := a.TerminateInstance(node); err == nil {
+		t.Errorf("failure of the restore should be returned")
+	}
+	if fake.scaleSets["vmss-a"].capacity != 1 {
+		t.Errorf("capacity should be decremented by the delete, but it is %d", fake.scaleSets["vmss-a"].capacity)
+	}
+
+	fake.conflictUpdate = false
+	if err := a.TerminateInstance(node); err != nil {
+		t.Fatalf("failed to terminate deleted instance: %v", err)
+	}
+	if !reflect.DeepEqual(fake.deleted, []string{"vmss-a_0"}) {
+		t.Errorf("deleted instance should not be deleted again, but deleted %v", fake.deleted)
+	}
+	if err := a.RestoreCapacity([]operatorv1alpha1.AutoScalingGroup{{Name: "vmss-a"}}, 2); err != nil {
+		t.Fatalf("failed to restore capacity: %v", err)
+	}
+	if fake.scaleSets["vmss-a"].capacity != 2 {
+		t.Errorf("capacity should be restored, but it is %d", fake.scaleSets["vmss-a"].capacity)
+	}
+}
+
+func TestRestoreCapacity(t *testing.T) {
+	cases := []struct {
+		title            string
+		total            int
+		expectedCapacity map[string][]int64
+	}{
+		{
+			title:            "Capacity reaches total",
+			total:            3,
+			expectedCapacity: map[string][]int64{},
+		},
+		{
+			title:            "Capacity is decreased by a delete",
+			total:            4,
+			expectedCapacity: map[string][]int64{"vmss-a": {2}},
+		},
+		{
+			title:            "Capacity is decreased by deletes in multiple scale sets",
+			total:            6,
+			expectedCapacity: map[string][]int64{"vmss-a": {3}, "vmss-b": {3}},
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		fake := newFakeARM()
+		fake.addScaleSet("vmss-a", 1, "0")
+		fake.addScaleSet("vmss-b", 2, "0", "1")
+		a, closeServer := fake.start()
+
+		err := a.RestoreCapacity([]operatorv1alpha1.AutoScalingGroup{{Name: "vmss-a"}, {Name: "vmss-b"}}, c.total)
+		closeServer()
+		if err != nil {
+			t.Errorf("CASE: %s : %v", c.title, err)
+		}
+		if !reflect.DeepEqual(fake.updatedCapacity, c.expectedCapacity) {
+			t.Errorf("CASE: %s : updated capacity is not matched, expected %v, but got %v", c.title, c.expectedCapacity, fake.updatedCapacity)
+		}
+	}
+}
+
+func TestDetachInstance(t *testing.T) {
+	fake := newFakeARM()
+	fake.addScaleSet("vmss-a", 2, "0", "1")
+	a, closeServer := fake.start()
+	defer closeServer()
+
+	if err := a.DetachInstance(&operatorv1alpha1.AWSNode{InstanceID: "vmss-a_1", AutoScalingGroupName: "vmss-a"}); err != nil {
+		t.Fatalf("failed to detach instance: %v", err)
+	}
+	if fake.scaleSets["vmss-a"].capacity != 1 {
+		t.Errorf("capacity should be decremented, but it is %d", fake.scaleSets["vmss-a"].capacity)
+	}
+	if len(fake.updatedCapacity) != 0 {
+		t.Errorf("capacity should not be restored, but updated %v", fake.updatedCapacity)
+	}
+
+	if err := a.TerminateInstance(&operatorv1alpha1.AWSNode{InstanceID: "vmss-a_1", AutoScalingGroupName: "vmss-a"}); err != nil {
+		t.Fatalf("failed to terminate detached instance: %v", err)
+	}
+	if !reflect.DeepEqual(fake.deleted, []string{"vmss-a_1"}) {
+		t.Errorf("detached instance should not be deleted again, but deleted %v", fake.deleted)
+	}
+	if fake.scaleSets["vmss-a"].capacity != 1 {
+		t.Errorf("capacity of detached instance should not be restored, but it is %d", fake.scaleSets["vmss-a"].capacity)
+	}
+
+	err := a.DetachInstance(&operatorv1alpha1.AWSNode{InstanceID: "i-0123456789"})
+	var invalidErr *InvalidInstanceIDError
+	if !errors.As(err, &invalidErr) {
+		t.Errorf("invalid instance ID should return InvalidInstanceIDError, but returned %v", err)
+	}
+}
+
+func TestInstanceForNode(t *testing.T) {
+	fake := newFakeARM()
+	fake.addScaleSet("vmss-a", 2, "0", "1")
+	fake.addScaleSet("vmss-b", 1, "3")
+	a, closeServer := fake.start()
+	defer closeServer()
+
+	n, err := a.InstanceForNode(&operatorv1alpha1.AWSNode{Name: "vmss-b000003"})
+	if err != nil {
+		t.Fatalf("failed to find instance: %v", err)
+	}
+	expected := "vmss-b_3"
+	if n == nil || n.InstanceID != expected || n.AutoScalingGroupName != "vmss-b" || n.AvailabilityZone != "eastus-1" || n.InstanceType != "Standard_D2s_v3" {
+		t.Fatalf("instance is not converted correctly: %#v", n)
+	}
+	if n.CreationTimestamp.IsZero() {
+		t.Errorf("creation timestamp should be parsed")
+	}
+
+	n, err = a.InstanceForNode(&operatorv1alpha1.AWSNode{Name: "unknown-node"})
+	if err != nil || n != nil {
+		t.Errorf("node which is not in scale sets should be ignored, but returned %#v, %v", n, err)
+	}
+
+	nodes, err := a.DescribeInstances([]string{"vmss-a_1", "vmss-b_3"})
+	if err != nil {
+		t.Fatalf("failed to describe instances: %v", err)
+	}
+	if len(nodes) != 2 || nodes[0].Name != "vmss-a000001" || nodes[1].Name != "vmss-b000003" {
+		t.Errorf("instances are not matched: %#v", nodes)
+	}
+}
@@ -0,0 +1,68 @@
+package azure
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"golang.org/x/oauth2"
+	"k8s.io/klog/v2"
+
+	"github.com/h3poteto/node-manager/pkg/cloud"
+)
+
+// ClientCache keeps clients of Azure for each subscription and resource group, so a token source is not created on every reconcile.
+// All clients share an HTTP client, because access tokens are issued for the same managed identity of the host.
+type ClientCache struct {
+	mu      sync.Mutex
+	client  *http.Client
+	clients map[clientKey]*Azure
+	// newTokenSource is replaced in tests.
+	newTokenSource func() oauth2.TokenSource
+}
+
+// clientKey identifies a location of Virtual Machine Scale Sets.
+type clientKey struct {
+	subscriptionID string
+	resourceGroup  string
+}
+
+func NewClientCache() *ClientCache {
+	return &ClientCache{
+		clients: map[clientKey]*Azure{},
+		newTokenSource: func() oauth2.TokenSource {
+			return NewManagedIdentityTokenSource(DefaultEndpoint)
+		},
+	}
+}
+
+// NewProvider is a cloud.Factory for Azure, which returns the cached client for the config.
+// Access tokens are issued by the managed identity of the host, so the controller has to run on Azure VMs or AKS.
+func (c *ClientCache) NewProvider(config cloud.Config) (cloud.Provider, error) {
+	return c.Client(config)
+}
+
+// Client returns the client for the subscription and the resource group of the config, and creates it at the first time.
+func (c *ClientCache) Client(config cloud.Config) (*Azure, error) {
+	if config.SubscriptionID == "" || config.ResourceGroup == "" {
+		err := errors.New("subscriptionID and resourceGroup are required for azure")
+		klog.Error(err)
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := clientKey{
+		subscriptionID: config.SubscriptionID,
+		resourceGroup:  config.ResourceGroup,
+	}
+	if client, ok := c.clients[key]; ok {
+		return client, nil
+	}
+	if c.client == nil {
+		c.client = oauth2.NewClient(context.Background(), oauth2.ReuseTokenSource(nil, c.newTokenSource()))
+	}
+	client := New(c.client, DefaultEndpoint, config.SubscriptionID, config.ResourceGroup)
+	c.clients[key] = client
+	return client, nil
+}
@@ -0,0 +1,46 @@
+package azure
+
+import (
+	"testing"
+
+	"golang.org/x/oauth2"
+
+	"github.com/h3poteto/node-manager/pkg/cloud"
+)
+
+func TestClientCache(t *testing.T) {
+	sources := 0
+	c := NewClientCache()
+	c.newTokenSource = func() oauth2.TokenSource {
+		sources++
+		return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"})
+	}
+
+	if _, err := c.Client(cloud.Config{SubscriptionID: "sub"}); err == nil {
+		t.Fatalf("subscriptionID and resourceGroup should be required")
+	}
+	a, err := c.Client(cloud.Config{SubscriptionID: "sub", ResourceGroup: "rg-a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cached, err := c.Client(cloud.Config{SubscriptionID: "sub", ResourceGroup: "rg-a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != cached {
+		t.Errorf("client should be reused for the same resource group")
+	}
+	b, err := c.Client(cloud.Config{SubscriptionID: "sub", ResourceGroup: "rg-b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a == b {
+		t.Errorf("client should be created for each resource group")
+	}
+	if a.Client != b.Client {
+		t.Errorf("http client should be shared by clients")
+	}
+	if sources != 1 {
+		t.Errorf("token source should be shared by clients, but created %d times", sources)
+	}
+}
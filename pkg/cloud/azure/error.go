@@ -0,0 +1,17 @@
+package azure
+
+import "fmt"
+
+type InvalidInstanceIDError struct {
+	Msg string
+}
+
+func NewInvalidInstanceIDErrorf(format string, a ...interface{}) *InvalidInstanceIDError {
+	return &InvalidInstanceIDError{
+		Msg: fmt.Sprintf(format, a...),
+	}
+}
+
+func (err *InvalidInstanceIDError) Error() string {
+	return err.Msg
+}
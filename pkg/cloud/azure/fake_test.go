@@ -0,0 +1,183 @@
+package azure
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	testSubscriptionID = "00000000-0000-0000-0000-000000000000"
+	testResourceGroup  = "test-rg"
+)
+
+type fakeScaleSet struct {
+	capacity int64
+	vms      []*virtualMachine
+}
+
+// fakeARM is an in-process Azure Resource Manager which serves a part of Microsoft.Compute/virtualMachineScaleSets API.
+type fakeARM struct {
+	mu        sync.Mutex
+	server    *httptest.Server
+	scaleSets map[string]*fakeScaleSet
+	// pageSize is the number of virtual machines in a page of list.
+	pageSize int
+	// updatedCapacity records capacities which are requested to update.
+	updatedCapacity map[string][]int64
+	deleted         []string
+	// conflictUpdate makes update of capacity fail with Conflict, like a scale set which is still operating.
+	conflictUpdate bool
+}
+
+func newFakeARM() *fakeARM {
+	return &fakeARM{
+		scaleSets:       map[string]*fakeScaleSet{},
+		pageSize:        1000,
+		updatedCapacity: map[string][]int64{},
+	}
+}
+
+func (f *fakeARM) addScaleSet(name string, capacity int64, instanceIDs ...string) {
+	ss := &fakeScaleSet{
+		capacity: capacity,
+	}
+	for _, id := range instanceIDs {
+		n, _ := strconv.Atoi(id)
+		ss.vms = append(ss.vms, &virtualMachine{
+			Name:       name + "_" + id,
+			InstanceID: id,
+			Location:   "eastus",
+			Zones:      []string{"1"},
+			Sku: sku{
+				Name: "Standard_D2s_v3",
+			},
+			Properties: virtualMachineProperties{
+				ProvisioningState: "Succeeded",
+				TimeCreated:       "2021-01-02T03:04:05.1234567+00:00",
+				OSProfile: osProfile{
+					ComputerName: fmt.Sprintf("%s%06d", strings.ToUpper(name), n),
+				},
+			},
+		})
+	}
+	f.scaleSets[name] = ss
+}
+
+// start starts the fake server, and returns Azure which uses it.
+func (f *fakeARM) start() (*Azure, func()) {
+	f.server = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	return New(f.server.Client(), f.server.URL, testSubscriptionID, testResourceGroup), f.server.Close
+}
+
+func (f *fakeARM) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Query().Get("api-version") != APIVersion {
+		writeError(w, http.StatusBadRequest, "InvalidApiVersionParameter", "api-version is not supported")
+		return
+	}
+	prefix := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/virtualMachineScaleSets", testSubscriptionID, testResourceGroup)
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		writeError(w, http.StatusNotFound, "ResourceGroupNotFound", "unknown path "+r.URL.Path)
+		return
+	}
+	rest := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+	if rest == "" && r.Method == http.MethodGet {
+		list := scaleSetList{}
+		for name, ss := range f.scaleSets {
+			capacity := ss.capacity
+			list.Value = append(list.Value, scaleSet{Name: name, Sku: sku{Capacity: &capacity}})
+		}
+		writeJSON(w, list)
+		return
+	}
+	parts := strings.Split(rest, "/")
+	ss, ok := f.scaleSets[parts[0]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "ResourceNotFound", "scale set "+parts[0]+" was not found")
+		return
+	}
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		capacity := ss.capacity
+		writeJSON(w, scaleSet{Name: parts[0], Sku: sku{Name: "Standard_D2s_v3", Capacity: &capacity}})
+	case len(parts) == 1 && r.Method == http.MethodPatch:
+		if f.conflictUpdate {
+			writeError(w, http.StatusConflict, "OperationNotAllowed", "operation is in progress on scale set "+parts[0])
+			return
+		}
+		var in scaleSetUpdate
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil || in.Sku.Capacity == nil {
+			writeError(w, http.StatusBadRequest, "InvalidRequestContent", "capacity is required")
+			return
+		}
+		ss.capacity = *in.Sku.Capacity
+		f.updatedCapacity[parts[0]] = append(f.updatedCapacity[parts[0]], ss.capacity)
+		w.WriteHeader(http.StatusAccepted)
+	case len(parts) == 2 && parts[1] == "delete" && r.Method == http.MethodPost:
+		var in instanceIDs
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			writeError(w, http.StatusBadRequest, "InvalidRequestContent", err.Error())
+			return
+		}
+		for _, id := range in.InstanceIDs {
+			for i := range ss.vms {
+				if ss.vms[i].InstanceID == id {
+					f.deleted = append(f.deleted, ss.vms[i].Name)
+					ss.vms = append(ss.vms[:i], ss.vms[i+1:]...)
+					ss.capacity--
+					break
+				}
+			}
+		}
+		w.WriteHeader(http.StatusAccepted)
+	case len(parts) == 2 && parts[1] == "virtualMachines" && r.Method == http.MethodGet:
+		start := 0
+		if token := r.URL.Query().Get("skipToken"); token != "" {
+			start, _ = strconv.Atoi(token)
+		}
+		end := start + f.pageSize
+		list := virtualMachineList{}
+		if end < len(ss.vms) {
+			list.NextLink = fmt.Sprintf("%s%s/%s/virtualMachines?api-version=%s&skipToken=%d", f.server.URL, prefix, parts[0], APIVersion, end)
+		} else {
+			end = len(ss.vms)
+		}
+		for _, vm := range ss.vms[start:end] {
+			list.Value = append(list.Value, *vm)
+		}
+		writeJSON(w, list)
+	case len(parts) == 3 && parts[1] == "virtualMachines" && r.Method == http.MethodGet:
+		for _, vm := range ss.vms {
+			if vm.InstanceID == parts[2] {
+				writeJSON(w, vm)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "NotFound", "virtual machine "+parts[2]+" was not found")
+	default:
+		writeError(w, http.StatusNotFound, "NotFound", "unknown path "+r.URL.Path)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": map[string]string{
+			"code":    code,
+			"message": message,
+		},
+	})
+}
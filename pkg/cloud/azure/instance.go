@@ -0,0 +1,150 @@
+package azure
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+)
+
+type virtualMachine struct {
+	// Name is <scale set name>_<instance ID>.
+	Name       string                   `json:"name"`
+	InstanceID string                   `json:"instanceId"`
+	Location   string                   `json:"location"`
+	Zones      []string                 `json:"zones"`
+	Sku        sku                      `json:"sku"`
+	Properties virtualMachineProperties `json:"properties"`
+}
+
+type virtualMachineProperties struct {
+	ProvisioningState string    `json:"provisioningState"`
+	TimeCreated       string    `json:"timeCreated"`
+	OSProfile         osProfile `json:"osProfile"`
+}
+
+type osProfile struct {
+	ComputerName string `json:"computerName"`
+}
+
+type virtualMachineList struct {
+	Value    []virtualMachine `json:"value"`
+	NextLink string           `json:"nextLink"`
+}
+
+func (a *Azure) listVirtualMachines(scaleSetName string) ([]virtualMachine, error) {
+	var vms []virtualMachine
+	u := a.scaleSetURL(scaleSetName, "virtualMachines")
+	for u != "" {
+		list := virtualMachineList{}
+		if err := a.do(http.MethodGet, u, nil, &list); err != nil {
+			klog.Errorf("failed to list virtual machines in %s: %v", scaleSetName, err)
+			return nil, err
+		}
+		vms = append(vms, list.Value...)
+		u = list.NextLink
+	}
+	return vms, nil
+}
+
+func (a *Azure) getVirtualMachine(name string) (*virtualMachine, error) {
+	scaleSetName, instanceID, err := parseInstanceID(name)
+	if err != nil {
+		return nil, err
+	}
+	vm := virtualMachine{}
+	if err := a.do(http.MethodGet, a.scaleSetURL(scaleSetName, "virtualMachines", instanceID), nil, &vm); err != nil {
+		if !isNotFound(err) {
+			klog.Errorf("failed to get virtual machine %s: %v", name, err)
+		}
+		return nil, err
+	}
+	return &vm, nil
+}
+
+func (a *Azure) InstanceTerminated(node *operatorv1alpha1.AWSNode) (bool, error) {
+	vm, err := a.getVirtualMachine(node.InstanceID)
+	if isNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	klog.Infof("Instance %s provisioning state is %s", vm.Name, vm.Properties.ProvisioningState)
+	return vm.Properties.ProvisioningState == "Deleting", nil
+}
+
+// InstanceForNode finds the virtual machine whose computer name is same as the node name in all scale sets of the resource group.
+func (a *Azure) InstanceForNode(node *operatorv1alpha1.AWSNode) (*operatorv1alpha1.AWSNode, error) {
+	if node.InstanceID != "" {
+		vm, err := a.getVirtualMachine(node.InstanceID)
+		if err != nil {
+			return nil, err
+		}
+		return convertVirtualMachineToAWSNode(vm), nil
+	}
+	scaleSets, err := a.listScaleSets()
+	if err != nil {
+		return nil, err
+	}
+	for _, ss := range scaleSets {
+		vms, err := a.listVirtualMachines(ss.Name)
+		if err != nil {
+			return nil, err
+		}
+		for i := range vms {
+			if strings.EqualFold(vms[i].Properties.OSProfile.ComputerName, node.Name) {
+				return convertVirtualMachineToAWSNode(&vms[i]), nil
+			}
+		}
+	}
+	klog.Warningf("could not find virtual machine of node %s in scale sets", node.Name)
+	return nil, nil
+}
+
+func (a *Azure) DescribeInstances(instanceIDs []string) ([]operatorv1alpha1.AWSNode, error) {
+	var nodes []operatorv1alpha1.AWSNode
+	for _, id := range instanceIDs {
+		vm, err := a.getVirtualMachine(id)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, *convertVirtualMachineToAWSNode(vm))
+	}
+	return nodes, nil
+}
+
+// convertVirtualMachineToAWSNode converts the virtual machine to AWSNode.
+// Name of the virtual machine is used as InstanceID, because instance IDs are unique only in a scale set.
+func convertVirtualMachineToAWSNode(vm *virtualMachine) *operatorv1alpha1.AWSNode {
+	scaleSetName, _, _ := parseInstanceID(vm.Name)
+	zone := vm.Location
+	if len(vm.Zones) > 0 {
+		zone = vm.Location + "-" + vm.Zones[0]
+	}
+	var creationTimestamp metav1.Time
+	if t, err := time.Parse(time.RFC3339Nano, vm.Properties.TimeCreated); err == nil {
+		creationTimestamp = metav1.NewTime(t.In(time.Local))
+	}
+	return &operatorv1alpha1.AWSNode{
+		Name:                 strings.ToLower(vm.Properties.OSProfile.ComputerName),
+		InstanceID:           vm.Name,
+		AvailabilityZone:     zone,
+		InstanceType:         vm.Sku.Name,
+		AutoScalingGroupName: scaleSetName,
+		CreationTimestamp:    creationTimestamp,
+	}
+}
+
+// parseInstanceID splits the name of the virtual machine into the scale set name and the instance ID.
+func parseInstanceID(name string) (string, string, error) {
+	i := strings.LastIndex(name, "_")
+	if i <= 0 || i == len(name)-1 {
+		return "", "", NewInvalidInstanceIDErrorf("%s is not a virtual machine name in scale sets", name)
+	}
+	return name[:i], name[i+1:], nil
+}
@@ -0,0 +1,237 @@
+package azure
+
+import (
+	"math"
+	"net/http"
+	"sort"
+
+	"k8s.io/klog/v2"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+	"github.com/h3poteto/node-manager/pkg/cloud"
+)
+
+type scaleSet struct {
+	Name string `json:"name"`
+	Sku  sku    `json:"sku"`
+}
+
+type scaleSetList struct {
+	Value    []scaleSet `json:"value"`
+	NextLink string     `json:"nextLink"`
+}
+
+type sku struct {
+	Name     string `json:"name,omitempty"`
+	Capacity *int64 `json:"capacity,omitempty"`
+}
+
+type scaleSetUpdate struct {
+	Sku sku `json:"sku"`
+}
+
+type instanceIDs struct {
+	InstanceIDs []string `json:"instanceIds"`
+}
+
+var _ cloud.Provider = &Azure{}
+
+func (a *Azure) DescribeNodeGroups(groups []operatorv1alpha1.AutoScalingGroup) ([]cloud.NodeGroup, error) {
+	var nodeGroups []cloud.NodeGroup
+	for _, group := range groups {
+		ss, err := a.getScaleSet(group.Name)
+		if err != nil {
+			return nil, err
+		}
+		vms, err := a.listVirtualMachines(group.Name)
+		if err != nil {
+			return nil, err
+		}
+		var ids []string
+		for _, vm := range vms {
+			ids = append(ids, vm.Name)
+		}
+		var capacity int64
+		if ss.Sku.Capacity != nil {
+			capacity = *ss.Sku.Capacity
+		}
+		nodeGroups = append(nodeGroups, cloud.NodeGroup{
+			Name: ss.Name,
+			// Scale set does not have size limits, they belong to autoscale settings.
+			MinSize:         0,
+			MaxSize:         math.MaxInt32,
+			DesiredCapacity: int(capacity),
+			InstanceIDs:     ids,
+		})
+	}
+	return nodeGroups, nil
+}
+
+func (a *Azure) getScaleSet(name string) (*scaleSet, error) {
+	ss := scaleSet{}
+	if err := a.do(http.MethodGet, a.scaleSetURL(name), nil, &ss); err != nil {
+		klog.Errorf("failed to get scale set %s: %v", name, err)
+		return nil, err
+	}
+	return &ss, nil
+}
+
+func (a *Azure) listScaleSets() ([]scaleSet, error) {
+	var scaleSets []scaleSet
+	u := a.scaleSetURL("")
+	for u != "" {
+		list := scaleSetList{}
+		if err := a.do(http.MethodGet, u, nil, &list); err != nil {
+			klog.Errorf("failed to list scale sets: %v", err)
+			return nil, err
+		}
+		scaleSets = append(scaleSets, list.Value...)
+		u = list.NextLink
+	}
+	return scaleSets, nil
+}
+
+func (a *Azure) ScaleUpNodeGroups(groups []operatorv1alpha1.AutoScalingGroup, totalDesired int, currentNodesCount int) error {
+	nodeGroups, err := a.DescribeNodeGroups(groups)
+	if err != nil {
+		return err
+	}
+	resized, err := cloud.PlanScaleUp(nodeGroups, totalDesired, currentNodesCount)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	klog.Infof("spec desired is %d, and current nodes count is %d, so increase capacity of scale sets", totalDesired, currentNodesCount)
+	return a.resizeAll(resized)
+}
+
+func (a *Azure) ScaleDownNodeGroups(groups []operatorv1alpha1.AutoScalingGroup, totalDesired int, currentNodesCount int) error {
+	nodeGroups, err := a.DescribeNodeGroups(groups)
+	if err != nil {
+		return err
+	}
+	resized, err := cloud.PlanScaleDown(nodeGroups, totalDesired, currentNodesCount)
+	if len(resized) > 0 {
+		klog.Infof("spec desired is %d, and current nodes count is %d, so decrease capacity of scale sets", totalDesired, currentNodesCount)
+		if e := a.resizeAll(resized); e != nil && err == nil {
+			err = e
+		}
+	}
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (a *Azure) resizeAll(groups []cloud.NodeGroup) error {
+	var err []error
+	for i := range groups {
+		if e := a.resize(groups[i].Name, groups[i].DesiredCapacity); e != nil {
+			err = append(err, e)
+		}
+	}
+	if len(err) > 0 {
+		return err[0]
+	}
+	return nil
+}
+
+func (a *Azure) resize(name string, capacity int) error {
+	c := int64(capacity)
+	in := scaleSetUpdate{
+		Sku: sku{
+			Capacity: &c,
+		},
+	}
+	if err := a.do(http.MethodPatch, a.scaleSetURL(name), in, nil); err != nil {
+		klog.Errorf("failed to update capacity of scale set %s: %v", name, err)
+		return err
+	}
+	klog.Infof("updated capacity of scale set %s to %d", name, capacity)
+	return nil
+}
+
+// deleteInstance deletes the instance from the scale set. Capacity of the scale set is decremented by Azure.
+func (a *Azure) deleteInstance(scaleSetName, instanceID string) error {
+	in := instanceIDs{
+		InstanceIDs: []string{instanceID},
+	}
+	if err := a.do(http.MethodPost, a.scaleSetURL(scaleSetName, "delete"), in, nil); err != nil {
+		klog.Errorf("failed to delete instance %s from %s: %v", instanceID, scaleSetName, err)
+		return err
+	}
+	return nil
+}
+
+// DetachInstance deletes the instance and decrements capacity of the scale set.
+// Instances in a scale set can not live without it, so detached instances are deleted at the same time.
+func (a *Azure) DetachInstance(node *operatorv1alpha1.AWSNode) error {
+	scaleSetName, instanceID, err := parseInstanceID(node.InstanceID)
+	if err != nil {
+		return err
+	}
+	return a.deleteInstance(scaleSetName, instanceID)
+}
+
+// TerminateInstance deletes the instance, and restores capacity of the scale set to create a new instance instead of it.
+// When the restore fails after the instance is deleted, RestoreCapacity restores it later.
+func (a *Azure) TerminateInstance(node *operatorv1alpha1.AWSNode) error {
+	scaleSetName, instanceID, err := parseInstanceID(node.InstanceID)
+	if err != nil {
+		return err
+	}
+	// DetachInstance has already deleted the instance when it is detached before, so it is not deleted twice.
+	terminated, err := a.InstanceTerminated(node)
+	if err != nil {
+		return err
+	}
+	if terminated {
+		klog.Infof("instance %s is already deleted", node.InstanceID)
+		return nil
+	}
+	ss, err := a.getScaleSet(scaleSetName)
+	if err != nil {
+		return err
+	}
+	if err := a.deleteInstance(scaleSetName, instanceID); err != nil {
+		return err
+	}
+	if ss.Sku.Capacity == nil {
+		return nil
+	}
+	return a.resize(scaleSetName, int(*ss.Sku.Capacity))
+}
+
+var _ cloud.CapacityRestorer = &Azure{}
+
+// RestoreCapacity increases capacity of the scale sets until the sum reaches total.
+// Deleting an instance decrements capacity, so capacity is restored again when TerminateInstance could not restore it.
+func (a *Azure) RestoreCapacity(groups []operatorv1alpha1.AutoScalingGroup, total int) error {
+	nodeGroups, err := a.DescribeNodeGroups(groups)
+	if err != nil {
+		return err
+	}
+	sum := 0
+	for _, group := range nodeGroups {
+		sum += group.DesiredCapacity
+	}
+	if sum >= total || len(nodeGroups) == 0 {
+		return nil
+	}
+	// Smaller scale sets get the instances at first, like ScaleUpNodeGroups adds instances equally.
+	sort.SliceStable(nodeGroups, func(i, j int) bool {
+		return nodeGroups[i].DesiredCapacity < nodeGroups[j].DesiredCapacity
+	})
+	size := len(nodeGroups)
+	if total-sum < size {
+		size = total - sum
+	}
+	resized := nodeGroups[:size]
+	for i := 0; sum < total; i = (i + 1) % size {
+		resized[i].DesiredCapacity += 1
+		sum++
+	}
+	klog.Infof("capacity of scale sets is less than %d, so restore it", total)
+	return a.resizeAll(resized)
+}
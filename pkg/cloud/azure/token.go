@@ -0,0 +1,77 @@
+package azure
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	// clientIDEnv specifies the client ID of a user assigned managed identity.
+	clientIDEnv             = "AZURE_CLIENT_ID"
+	defaultIdentityEndpoint = "http://169.254.169.254/metadata/identity/oauth2/token"
+)
+
+// ManagedIdentityTokenSource issues access tokens of the managed identity from Azure Instance Metadata Service.
+type ManagedIdentityTokenSource struct {
+	Client   *http.Client
+	Endpoint string
+	Resource string
+	ClientID string
+}
+
+var _ oauth2.TokenSource = &ManagedIdentityTokenSource{}
+
+func NewManagedIdentityTokenSource(resource string) *ManagedIdentityTokenSource {
+	return &ManagedIdentityTokenSource{
+		Client:   &http.Client{Timeout: 10 * time.Second},
+		Endpoint: defaultIdentityEndpoint,
+		Resource: resource,
+		ClientID: os.Getenv(clientIDEnv),
+	}
+}
+
+func (s *ManagedIdentityTokenSource) Token() (*oauth2.Token, error) {
+	query := url.Values{}
+	query.Set("api-version", "2018-02-01")
+	query.Set("resource", s.Resource)
+	if s.ClientID != "" {
+		query.Set("client_id", s.ClientID)
+	}
+	req, err := http.NewRequest(http.MethodGet, s.Endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Metadata", "true")
+	res, err := s.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("instance metadata service returned %s", res.Status)
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresOn   string `json:"expires_on"`
+		TokenType   string `json:"token_type"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&token); err != nil {
+		return nil, err
+	}
+	expiresOn, err := strconv.ParseInt(token.ExpiresOn, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &oauth2.Token{
+		AccessToken: token.AccessToken,
+		TokenType:   token.TokenType,
+		Expiry:      time.Unix(expiresOn, 0),
+	}, nil
+}
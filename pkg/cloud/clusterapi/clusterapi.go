@@ -0,0 +1,103 @@
+package clusterapi
+
+import (
+	"context"
+	"errors"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/h3poteto/node-manager/pkg/cloud"
+)
+
+const (
+	// DeploymentNameLabel is set on Machines by MachineDeployment controller of Cluster API.
+	DeploymentNameLabel = "cluster.x-k8s.io/deployment-name"
+	// DeleteMachineAnnotation marks a Machine which MachineSet deletes first when replicas are decreased.
+	DeleteMachineAnnotation = "cluster.x-k8s.io/delete-machine"
+	// MinSizeAnnotation and MaxSizeAnnotation are size limits of a MachineDeployment, which are shared with cluster-autoscaler.
+	MinSizeAnnotation = "cluster.x-k8s.io/cluster-api-autoscaler-node-group-min-size"
+	MaxSizeAnnotation = "cluster.x-k8s.io/cluster-api-autoscaler-node-group-max-size"
+)
+
+var (
+	// GroupVersion is the API version of Cluster API which this provider operates.
+	GroupVersion         = schema.GroupVersion{Group: "cluster.x-k8s.io", Version: "v1beta1"}
+	MachineGVK           = GroupVersion.WithKind("Machine")
+	MachineDeploymentGVK = GroupVersion.WithKind("MachineDeployment")
+	machineListGVK       = GroupVersion.WithKind("MachineList")
+)
+
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinedeployments,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines,verbs=get;list;watch;update;patch;delete
+
+// ClusterAPI operates MachineDeployments and Machines of Cluster API instead of a cloud provider.
+// Cluster API controllers own instances in the cloud, so this provider never calls cloud APIs directly.
+// It works with unstructured objects, so it does not depend on a specific release of Cluster API.
+type ClusterAPI struct {
+	Client    client.Client
+	Namespace string
+}
+
+func New(c client.Client, namespace string) *ClusterAPI {
+	return &ClusterAPI{
+		Client:    c,
+		Namespace: namespace,
+	}
+}
+
+// NewFactory returns a cloud.Factory for Cluster API.
+// MachineDeployments are read from the cluster where the controller runs, so the cluster has to be self-managed
+// or the controller has to run in the management cluster.
+func NewFactory(c client.Client) cloud.Factory {
+	return func(config cloud.Config) (cloud.Provider, error) {
+		if config.Namespace == "" {
+			err := errors.New("namespace is required for clusterapi")
+			klog.Error(err)
+			return nil, err
+		}
+		return New(c, config.Namespace), nil
+	}
+}
+
+func newMachine() *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(MachineGVK)
+	return u
+}
+
+func newMachineDeployment() *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(MachineDeploymentGVK)
+	return u
+}
+
+func (c *ClusterAPI) getMachineDeployment(ctx context.Context, name string) (*unstructured.Unstructured, error) {
+	md := newMachineDeployment()
+	if err := c.Client.Get(ctx, client.ObjectKey{Namespace: c.Namespace, Name: name}, md); err != nil {
+		klog.Errorf("failed to get MachineDeployment %s/%s: %v", c.Namespace, name, err)
+		return nil, err
+	}
+	return md, nil
+}
+
+func (c *ClusterAPI) getMachine(ctx context.Context, name string) (*unstructured.Unstructured, error) {
+	m := newMachine()
+	if err := c.Client.Get(ctx, client.ObjectKey{Namespace: c.Namespace, Name: name}, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *ClusterAPI) listMachines(ctx context.Context, opts ...client.ListOption) ([]unstructured.Unstructured, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(machineListGVK)
+	opts = append(opts, client.InNamespace(c.Namespace))
+	if err := c.Client.List(ctx, list, opts...); err != nil {
+		klog.Errorf("failed to list Machines in %s: %v", c.Namespace, err)
+		return nil, err
+	}
+	return list.Items, nil
+}
@@ -0,0 +1,320 @@
+package clusterapi
+
+import (
+	"context"
+	"log"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+	"github.com/h3poteto/node-manager/pkg/cloud"
+)
+
+const testNamespace = "capi-system"
+
+func newTestMachineDeployment(name string, replicas int64, annotations map[string]string) *unstructured.Unstructured {
+	md := newMachineDeployment()
+	md.SetNamespace(testNamespace)
+	md.SetName(name)
+	md.SetAnnotations(annotations)
+	_ = unstructured.SetNestedField(md.Object, "test-cluster", "spec", "clusterName")
+	_ = unstructured.SetNestedField(md.Object, replicas, "spec", "replicas")
+	return md
+}
+
+func newTestMachine(name, deployment, nodeName string, created time.Time) *unstructured.Unstructured {
+	m := newMachine()
+	m.SetNamespace(testNamespace)
+	m.SetName(name)
+	m.SetCreationTimestamp(metav1.NewTime(created))
+	if deployment != "" {
+		m.SetLabels(map[string]string{DeploymentNameLabel: deployment})
+	}
+	_ = unstructured.SetNestedField(m.Object, "test-cluster", "spec", "clusterName")
+	_ = unstructured.SetNestedField(m.Object, "ap-northeast-1a", "spec", "failureDomain")
+	if nodeName != "" {
+		_ = unstructured.SetNestedField(m.Object, map[string]interface{}{"kind": "Node", "name": nodeName}, "status", "nodeRef")
+	}
+	return m
+}
+
+func newFakeClusterAPI(objects ...client.Object) *ClusterAPI {
+	c := fake.NewClientBuilder().WithObjects(objects...).Build()
+	return New(c, testNamespace)
+}
+
+func getReplicas(t *testing.T, c client.Client, name string) int {
+	md := newMachineDeployment()
+	if err := c.Get(context.Background(), client.ObjectKey{Namespace: testNamespace, Name: name}, md); err != nil {
+		t.Fatalf("failed to get MachineDeployment %s: %v", name, err)
+	}
+	return replicas(md)
+}
+
+func TestDescribeNodeGroups(t *testing.T) {
+	now := time.Now()
+	deleting := newTestMachine("md-a-deleting", "md-a", "node-deleting", now)
+	deleting.SetFinalizers([]string{"machine.cluster.x-k8s.io"})
+	deletionTime := metav1.NewTime(now)
+	deleting.SetDeletionTimestamp(&deletionTime)
+	c := newFakeClusterAPI(
+		newTestMachineDeployment("md-a", 2, map[string]string{MinSizeAnnotation: "1", MaxSizeAnnotation: "5"}),
+		newTestMachineDeployment("md-b", 1, nil),
+		newTestMachine("md-a-1", "md-a", "node-a-1", now),
+		newTestMachine("md-a-2", "md-a", "", now),
+		newTestMachine("md-b-1", "md-b", "node-b-1", now),
+		newTestMachine("control-plane-1", "", "node-cp-1", now),
+		deleting,
+	)
+
+	groups, err := c.DescribeNodeGroups([]operatorv1alpha1.AutoScalingGroup{{Name: "md-a"}, {Name: "md-b"}})
+	if err != nil {
+		t.Fatalf("failed to describe node groups: %v", err)
+	}
+	expected := []cloud.NodeGroup{
+		{Name: "md-a", MinSize: 1, MaxSize: 5, DesiredCapacity: 2, InstanceIDs: []string{"md-a-1", "md-a-2"}},
+		{Name: "md-b", MinSize: 0, MaxSize: math.MaxInt32, DesiredCapacity: 1, InstanceIDs: []string{"md-b-1"}},
+	}
+	if !reflect.DeepEqual(groups, expected) {
+		t.Errorf("node groups are not matched, expected %#v, but got %#v", expected, groups)
+	}
+
+	_, err = c.DescribeNodeGroups([]operatorv1alpha1.AutoScalingGroup{{Name: "unknown"}})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("unknown MachineDeployment should return NotFound, but returned %v", err)
+	}
+}
+
+func TestPlanReplicas(t *testing.T) {
+	cases := []struct {
+		title        string
+		groups       []cloud.NodeGroup
+		totalDesired int
+		expected     []cloud.NodeGroup
+	}{
+		{
+			title: "Increase replicas equally",
+			groups: []cloud.NodeGroup{
+				{Name: "md-a", MinSize: 0, MaxSize: 10, DesiredCapacity: 2},
+				{Name: "md-b", MinSize: 0, MaxSize: 10, DesiredCapacity: 2},
+			},
+			totalDesired: 6,
+			expected: []cloud.NodeGroup{
+				{Name: "md-a", MinSize: 0, MaxSize: 10, DesiredCapacity: 3},
+				{Name: "md-b", MinSize: 0, MaxSize: 10, DesiredCapacity: 3},
+			},
+		},
+		{
+			title: "Increase replicas within max size",
+			groups: []cloud.NodeGroup{
+				{Name: "md-a", MinSize: 0, MaxSize: 2, DesiredCapacity: 2},
+				{Name: "md-b", MinSize: 0, MaxSize: 10, DesiredCapacity: 2},
+			},
+			totalDesired: 6,
+			expected: []cloud.NodeGroup{
+				{Name: "md-b", MinSize: 0, MaxSize: 10, DesiredCapacity: 4},
+			},
+		},
+		{
+			title: "Decrease replicas within min size",
+			groups: []cloud.NodeGroup{
+				{Name: "md-a", MinSize: 2, MaxSize: 10, DesiredCapacity: 3},
+				{Name: "md-b", MinSize: 0, MaxSize: 10, DesiredCapacity: 3},
+			},
+			totalDesired: 3,
+			expected: []cloud.NodeGroup{
+				{Name: "md-a", MinSize: 2, MaxSize: 10, DesiredCapacity: 2},
+				{Name: "md-b", MinSize: 0, MaxSize: 10, DesiredCapacity: 1},
+			},
+		},
+		{
+			title: "Replicas are already desired",
+			groups: []cloud.NodeGroup{
+				{Name: "md-a", MinSize: 0, MaxSize: 10, DesiredCapacity: 1},
+				{Name: "md-b", MinSize: 0, MaxSize: 10, DesiredCapacity: 2},
+			},
+			totalDesired: 3,
+			expected:     nil,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		resized := planReplicas(c.groups, c.totalDesired)
+		if !reflect.DeepEqual(resized, c.expected) {
+			t.Errorf("CASE: %s : planned node groups are not matched, expected %#v, but got %#v", c.title, c.expected, resized)
+		}
+	}
+}
+
+func TestScaleNodeGroups(t *testing.T) {
+	cases := []struct {
+		title            string
+		scaleUp          bool
+		replicas         int64
+		totalDesired     int
+		currentNodes     int
+		expectedReplicas []int
+		expectedErrorNil bool
+	}{
+		{
+			title:            "Scale up MachineDeployments",
+			scaleUp:          true,
+			replicas:         2,
+			totalDesired:     5,
+			currentNodes:     4,
+			expectedReplicas: []int{3, 2},
+			expectedErrorNil: true,
+		},
+		{
+			title:            "Replicas are already increased",
+			scaleUp:          true,
+			replicas:         3,
+			totalDesired:     5,
+			currentNodes:     4,
+			expectedReplicas: []int{3, 3},
+			expectedErrorNil: true,
+		},
+		{
+			title:            "Scale down MachineDeployments",
+			scaleUp:          false,
+			replicas:         3,
+			totalDesired:     4,
+			currentNodes:     6,
+			expectedReplicas: []int{2, 2},
+			expectedErrorNil: true,
+		},
+		{
+			title:            "Desired does not exceed current",
+			scaleUp:          true,
+			replicas:         2,
+			totalDesired:     4,
+			currentNodes:     4,
+			expectedReplicas: []int{2, 2},
+			expectedErrorNil: false,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		capi := newFakeClusterAPI(
+			newTestMachineDeployment("md-a", c.replicas, nil),
+			newTestMachineDeployment("md-b", c.replicas, nil),
+		)
+		groups := []operatorv1alpha1.AutoScalingGroup{{Name: "md-a"}, {Name: "md-b"}}
+		var err error
+		if c.scaleUp {
+			err = capi.ScaleUpNodeGroups(groups, c.totalDesired, c.currentNodes)
+		} else {
+			err = capi.ScaleDownNodeGroups(groups, c.totalDesired, c.currentNodes)
+		}
+		if c.expectedErrorNil && err != nil {
+			t.Errorf("CASE: %s : %v", c.title, err)
+		}
+		if !c.expectedErrorNil && err == nil {
+			t.Errorf("CASE: %s : error should be returned", c.title)
+		}
+		replicas := []int{getReplicas(t, capi.Client, "md-a"), getReplicas(t, capi.Client, "md-b")}
+		if !reflect.DeepEqual(replicas, c.expectedReplicas) {
+			t.Errorf("CASE: %s : replicas are not matched, expected %v, but got %v", c.title, c.expectedReplicas, replicas)
+		}
+	}
+}
+
+func TestTerminateInstance(t *testing.T) {
+	c := newFakeClusterAPI(
+		newTestMachineDeployment("md-a", 1, nil),
+		newTestMachine("md-a-1", "md-a", "node-a-1", time.Now()),
+	)
+	node := &operatorv1alpha1.AWSNode{Name: "node-a-1", InstanceID: "md-a-1", AutoScalingGroupName: "md-a"}
+
+	terminated, err := c.InstanceTerminated(node)
+	if err != nil || terminated {
+		t.Errorf("existing Machine should not be terminated, but returned %v, %v", terminated, err)
+	}
+	if err := c.TerminateInstance(node); err != nil {
+		t.Fatalf("failed to terminate instance: %v", err)
+	}
+	terminated, err = c.InstanceTerminated(node)
+	if err != nil || !terminated {
+		t.Errorf("deleted Machine should be terminated, but returned %v, %v", terminated, err)
+	}
+	if replicas := getReplicas(t, c.Client, "md-a"); replicas != 1 {
+		t.Errorf("replicas should not be changed to replace the Machine, but it is %d", replicas)
+	}
+	if err := c.TerminateInstance(node); err != nil {
+		t.Errorf("deleting a deleted Machine should not return error, but returned %v", err)
+	}
+}
+
+func TestDetachInstance(t *testing.T) {
+	c := newFakeClusterAPI(
+		newTestMachineDeployment("md-a", 2, nil),
+		newTestMachine("md-a-1", "md-a", "", time.Now()),
+		newTestMachine("md-a-2", "md-a", "node-a-2", time.Now()),
+	)
+
+	if err := c.DetachInstance(&operatorv1alpha1.AWSNode{InstanceID: "md-a-1", AutoScalingGroupName: "md-a"}); err != nil {
+		t.Fatalf("failed to detach instance: %v", err)
+	}
+	m, err := c.getMachine(context.Background(), "md-a-1")
+	if err != nil {
+		t.Fatalf("failed to get Machine: %v", err)
+	}
+	if _, ok := m.GetAnnotations()[DeleteMachineAnnotation]; !ok {
+		t.Errorf("Machine should be marked to be deleted, but annotations are %v", m.GetAnnotations())
+	}
+	if replicas := getReplicas(t, c.Client, "md-a"); replicas != 1 {
+		t.Errorf("replicas should be decremented, but it is %d", replicas)
+	}
+}
+
+func TestInstanceForNode(t *testing.T) {
+	created := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	c := newFakeClusterAPI(
+		newTestMachineDeployment("md-a", 1, nil),
+		newTestMachine("md-a-1", "md-a", "node-a-1", created),
+		newTestMachine("md-a-2", "md-a", "", created),
+		newTestMachine("control-plane-1", "", "node-cp-1", created),
+	)
+
+	n, err := c.InstanceForNode(&operatorv1alpha1.AWSNode{Name: "node-a-1"})
+	if err != nil {
+		t.Fatalf("failed to find instance: %v", err)
+	}
+	expected := &operatorv1alpha1.AWSNode{
+		Name:                 "node-a-1",
+		InstanceID:           "md-a-1",
+		AvailabilityZone:     "ap-northeast-1a",
+		AutoScalingGroupName: "md-a",
+		CreationTimestamp:    metav1.NewTime(created),
+	}
+	if n == nil || !n.CreationTimestamp.Equal(&expected.CreationTimestamp) {
+		t.Fatalf("Machine is not converted correctly: %#v", n)
+	}
+	n.CreationTimestamp = expected.CreationTimestamp
+	if !reflect.DeepEqual(n, expected) {
+		t.Errorf("Machine is not converted correctly, expected %#v, but got %#v", expected, n)
+	}
+
+	n, err = c.InstanceForNode(&operatorv1alpha1.AWSNode{Name: "node-cp-1"})
+	if err != nil || n != nil {
+		t.Errorf("Machine which does not belong to MachineDeployments should be ignored, but returned %#v, %v", n, err)
+	}
+
+	nodes, err := c.DescribeInstances([]string{"md-a-2", "unknown"})
+	if err != nil {
+		t.Fatalf("failed to describe instances: %v", err)
+	}
+	if len(nodes) != 1 || nodes[0].InstanceID != "md-a-2" || nodes[0].Name != "" {
+		t.Errorf("only existing Machines should be returned, but returned %#v", nodes)
+	}
+}
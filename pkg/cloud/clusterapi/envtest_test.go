@@ -0,0 +1,108 @@
+package clusterapi
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+)
+
+// TestClusterAPIWithEnvtest runs the provider against a real API server with CRDs of Cluster API.
+// It requires binaries of envtest, so it is skipped when KUBEBUILDER_ASSETS is not set, except in CI where it fails.
+func TestClusterAPIWithEnvtest(t *testing.T) {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		if os.Getenv("CI") != "" {
+			t.Fatal("KUBEBUILDER_ASSETS is not set in CI, so envtest can not run. Install envtest binaries with setup-envtest")
+		}
+		t.Skip("WARNING: KUBEBUILDER_ASSETS is not set, so envtest is skipped. Run make test to run it")
+	}
+	testEnv := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("testdata", "crd")},
+		ErrorIfCRDPathMissing: true,
+	}
+	cfg, err := testEnv.Start()
+	if err != nil {
+		t.Fatalf("failed to start envtest: %v", err)
+	}
+	defer func() {
+		if err := testEnv.Stop(); err != nil {
+			t.Errorf("failed to stop envtest: %v", err)
+		}
+	}()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := c.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testNamespace}}); err != nil {
+		t.Fatalf("failed to create namespace: %v", err)
+	}
+	for _, obj := range []*unstructured.Unstructured{
+		newTestMachineDeployment("md-a", 2, nil),
+		newTestMachine("md-a-1", "md-a", "node-a-1", time.Now()),
+		newTestMachine("md-a-2", "md-a", "node-a-2", time.Now()),
+	} {
+		status, hasStatus := obj.Object["status"]
+		if err := c.Create(ctx, obj); err != nil {
+			t.Fatalf("failed to create %s %s: %v", obj.GetKind(), obj.GetName(), err)
+		}
+		if hasStatus {
+			obj.Object["status"] = status
+			if err := c.Status().Update(ctx, obj); err != nil {
+				t.Fatalf("failed to update status of %s %s: %v", obj.GetKind(), obj.GetName(), err)
+			}
+		}
+	}
+
+	capi := New(c, testNamespace)
+	groups := []operatorv1alpha1.AutoScalingGroup{{Name: "md-a"}}
+
+	n, err := capi.InstanceForNode(&operatorv1alpha1.AWSNode{Name: "node-a-1"})
+	if err != nil || n == nil || n.InstanceID != "md-a-1" || n.AutoScalingGroupName != "md-a" {
+		t.Fatalf("failed to find Machine of the node: %#v, %v", n, err)
+	}
+
+	if err := capi.ScaleUpNodeGroups(groups, 3, 2); err != nil {
+		t.Fatalf("failed to scale up: %v", err)
+	}
+	if replicas := getReplicas(t, c, "md-a"); replicas != 3 {
+		t.Errorf("replicas should be increased, but it is %d", replicas)
+	}
+
+	if err := capi.TerminateInstance(n); err != nil {
+		t.Fatalf("failed to terminate instance: %v", err)
+	}
+	terminated, err := capi.InstanceTerminated(n)
+	if err != nil || !terminated {
+		t.Errorf("Machine should be deleted, but returned %v, %v", terminated, err)
+	}
+
+	if err := capi.DetachInstance(&operatorv1alpha1.AWSNode{InstanceID: "md-a-2"}); err != nil {
+		t.Fatalf("failed to detach instance: %v", err)
+	}
+	if replicas := getReplicas(t, c, "md-a"); replicas != 2 {
+		t.Errorf("replicas should be decremented, but it is %d", replicas)
+	}
+
+	if err := capi.ScaleDownNodeGroups(groups, 1, 2); err != nil {
+		t.Fatalf("failed to scale down: %v", err)
+	}
+	if replicas := getReplicas(t, c, "md-a"); replicas != 1 {
+		t.Errorf("replicas should be decreased, but it is %d", replicas)
+	}
+}
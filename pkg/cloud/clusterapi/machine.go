@@ -0,0 +1,136 @@
+package clusterapi
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+)
+
+// TerminateInstance deletes the Machine of the node, and MachineSet creates a new Machine instead of it.
+func (c *ClusterAPI) TerminateInstance(node *operatorv1alpha1.AWSNode) error {
+	ctx := context.Background()
+	m := newMachine()
+	m.SetNamespace(c.Namespace)
+	m.SetName(node.InstanceID)
+	if err := c.Client.Delete(ctx, m); err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.Infof("Machine %s/%s has already been deleted", c.Namespace, node.InstanceID)
+			return nil
+		}
+		klog.Errorf("failed to delete Machine %s/%s: %v", c.Namespace, node.InstanceID, err)
+		return err
+	}
+	klog.Infof("deleted Machine %s/%s", c.Namespace, node.InstanceID)
+	return nil
+}
+
+// DetachInstance marks the Machine of the node to be deleted, and decrements replicas of its MachineDeployment.
+// MachineSet deletes the marked Machine first regardless of deletePolicy.
+func (c *ClusterAPI) DetachInstance(node *operatorv1alpha1.AWSNode) error {
+	ctx := context.Background()
+	m, err := c.getMachine(ctx, node.InstanceID)
+	if err != nil {
+		klog.Errorf("failed to get Machine %s/%s: %v", c.Namespace, node.InstanceID, err)
+		return err
+	}
+	patch := client.MergeFrom(m.DeepCopy())
+	annotations := m.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[DeleteMachineAnnotation] = "yes"
+	m.SetAnnotations(annotations)
+	if err := c.Client.Patch(ctx, m, patch); err != nil {
+		klog.Errorf("failed to annotate Machine %s/%s: %v", c.Namespace, node.InstanceID, err)
+		return err
+	}
+
+	deployment := m.GetLabels()[DeploymentNameLabel]
+	if deployment == "" {
+		deployment = node.AutoScalingGroupName
+	}
+	md, err := c.getMachineDeployment(ctx, deployment)
+	if err != nil {
+		return err
+	}
+	current := replicas(md)
+	if current < 1 {
+		return nil
+	}
+	return c.setReplicas(ctx, md, current-1)
+}
+
+// InstanceTerminated returns true when the Machine has been removed, which means the instance has been deleted by Cluster API.
+func (c *ClusterAPI) InstanceTerminated(node *operatorv1alpha1.AWSNode) (bool, error) {
+	_, err := c.getMachine(context.Background(), node.InstanceID)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		klog.Errorf("failed to get Machine %s/%s: %v", c.Namespace, node.InstanceID, err)
+		return false, err
+	}
+	return false, nil
+}
+
+// InstanceForNode finds the Machine whose nodeRef is the node.
+// Machines which do not belong to MachineDeployments, like control plane machines, are ignored.
+func (c *ClusterAPI) InstanceForNode(node *operatorv1alpha1.AWSNode) (*operatorv1alpha1.AWSNode, error) {
+	machines, err := c.listMachines(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	for i := range machines {
+		m := &machines[i]
+		if nodeRefName(m) != node.Name {
+			continue
+		}
+		if m.GetLabels()[DeploymentNameLabel] == "" {
+			klog.Warningf("Machine %s/%s of node %s does not belong to any MachineDeployment", m.GetNamespace(), m.GetName(), node.Name)
+			return nil, nil
+		}
+		n := convertMachineToAWSNode(m)
+		return &n, nil
+	}
+	return nil, nil
+}
+
+// DescribeInstances returns Machines which have the names, so instance IDs are names of Machines in this provider.
+func (c *ClusterAPI) DescribeInstances(instanceIDs []string) ([]operatorv1alpha1.AWSNode, error) {
+	ctx := context.Background()
+	var nodes []operatorv1alpha1.AWSNode
+	for _, id := range instanceIDs {
+		m, err := c.getMachine(ctx, id)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			klog.Errorf("failed to get Machine %s/%s: %v", c.Namespace, id, err)
+			return nil, err
+		}
+		nodes = append(nodes, convertMachineToAWSNode(m))
+	}
+	return nodes, nil
+}
+
+func convertMachineToAWSNode(m *unstructured.Unstructured) operatorv1alpha1.AWSNode {
+	failureDomain, _, _ := unstructured.NestedString(m.Object, "spec", "failureDomain")
+	return operatorv1alpha1.AWSNode{
+		Name:                 nodeRefName(m),
+		InstanceID:           m.GetName(),
+		AvailabilityZone:     failureDomain,
+		AutoScalingGroupName: m.GetLabels()[DeploymentNameLabel],
+		CreationTimestamp:    m.GetCreationTimestamp(),
+	}
+}
+
+// nodeRefName returns the name of the node which is linked to the Machine, or empty until the node joins the cluster.
+func nodeRefName(m *unstructured.Unstructured) string {
+	name, _, _ := unstructured.NestedString(m.Object, "status", "nodeRef", "name")
+	return name
+}
@@ -0,0 +1,186 @@
+package clusterapi
+
+import (
+	"context"
+	"math"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+	"github.com/h3poteto/node-manager/pkg/cloud"
+)
+
+var _ cloud.Provider = &ClusterAPI{}
+
+func (c *ClusterAPI) DescribeNodeGroups(groups []operatorv1alpha1.AutoScalingGroup) ([]cloud.NodeGroup, error) {
+	ctx := context.Background()
+	var nodeGroups []cloud.NodeGroup
+	for _, group := range groups {
+		md, err := c.getMachineDeployment(ctx, group.Name)
+		if err != nil {
+			return nil, err
+		}
+		machines, err := c.listMachines(ctx, client.MatchingLabels{DeploymentNameLabel: group.Name})
+		if err != nil {
+			return nil, err
+		}
+		var instanceIDs []string
+		for i := range machines {
+			// Machines which are being deleted are replaced by MachineSet, so they are not members any more.
+			if machines[i].GetDeletionTimestamp() != nil {
+				continue
+			}
+			instanceIDs = append(instanceIDs, machines[i].GetName())
+		}
+		nodeGroups = append(nodeGroups, cloud.NodeGroup{
+			Name:            md.GetName(),
+			MinSize:         sizeAnnotation(md, MinSizeAnnotation, 0),
+			MaxSize:         sizeAnnotation(md, MaxSizeAnnotation, math.MaxInt32),
+			DesiredCapacity: replicas(md),
+			InstanceIDs:     instanceIDs,
+		})
+	}
+	return nodeGroups, nil
+}
+
+// ScaleUpNodeGroups increases replicas of MachineDeployments until the sum reaches totalDesired.
+// MachineSets create Machines as many as replicas by themselves, so it does nothing when replicas are already enough.
+func (c *ClusterAPI) ScaleUpNodeGroups(groups []operatorv1alpha1.AutoScalingGroup, totalDesired int, currentNodesCount int) error {
+	if totalDesired <= currentNodesCount {
+		return cloud.NewDesiredInvalidErrorf("desired does not exceed current, totalDesired: %d, currentNodesCount: %d", totalDesired, currentNodesCount)
+	}
+	nodeGroups, err := c.DescribeNodeGroups(groups)
+	if err != nil {
+		return err
+	}
+	if sumReplicas(nodeGroups) >= totalDesired {
+		klog.Infof("replicas of MachineDeployments already reach %d, so waiting for Machines", totalDesired)
+		return nil
+	}
+	return c.applyReplicas(planReplicas(nodeGroups, totalDesired))
+}
+
+// ScaleDownNodeGroups decreases replicas of MachineDeployments until the sum reaches totalDesired.
+// Which Machines are deleted depends on deletePolicy of MachineDeployments.
+func (c *ClusterAPI) ScaleDownNodeGroups(groups []operatorv1alpha1.AutoScalingGroup, totalDesired int, currentNodesCount int) error {
+	if totalDesired >= currentNodesCount {
+		return cloud.NewDesiredInvalidErrorf("desired exceeds current, totalDesired: %d, currentNodesCount: %d", totalDesired, currentNodesCount)
+	}
+	nodeGroups, err := c.DescribeNodeGroups(groups)
+	if err != nil {
+		return err
+	}
+	if sumReplicas(nodeGroups) <= totalDesired {
+		klog.Infof("replicas of MachineDeployments already reach %d, so waiting for Machines", totalDesired)
+		return nil
+	}
+	return c.applyReplicas(planReplicas(nodeGroups, totalDesired))
+}
+
+func (c *ClusterAPI) applyReplicas(nodeGroups []cloud.NodeGroup) error {
+	ctx := context.Background()
+	for _, group := range nodeGroups {
+		md, err := c.getMachineDeployment(ctx, group.Name)
+		if err != nil {
+			return err
+		}
+		if err := c.setReplicas(ctx, md, group.DesiredCapacity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *ClusterAPI) setReplicas(ctx context.Context, md *unstructured.Unstructured, count int) error {
+	patch := client.MergeFrom(md.DeepCopy())
+	if err := unstructured.SetNestedField(md.Object, int64(count), "spec", "replicas"); err != nil {
+		return err
+	}
+	if err := c.Client.Patch(ctx, md, patch); err != nil {
+		klog.Errorf("failed to update replicas of MachineDeployment %s/%s: %v", md.GetNamespace(), md.GetName(), err)
+		return err
+	}
+	klog.Infof("updated replicas of MachineDeployment %s/%s to %d", md.GetNamespace(), md.GetName(), count)
+	return nil
+}
+
+// planReplicas distributes totalDesired to replicas of node groups equally within their size limits.
+// Unlike cloud.PlanScaleUp, it does not compare replicas with current Machines, because MachineSets reconcile them.
+// It returns only node groups which should be resized.
+func planReplicas(nodeGroups []cloud.NodeGroup, totalDesired int) []cloud.NodeGroup {
+	planned := make([]cloud.NodeGroup, len(nodeGroups))
+	copy(planned, nodeGroups)
+	sum := sumReplicas(planned)
+	for sum != totalDesired {
+		target := -1
+		for i := range planned {
+			if sum < totalDesired {
+				if planned[i].MaxSize-planned[i].DesiredCapacity <= 0 {
+					continue
+				}
+				if target < 0 || planned[i].MaxSize-planned[i].DesiredCapacity > planned[target].MaxSize-planned[target].DesiredCapacity {
+					target = i
+				}
+			} else {
+				if planned[i].DesiredCapacity-planned[i].MinSize <= 0 {
+					continue
+				}
+				if target < 0 || planned[i].DesiredCapacity-planned[i].MinSize > planned[target].DesiredCapacity-planned[target].MinSize {
+					target = i
+				}
+			}
+		}
+		// Exit this loop when all node groups reach their size limits.
+		if target < 0 {
+			break
+		}
+		if sum < totalDesired {
+			planned[target].DesiredCapacity += 1
+			sum++
+		} else {
+			planned[target].DesiredCapacity -= 1
+			sum--
+		}
+	}
+
+	var resized []cloud.NodeGroup
+	for i := range planned {
+		if planned[i].DesiredCapacity != nodeGroups[i].DesiredCapacity {
+			resized = append(resized, planned[i])
+		}
+	}
+	return resized
+}
+
+func sumReplicas(nodeGroups []cloud.NodeGroup) int {
+	sum := 0
+	for _, group := range nodeGroups {
+		sum += group.DesiredCapacity
+	}
+	return sum
+}
+
+// replicas returns spec.replicas of the MachineDeployment, which defaults to 1 in Cluster API.
+func replicas(md *unstructured.Unstructured) int {
+	r, found, err := unstructured.NestedInt64(md.Object, "spec", "replicas")
+	if err != nil || !found {
+		return 1
+	}
+	return int(r)
+}
+
+func sizeAnnotation(md *unstructured.Unstructured, key string, defaultSize int) int {
+	value, ok := md.GetAnnotations()[key]
+	if !ok {
+		return defaultSize
+	}
+	size, err := strconv.Atoi(value)
+	if err != nil {
+		klog.Warningf("annotation %s of MachineDeployment %s/%s is not a number: %s", key, md.GetNamespace(), md.GetName(), value)
+		return defaultSize
+	}
+	return size
+}
@@ -0,0 +1,68 @@
+package gcp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"golang.org/x/oauth2"
+	"k8s.io/klog/v2"
+
+	"github.com/h3poteto/node-manager/pkg/cloud"
+)
+
+// ClientCache keeps clients of GCP for each project and zone, so a token source is not created on every reconcile.
+// All clients share an HTTP client, because access tokens are issued for the same service account of the host.
+type ClientCache struct {
+	mu      sync.Mutex
+	client  *http.Client
+	clients map[clientKey]*GCP
+	// newTokenSource is replaced in tests.
+	newTokenSource func() oauth2.TokenSource
+}
+
+// clientKey identifies a location of Managed Instance Groups.
+type clientKey struct {
+	project string
+	zone    string
+}
+
+func NewClientCache() *ClientCache {
+	return &ClientCache{
+		clients: map[clientKey]*GCP{},
+		newTokenSource: func() oauth2.TokenSource {
+			return NewMetadataTokenSource()
+		},
+	}
+}
+
+// NewProvider is a cloud.Factory for GCP, which returns the cached client for the config.
+// Access tokens are issued by the metadata server, so the controller has to run on GCE or GKE with a service account.
+func (c *ClientCache) NewProvider(config cloud.Config) (cloud.Provider, error) {
+	return c.Client(config)
+}
+
+// Client returns the client for the project and the zone of the config, and creates it at the first time.
+func (c *ClientCache) Client(config cloud.Config) (*GCP, error) {
+	if config.Project == "" || config.Zone == "" {
+		err := errors.New("project and zone are required for gcp")
+		klog.Error(err)
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := clientKey{
+		project: config.Project,
+		zone:    config.Zone,
+	}
+	if client, ok := c.clients[key]; ok {
+		return client, nil
+	}
+	if c.client == nil {
+		c.client = oauth2.NewClient(context.Background(), oauth2.ReuseTokenSource(nil, c.newTokenSource()))
+	}
+	client := New(c.client, DefaultEndpoint, config.Project, config.Zone)
+	c.clients[key] = client
+	return client, nil
+}
@@ -0,0 +1,46 @@
+package gcp
+
+import (
+	"testing"
+
+	"golang.org/x/oauth2"
+
+	"github.com/h3poteto/node-manager/pkg/cloud"
+)
+
+func TestClientCache(t *testing.T) {
+	sources := 0
+	c := NewClientCache()
+	c.newTokenSource = func() oauth2.TokenSource {
+		sources++
+		return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"})
+	}
+
+	if _, err := c.Client(cloud.Config{Project: "my-project"}); err == nil {
+		t.Fatalf("project and zone should be required")
+	}
+	a, err := c.Client(cloud.Config{Project: "my-project", Zone: "us-central1-a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cached, err := c.Client(cloud.Config{Project: "my-project", Zone: "us-central1-a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != cached {
+		t.Errorf("client should be reused for the same zone")
+	}
+	b, err := c.Client(cloud.Config{Project: "my-project", Zone: "us-central1-b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a == b {
+		t.Errorf("client should be created for each zone")
+	}
+	if a.Client != b.Client {
+		t.Errorf("http client should be shared by clients")
+	}
+	if sources != 1 {
+		t.Errorf("token source should be shared by clients, but created %d times", sources)
+	}
+}
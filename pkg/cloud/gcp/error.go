@@ -0,0 +1,17 @@
+package gcp
+
+import "fmt"
+
+type NotManagedInstanceError struct {
+	Msg string
+}
+
+func NewNotManagedInstanceErrorf(format string, a ...interface{}) *NotManagedInstanceError {
+	return &NotManagedInstanceError{
+		Msg: fmt.Sprintf(format, a...),
+	}
+}
+
+func (err *NotManagedInstanceError) Error() string {
+	return err.Msg
+}
@@ -0,0 +1,192 @@
+package gcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	testProject = "test-project"
+	testZone    = "us-central1-a"
+)
+
+type fakeInstanceGroup struct {
+	targetSize int64
+	instances  []string
+}
+
+// fakeCompute is an in-process Compute Engine API which serves a part of instanceGroupManagers and instances APIs.
+type fakeCompute struct {
+	mu        sync.Mutex
+	groups    map[string]*fakeInstanceGroup
+	instances map[string]*instance
+	// pageSize is the number of managed instances in a page of listManagedInstances.
+	pageSize int
+	// resized records sizes which are requested to resize.
+	resized map[string]int64
+	deleted []string
+}
+
+func newFakeCompute() *fakeCompute {
+	return &fakeCompute{
+		groups:    map[string]*fakeInstanceGroup{},
+		instances: map[string]*instance{},
+		pageSize:  500,
+		resized:   map[string]int64{},
+	}
+}
+
+func (f *fakeCompute) addGroup(name string, targetSize int64, instanceNames ...string) {
+	f.groups[name] = &fakeInstanceGroup{
+		targetSize: targetSize,
+		instances:  instanceNames,
+	}
+	for _, n := range instanceNames {
+		f.instances[n] = &instance{
+			Name:              n,
+			Zone:              "https://www.googleapis.com/compute/v1/projects/" + testProject + "/zones/" + testZone,
+			MachineType:       "https://www.googleapis.com/compute/v1/projects/" + testProject + "/zones/" + testZone + "/machineTypes/e2-medium",
+			Status:            "RUNNING",
+			CreationTimestamp: "2021-01-02T03:04:05-07:00",
+			Metadata: instanceMetadata{
+				Items: []metadataItem{
+					{
+						Key:   createdByKey,
+						Value: "projects/123456/zones/" + testZone + "/instanceGroupManagers/" + name,
+					},
+				},
+			},
+		}
+	}
+}
+
+// start starts the fake server, and returns GCP which uses it.
+func (f *fakeCompute) start() (*GCP, func()) {
+	server := httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	return New(server.Client(), server.URL, testProject, testZone), server.Close
+}
+
+func (f *fakeCompute) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	prefix := fmt.Sprintf("/projects/%s/zones/%s/", testProject, testZone)
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		writeError(w, http.StatusNotFound, "notFound", "unknown path "+r.URL.Path)
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, prefix), "/")
+	switch {
+	case parts[0] == "instanceGroupManagers" && len(parts) == 2 && r.Method == http.MethodGet:
+		group, ok := f.groups[parts[1]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "notFound", "instance group "+parts[1]+" was not found")
+			return
+		}
+		writeJSON(w, instanceGroupManager{Name: parts[1], TargetSize: group.targetSize})
+	case parts[0] == "instanceGroupManagers" && len(parts) == 3 && r.Method == http.MethodPost:
+		group, ok := f.groups[parts[1]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "notFound", "instance group "+parts[1]+" was not found")
+			return
+		}
+		f.serveInstanceGroupAction(w, r, parts[1], group, parts[2])
+	case parts[0] == "instances" && len(parts) == 2 && r.Method == http.MethodGet:
+		i, ok := f.instances[parts[1]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "notFound", "instance "+parts[1]+" was not found")
+			return
+		}
+		writeJSON(w, i)
+	case parts[0] == "instances" && len(parts) == 2 && r.Method == http.MethodDelete:
+		if _, ok := f.instances[parts[1]]; !ok {
+			writeError(w, http.StatusNotFound, "notFound", "instance "+parts[1]+" was not found")
+			return
+		}
+		delete(f.instances, parts[1])
+		f.deleted = append(f.deleted, parts[1])
+		writeJSON(w, map[string]string{"status": "RUNNING"})
+	default:
+		writeError(w, http.StatusNotFound, "notFound", "unknown path "+r.URL.Path)
+	}
+}
+
+func (f *fakeCompute) serveInstanceGroupAction(w http.ResponseWriter, r *http.Request, name string, group *fakeInstanceGroup, action string) {
+	switch action {
+	case "listManagedInstances":
+		start := 0
+		if token := r.URL.Query().Get("pageToken"); token != "" {
+			start, _ = strconv.Atoi(token)
+		}
+		end := start + f.pageSize
+		list := managedInstanceList{}
+		if end < len(group.instances) {
+			list.NextPageToken = strconv.Itoa(end)
+		} else {
+			end = len(group.instances)
+		}
+		for _, n := range group.instances[start:end] {
+			list.ManagedInstances = append(list.ManagedInstances, managedInstance{
+				Instance:       "https://www.googleapis.com/compute/v1/projects/" + testProject + "/zones/" + testZone + "/instances/" + n,
+				InstanceStatus: "RUNNING",
+				CurrentAction:  "NONE",
+			})
+		}
+		writeJSON(w, list)
+	case "resize":
+		size, err := strconv.ParseInt(r.URL.Query().Get("size"), 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid", err.Error())
+			return
+		}
+		group.targetSize = size
+		f.resized[name] = size
+		writeJSON(w, map[string]string{"status": "RUNNING"})
+	case "abandonInstances":
+		var refs instanceReferences
+		if err := json.NewDecoder(r.Body).Decode(&refs); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid", err.Error())
+			return
+		}
+		for _, ref := range refs.Instances {
+			n := lastSegment(ref)
+			for i := range group.instances {
+				if group.instances[i] == n {
+					group.instances = append(group.instances[:i], group.instances[i+1:]...)
+					group.targetSize--
+					break
+				}
+			}
+		}
+		writeJSON(w, map[string]string{"status": "RUNNING"})
+	default:
+		writeError(w, http.StatusNotFound, "notFound", "unknown action "+action)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, reason, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    status,
+			"message": message,
+			"errors": []map[string]string{
+				{
+					"reason":  reason,
+					"message": message,
+				},
+			},
+		},
+	})
+}
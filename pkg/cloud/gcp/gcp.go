@@ -0,0 +1,137 @@
+package gcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultEndpoint is the endpoint of Compute Engine API v1.
+const DefaultEndpoint = "https://compute.googleapis.com/compute/v1/"
+
+// GCP operates zonal Managed Instance Groups through Compute Engine API.
+type GCP struct {
+	Client   *http.Client
+	Endpoint string
+	Project  string
+	Zone     string
+}
+
+func New(client *http.Client, endpoint, project, zone string) *GCP {
+	if !strings.HasSuffix(endpoint, "/") {
+		endpoint += "/"
+	}
+	return &GCP{
+		Client:   client,
+		Endpoint: endpoint,
+		Project:  project,
+		Zone:     zone,
+	}
+}
+
+// zonalURL returns the URL of the resource in the zone.
+func (g *GCP) zonalURL(resource ...string) string {
+	return g.Endpoint + g.zonalPath(resource...)
+}
+
+// zonalPath returns the partial URL of the resource in the zone, which is accepted as a resource reference in Compute Engine API.
+func (g *GCP) zonalPath(resource ...string) string {
+	return fmt.Sprintf("projects/%s/zones/%s/%s", g.Project, g.Zone, strings.Join(resource, "/"))
+}
+
+func (g *GCP) do(method, url string, in interface{}, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	res, err := g.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode >= 300 {
+		return newAPIError(res.StatusCode, b)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(b, out)
+}
+
+// APIError is an error response of Compute Engine API.
+type APIError struct {
+	StatusCode int
+	Status     string
+	Msg        string
+}
+
+func newAPIError(statusCode int, body []byte) *APIError {
+	var res struct {
+		Error struct {
+			Message string `json:"message"`
+			Status  string `json:"status"`
+			Errors  []struct {
+				Reason string `json:"reason"`
+			} `json:"errors"`
+		} `json:"error"`
+	}
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		Status:     http.StatusText(statusCode),
+		Msg:        string(body),
+	}
+	if err := json.Unmarshal(body, &res); err != nil {
+		return apiErr
+	}
+	if res.Error.Message != "" {
+		apiErr.Msg = res.Error.Message
+	}
+	if len(res.Error.Errors) > 0 && res.Error.Errors[0].Reason != "" {
+		apiErr.Status = res.Error.Errors[0].Reason
+	} else if res.Error.Status != "" {
+		apiErr.Status = res.Error.Status
+	}
+	return apiErr
+}
+
+func (err *APIError) Error() string {
+	return fmt.Sprintf("%s: %s", err.Status, err.Msg)
+}
+
+// Code returns the reason of the error, which is reported in CloudAPIError condition.
+func (err *APIError) Code() string {
+	return err.Status
+}
+
+func (err *APIError) Message() string {
+	return err.Msg
+}
+
+func isNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// lastSegment returns the resource name from the URL of the resource.
+func lastSegment(url string) string {
+	return url[strings.LastIndex(url, "/")+1:]
+}
@@ -0,0 +1,181 @@
+package gcp
+
+import (
+	"errors"
+	"log"
+	"reflect"
+	"sort"
+	"testing"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+)
+
+func TestDescribeNodeGroups(t *testing.T) {
+	fake := newFakeCompute()
+	fake.pageSize = 2
+	fake.addGroup("mig-a", 3, "instance-a-1", "instance-a-2", "instance-a-3")
+	fake.addGroup("mig-b", 1)
+	g, closeServer := fake.start()
+	defer closeServer()
+
+	groups, err := g.DescribeNodeGroups([]operatorv1alpha1.AutoScalingGroup{{Name: "mig-a"}, {Name: "mig-b"}})
+	if err != nil {
+		t.Fatalf("failed to describe node groups: %v", err)
+	}
+	if len(groups) != 2 {
+		t.Fatalf("node groups should be 2, but returned %d", len(groups))
+	}
+	if !reflect.DeepEqual(groups[0].InstanceIDs, []string{"instance-a-1", "instance-a-2", "instance-a-3"}) {
+		t.Errorf("all pages of managed instances should be listed, but returned %v", groups[0].InstanceIDs)
+	}
+	if groups[0].DesiredCapacity != 3 || groups[1].DesiredCapacity != 1 {
+		t.Errorf("desired capacity should be target size, but returned %d and %d", groups[0].DesiredCapacity, groups[1].DesiredCapacity)
+	}
+
+	_, err = g.DescribeNodeGroups([]operatorv1alpha1.AutoScalingGroup{{Name: "unknown"}})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code() != "notFound" {
+		t.Errorf("unknown group should return notFound APIError, but returned %v", err)
+	}
+}
+
+func TestScaleNodeGroups(t *testing.T) {
+	cases := []struct {
+		title            string
+		scaleUp          bool
+		totalDesired     int
+		currentNodes     int
+		expectedResized  map[string]int64
+		expectedErrorNil bool
+	}{
+		{
+			title:            "Scale up across multiple groups",
+			scaleUp:          true,
+			totalDesired:     6,
+			currentNodes:     4,
+			expectedResized:  map[string]int64{"mig-a": 3, "mig-b": 3},
+			expectedErrorNil: true,
+		},
+		{
+			title:            "Scale down across multiple groups",
+			scaleUp:          false,
+			totalDesired:     2,
+			currentNodes:     4,
+			expectedResized:  map[string]int64{"mig-a": 1, "mig-b": 1},
+			expectedErrorNil: true,
+		},
+		{
+			title:            "Instances have not joined yet",
+			scaleUp:          true,
+			totalDesired:     6,
+			currentNodes:     3,
+			expectedResized:  map[string]int64{},
+			expectedErrorNil: false,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		fake := newFakeCompute()
+		fake.addGroup("mig-a", 2, "instance-a-1", "instance-a-2")
+		fake.addGroup("mig-b", 2, "instance-b-1", "instance-b-2")
+		g, closeServer := fake.start()
+
+		groups := []operatorv1alpha1.AutoScalingGroup{{Name: "mig-a"}, {Name: "mig-b"}}
+		var err error
+		if c.scaleUp {
+			err = g.ScaleUpNodeGroups(groups, c.totalDesired, c.currentNodes)
+		} else {
+			err = g.ScaleDownNodeGroups(groups, c.totalDesired, c.currentNodes)
+		}
+		closeServer()
+		if c.expectedErrorNil && err != nil {
+			t.Errorf("CASE: %s : %v", c.title, err)
+		}
+		if !c.expectedErrorNil && err == nil {
+			t.Errorf("CASE: %s : error should be returned", c.title)
+		}
+		if !reflect.DeepEqual(fake.resized, c.expectedResized) {
+			t.Errorf("CASE: %s : resized groups are not matched, expected %v, but got %v", c.title, c.expectedResized, fake.resized)
+		}
+	}
+}
+
+func TestTerminateInstance(t *testing.T) {
+	fake := newFakeCompute()
+	fake.addGroup("mig-a", 1, "instance-a-1")
+	g, closeServer := fake.start()
+	defer closeServer()
+
+	node := &operatorv1alpha1.AWSNode{Name: "instance-a-1", InstanceID: "instance-a-1"}
+	terminated, err := g.InstanceTerminated(node)
+	if err != nil || terminated {
+		t.Errorf("running instance should not be terminated, but returned %v, %v", terminated, err)
+	}
+	if err := g.TerminateInstance(node); err != nil {
+		t.Fatalf("failed to terminate instance: %v", err)
+	}
+	if !reflect.DeepEqual(fake.deleted, []string{"instance-a-1"}) {
+		t.Errorf("instance should be deleted, but deleted %v", fake.deleted)
+	}
+	terminated, err = g.InstanceTerminated(node)
+	if err != nil || !terminated {
+		t.Errorf("deleted instance should be terminated, but returned %v, %v", terminated, err)
+	}
+}
+
+func TestDetachInstance(t *testing.T) {
+	fake := newFakeCompute()
+	fake.addGroup("mig-a", 2, "instance-a-1", "instance-a-2")
+	g, closeServer := fake.start()
+	defer closeServer()
+
+	err := g.DetachInstance(&operatorv1alpha1.AWSNode{InstanceID: "instance-a-1", AutoScalingGroupName: "mig-a"})
+	if err != nil {
+		t.Fatalf("failed to detach instance: %v", err)
+	}
+	group := fake.groups["mig-a"]
+	if group.targetSize != 1 || !reflect.DeepEqual(group.instances, []string{"instance-a-2"}) {
+		t.Errorf("instance should be abandoned from the group, but target size is %d and instances are %v", group.targetSize, group.instances)
+	}
+}
+
+func TestInstanceForNode(t *testing.T) {
+	fake := newFakeCompute()
+	fake.addGroup("mig-a", 2, "instance-a-1", "instance-a-2")
+	fake.instances["standalone"] = &instance{
+		Name:              "standalone",
+		Zone:              testZone,
+		MachineType:       "e2-medium",
+		Status:            "RUNNING",
+		CreationTimestamp: "2021-01-02T03:04:05-07:00",
+	}
+	g, closeServer := fake.start()
+	defer closeServer()
+
+	n, err := g.InstanceForNode(&operatorv1alpha1.AWSNode{Name: "instance-a-1"})
+	if err != nil {
+		t.Fatalf("failed to find instance: %v", err)
+	}
+	if n.InstanceID != "instance-a-1" || n.AutoScalingGroupName != "mig-a" || n.AvailabilityZone != testZone || n.InstanceType != "e2-medium" {
+		t.Errorf("instance is not converted correctly: %#v", n)
+	}
+
+	n, err = g.InstanceForNode(&operatorv1alpha1.AWSNode{Name: "standalone"})
+	if err != nil || n != nil {
+		t.Errorf("instance which is not managed by groups should be ignored, but returned %#v, %v", n, err)
+	}
+
+	nodes, err := g.DescribeInstances([]string{"instance-a-2", "standalone", "instance-a-1"})
+	if err != nil {
+		t.Fatalf("failed to describe instances: %v", err)
+	}
+	var names []string
+	for _, node := range nodes {
+		names = append(names, node.Name)
+	}
+	sort.Strings(names)
+	if !reflect.DeepEqual(names, []string{"instance-a-1", "instance-a-2"}) {
+		t.Errorf("only managed instances should be returned, but returned %v", names)
+	}
+}
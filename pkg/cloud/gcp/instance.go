@@ -0,0 +1,124 @@
+package gcp
+
+import (
+	"net/http"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+)
+
+// createdByKey is a metadata key which has the instance group manager of the instance.
+const createdByKey = "created-by"
+
+type instance struct {
+	Name              string           `json:"name"`
+	Zone              string           `json:"zone"`
+	MachineType       string           `json:"machineType"`
+	Status            string           `json:"status"`
+	CreationTimestamp string           `json:"creationTimestamp"`
+	Metadata          instanceMetadata `json:"metadata"`
+}
+
+type instanceMetadata struct {
+	Items []metadataItem `json:"items"`
+}
+
+type metadataItem struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// TerminateInstance deletes the instance, and the instance group creates a new instance instead of it.
+func (g *GCP) TerminateInstance(node *operatorv1alpha1.AWSNode) error {
+	if err := g.do(http.MethodDelete, g.zonalURL("instances", node.InstanceID), nil, nil); err != nil {
+		klog.Errorf("failed to delete instance %s: %v", node.InstanceID, err)
+		return err
+	}
+	return nil
+}
+
+func (g *GCP) InstanceTerminated(node *operatorv1alpha1.AWSNode) (bool, error) {
+	i, err := g.getInstance(node.InstanceID)
+	if isNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	klog.Infof("Instance %s status is %s", i.Name, i.Status)
+	// Deleting instances are stopped before they disappear.
+	return i.Status == "STOPPING" || i.Status == "TERMINATED", nil
+}
+
+// InstanceForNode finds the instance by name, because node name is same as instance name in GCE.
+func (g *GCP) InstanceForNode(node *operatorv1alpha1.AWSNode) (*operatorv1alpha1.AWSNode, error) {
+	name := node.InstanceID
+	if name == "" {
+		name = node.Name
+	}
+	i, err := g.getInstance(name)
+	if err != nil {
+		return nil, err
+	}
+	n, err := convertInstanceToAWSNode(i)
+	if err != nil {
+		klog.Warning(err)
+		return nil, nil
+	}
+	return n, nil
+}
+
+func (g *GCP) DescribeInstances(instanceIDs []string) ([]operatorv1alpha1.AWSNode, error) {
+	var nodes []operatorv1alpha1.AWSNode
+	for _, id := range instanceIDs {
+		i, err := g.getInstance(id)
+		if err != nil {
+			return nil, err
+		}
+		n, err := convertInstanceToAWSNode(i)
+		if err != nil {
+			continue
+		}
+		nodes = append(nodes, *n)
+	}
+	return nodes, nil
+}
+
+func (g *GCP) getInstance(name string) (*instance, error) {
+	i := instance{}
+	if err := g.do(http.MethodGet, g.zonalURL("instances", name), nil, &i); err != nil {
+		if !isNotFound(err) {
+			klog.Errorf("failed to get instance %s: %v", name, err)
+		}
+		return nil, err
+	}
+	return &i, nil
+}
+
+// convertInstanceToAWSNode converts the instance to AWSNode. Instance name is used as InstanceID, because Compute Engine API identifies instances by name.
+func convertInstanceToAWSNode(i *instance) (*operatorv1alpha1.AWSNode, error) {
+	var group string
+	for _, item := range i.Metadata.Items {
+		if item.Key == createdByKey {
+			group = lastSegment(item.Value)
+		}
+	}
+	if group == "" {
+		return nil, NewNotManagedInstanceErrorf("instance %s is not managed by any instance groups", i.Name)
+	}
+	creationTimestamp, err := time.Parse(time.RFC3339, i.CreationTimestamp)
+	if err != nil {
+		return nil, err
+	}
+	return &operatorv1alpha1.AWSNode{
+		Name:                 i.Name,
+		InstanceID:           i.Name,
+		AvailabilityZone:     lastSegment(i.Zone),
+		InstanceType:         lastSegment(i.MachineType),
+		AutoScalingGroupName: group,
+		CreationTimestamp:    metav1.NewTime(creationTimestamp.In(time.Local)),
+	}, nil
+}
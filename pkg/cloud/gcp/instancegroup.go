@@ -0,0 +1,156 @@
+package gcp
+
+import (
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"k8s.io/klog/v2"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+	"github.com/h3poteto/node-manager/pkg/cloud"
+)
+
+type instanceGroupManager struct {
+	Name       string `json:"name"`
+	TargetSize int64  `json:"targetSize"`
+}
+
+type managedInstance struct {
+	Instance       string `json:"instance"`
+	InstanceStatus string `json:"instanceStatus"`
+	CurrentAction  string `json:"currentAction"`
+}
+
+type managedInstanceList struct {
+	ManagedInstances []managedInstance `json:"managedInstances"`
+	NextPageToken    string            `json:"nextPageToken"`
+}
+
+type instanceReferences struct {
+	Instances []string `json:"instances"`
+}
+
+var _ cloud.Provider = &GCP{}
+
+func (g *GCP) DescribeNodeGroups(groups []operatorv1alpha1.AutoScalingGroup) ([]cloud.NodeGroup, error) {
+	var nodeGroups []cloud.NodeGroup
+	for _, group := range groups {
+		igm := instanceGroupManager{}
+		if err := g.do(http.MethodGet, g.zonalURL("instanceGroupManagers", group.Name), nil, &igm); err != nil {
+			klog.Errorf("failed to get instance group manager %s: %v", group.Name, err)
+			return nil, err
+		}
+		instances, err := g.listManagedInstances(group.Name)
+		if err != nil {
+			return nil, err
+		}
+		var instanceIDs []string
+		for _, instance := range instances {
+			instanceIDs = append(instanceIDs, lastSegment(instance.Instance))
+		}
+		nodeGroups = append(nodeGroups, cloud.NodeGroup{
+			Name: igm.Name,
+			// Managed Instance Group does not have size limits, they belong to an autoscaler.
+			MinSize:         0,
+			MaxSize:         math.MaxInt32,
+			DesiredCapacity: int(igm.TargetSize),
+			InstanceIDs:     instanceIDs,
+		})
+	}
+	return nodeGroups, nil
+}
+
+func (g *GCP) listManagedInstances(name string) ([]managedInstance, error) {
+	var instances []managedInstance
+	pageToken := ""
+	for {
+		query := url.Values{}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		list := managedInstanceList{}
+		u := g.zonalURL("instanceGroupManagers", name, "listManagedInstances")
+		if len(query) > 0 {
+			u += "?" + query.Encode()
+		}
+		if err := g.do(http.MethodPost, u, nil, &list); err != nil {
+			klog.Errorf("failed to list managed instances in %s: %v", name, err)
+			return nil, err
+		}
+		instances = append(instances, list.ManagedInstances...)
+		if list.NextPageToken == "" {
+			return instances, nil
+		}
+		pageToken = list.NextPageToken
+	}
+}
+
+func (g *GCP) ScaleUpNodeGroups(groups []operatorv1alpha1.AutoScalingGroup, totalDesired int, currentNodesCount int) error {
+	nodeGroups, err := g.DescribeNodeGroups(groups)
+	if err != nil {
+		return err
+	}
+	resized, err := cloud.PlanScaleUp(nodeGroups, totalDesired, currentNodesCount)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	klog.Infof("spec desired is %d, and current nodes count is %d, so increase target size of instance groups", totalDesired, currentNodesCount)
+	return g.resizeAll(resized)
+}
+
+func (g *GCP) ScaleDownNodeGroups(groups []operatorv1alpha1.AutoScalingGroup, totalDesired int, currentNodesCount int) error {
+	nodeGroups, err := g.DescribeNodeGroups(groups)
+	if err != nil {
+		return err
+	}
+	resized, err := cloud.PlanScaleDown(nodeGroups, totalDesired, currentNodesCount)
+	if len(resized) > 0 {
+		klog.Infof("spec desired is %d, and current nodes count is %d, so decrease target size of instance groups", totalDesired, currentNodesCount)
+		if e := g.resizeAll(resized); e != nil && err == nil {
+			err = e
+		}
+	}
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (g *GCP) resizeAll(groups []cloud.NodeGroup) error {
+	var err []error
+	for i := range groups {
+		if e := g.resize(groups[i].Name, groups[i].DesiredCapacity); e != nil {
+			err = append(err, e)
+		}
+	}
+	if len(err) > 0 {
+		return err[0]
+	}
+	return nil
+}
+
+func (g *GCP) resize(name string, size int) error {
+	u := g.zonalURL("instanceGroupManagers", name, "resize") + "?size=" + strconv.Itoa(size)
+	if err := g.do(http.MethodPost, u, nil, nil); err != nil {
+		klog.Errorf("failed to resize instance group %s: %v", name, err)
+		return err
+	}
+	klog.Infof("updated target size of instance group %s to %d", name, size)
+	return nil
+}
+
+// DetachInstance abandons the instance from the instance group, so target size of the group is decremented.
+func (g *GCP) DetachInstance(node *operatorv1alpha1.AWSNode) error {
+	in := instanceReferences{
+		Instances: []string{g.zonalPath("instances", node.InstanceID)},
+	}
+	if err := g.do(http.MethodPost, g.zonalURL("instanceGroupManagers", node.AutoScalingGroupName, "abandonInstances"), in, nil); err != nil {
+		klog.Errorf("failed to abandon instance %s from %s: %v", node.InstanceID, node.AutoScalingGroupName, err)
+		return err
+	}
+	return nil
+}
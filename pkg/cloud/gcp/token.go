@@ -0,0 +1,64 @@
+package gcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	metadataHostEnv     = "GCE_METADATA_HOST"
+	defaultMetadataHost = "metadata.google.internal"
+)
+
+// MetadataTokenSource issues access tokens of the default service account from the metadata server.
+type MetadataTokenSource struct {
+	Client *http.Client
+	Host   string
+}
+
+var _ oauth2.TokenSource = &MetadataTokenSource{}
+
+func NewMetadataTokenSource() *MetadataTokenSource {
+	host := os.Getenv(metadataHostEnv)
+	if host == "" {
+		host = defaultMetadataHost
+	}
+	return &MetadataTokenSource{
+		Client: &http.Client{Timeout: 10 * time.Second},
+		Host:   host,
+	}
+}
+
+func (s *MetadataTokenSource) Token() (*oauth2.Token, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://%s/computeMetadata/v1/instance/service-accounts/default/token", s.Host), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	res, err := s.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("metadata server returned %s", res.Status)
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+		TokenType   string `json:"token_type"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&token); err != nil {
+		return nil, err
+	}
+	return &oauth2.Token{
+		AccessToken: token.AccessToken,
+		TokenType:   token.TokenType,
+		Expiry:      time.Now().Add(time.Duration(token.ExpiresIn) * time.Second),
+	}, nil
+}
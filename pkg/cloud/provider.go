@@ -0,0 +1,137 @@
+package cloud
+
+import (
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+)
+
+// NodeGroup is a group of instances which is scaled by a cloud provider, like AutoScalingGroup in AWS.
+type NodeGroup struct {
+	Name            string
+	MinSize         int
+	MaxSize         int
+	DesiredCapacity int
+	InstanceIDs     []string
+}
+
+// Config is a set of parameters to build a Provider.
+type Config struct {
+	Region         string
+	RoleARN        string
+	ExternalID     string
+	Endpoint       string
+	Project        string
+	Zone           string
+	SubscriptionID string
+	ResourceGroup  string
+	Namespace      string
+}
+
+// NewConfig returns the cloud provider name and Config for the target of resources.
+func NewConfig(target operatorv1alpha1.CloudTarget, region string) (string, Config) {
+	name := target.CloudProvider
+	if name == "" {
+		name = operatorv1alpha1.CloudProviderAWS
+	}
+	config := Config{
+		Region: region,
+	}
+	if target.AWS != nil {
+		config.RoleARN = target.AWS.RoleARN
+		config.ExternalID = target.AWS.ExternalID
+		config.Endpoint = target.AWS.Endpoint
+	}
+	if target.GCP != nil {
+		config.Project = target.GCP.Project
+		config.Zone = target.GCP.Zone
+	}
+	if target.Azure != nil {
+		config.SubscriptionID = target.Azure.SubscriptionID
+		config.ResourceGroup = target.Azure.ResourceGroup
+	}
+	if target.ClusterAPI != nil {
+		config.Namespace = target.ClusterAPI.Namespace
+	}
+	return name, config
+}
+
+// Provider is an interface to operate node groups in a cloud provider.
+// Controllers use only this interface, so a new cloud provider can be added without changing phases of controllers.
+type Provider interface {
+	// DescribeNodeGroups returns current state of the node groups.
+	DescribeNodeGroups(groups []operatorv1alpha1.AutoScalingGroup) ([]NodeGroup, error)
+	// ScaleUpNodeGroups increases desired capacity of the node groups equally until it reaches totalDesired.
+	ScaleUpNodeGroups(groups []operatorv1alpha1.AutoScalingGroup, totalDesired int, currentNodesCount int) error
+	// ScaleDownNodeGroups decreases desired capacity of the node groups equally until it reaches totalDesired.
+	ScaleDownNodeGroups(groups []operatorv1alpha1.AutoScalingGroup, totalDesired int, currentNodesCount int) error
+	// TerminateInstance terminates the instance of the node.
+	TerminateInstance(node *operatorv1alpha1.AWSNode) error
+	// DetachInstance detaches the instance of the node from its node group, and decrements desired capacity.
+	DetachInstance(node *operatorv1alpha1.AWSNode) error
+	// InstanceTerminated returns true when the instance of the node has already been terminated.
+	InstanceTerminated(node *operatorv1alpha1.AWSNode) (bool, error)
+	// InstanceForNode finds the instance of the Kubernetes node.
+	// It returns nil without error when the instance is found but it is not managed by a node group.
+	InstanceForNode(node *operatorv1alpha1.AWSNode) (*operatorv1alpha1.AWSNode, error)
+	// DescribeInstances returns instances which have the instance IDs.
+	DescribeInstances(instanceIDs []string) ([]operatorv1alpha1.AWSNode, error)
+}
+
+// NodeGroupDiscoverer is implemented by providers which can find node groups by their tags.
+type NodeGroupDiscoverer interface {
+	// DiscoverNodeGroups returns node groups which have all of the tags. A tag with an empty value matches any value.
+	DiscoverNodeGroups(tags map[string]string) ([]operatorv1alpha1.AutoScalingGroup, error)
+}
+
+// InstanceRefresh is the state of a refresh which replaces all instances of a node group in the cloud provider.
+type InstanceRefresh struct {
+	ID string
+	// Status is the status reported by the cloud provider.
+	Status             string
+	StatusReason       string
+	PercentageComplete int
+	InstancesToUpdate  int
+	// Finished is true when the refresh is not running anymore, regardless of whether it succeeded.
+	Finished  bool
+	Succeeded bool
+}
+
+// InstanceRefresher is implemented by providers which can replace instances of node groups by themselves, like Instance Refresh of EC2 Auto Scaling.
+type InstanceRefresher interface {
+	// StartInstanceRefresh starts to replace instances of the node group, and returns the ID of the refresh.
+	// When a refresh is already running in the node group, it returns the ID of the refresh instead of starting a new one.
+	StartInstanceRefresh(group string, preferences *operatorv1alpha1.InstanceRefreshPreferences) (string, error)
+	// DescribeInstanceRefresh returns the current state of the refresh.
+	DescribeInstanceRefresh(group string, id string) (*InstanceRefresh, error)
+	// TerminationWaitingInstances returns IDs of instances in the node group which wait for the termination lifecycle hook.
+	TerminationWaitingInstances(group string) ([]string, error)
+	// CompleteTerminationLifecycleAction lets the instance which waits for the lifecycle hook be terminated.
+	CompleteTerminationLifecycleAction(group string, hook string, instanceID string) error
+}
+
+// CapacityRestorer is implemented by providers which decrease capacity of a node group when an instance is terminated, like Azure scale sets.
+type CapacityRestorer interface {
+	// RestoreCapacity increases capacity of the node groups until the sum reaches total.
+	// It does nothing when the sum already reaches total, so it can be called repeatedly.
+	RestoreCapacity(groups []operatorv1alpha1.AutoScalingGroup, total int) error
+}
+
+// DriftDetector is implemented by providers which can tell instances whose configuration is out of date in node groups.
+type DriftDetector interface {
+	// DriftedInstances returns IDs of instances in service which do not match the current configuration of their node groups,
+	// with the reason of the drift.
+	DriftedInstances(groups []operatorv1alpha1.AutoScalingGroup) (map[string]string, error)
+}
+
+// ParameterReader is implemented by providers which have a store of parameters, like SSM Parameter Store where AMIs are published.
+type ParameterReader interface {
+	// GetParameter returns the current value of the parameter.
+	GetParameter(name string) (string, error)
+}
+
+// InstanceTypeResolver is implemented by providers which can tell instance types which node groups launch new instances with.
+type InstanceTypeResolver interface {
+	// LaunchInstanceTypes returns instance types of new instances keyed by the node group name.
+	// A node group which launches several types, like a mixed instances policy, has all of them,
+	// and a node group is omitted when its types are not known.
+	LaunchInstanceTypes(groups []operatorv1alpha1.AutoScalingGroup) (map[string][]string, error)
+}
@@ -0,0 +1,38 @@
+package cloud
+
+import (
+	"sync"
+)
+
+// Factory builds a Provider from the config.
+type Factory func(config Config) (Provider, error)
+
+// Registry holds Factory for each cloud provider, which is specified in CloudProvider of NodeManager.
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[string]Factory
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		factories: map[string]Factory{},
+	}
+}
+
+// Register registers the factory for the cloud provider. It overwrites the factory if it is already registered.
+func (r *Registry) Register(name string, factory Factory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.factories[name] = factory
+}
+
+// Provider builds a Provider for the cloud provider.
+func (r *Registry) Provider(name string, config Config) (Provider, error) {
+	r.mu.RLock()
+	factory, ok := r.factories[name]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, NewUnknownProviderErrorf("cloud provider %s is not registered", name)
+	}
+	return factory(config)
+}
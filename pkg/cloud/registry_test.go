@@ -0,0 +1,36 @@
+package cloud
+
+import (
+	"errors"
+	"testing"
+)
+
+type fakeProvider struct {
+	Provider
+	config Config
+}
+
+func TestRegistryProvider(t *testing.T) {
+	r := NewRegistry()
+	r.Register("fake", func(config Config) (Provider, error) {
+		return &fakeProvider{config: config}, nil
+	})
+
+	p, err := r.Provider("fake", Config{Region: "us-east-1"})
+	if err != nil {
+		t.Fatalf("failed to build provider: %v", err)
+	}
+	fake, ok := p.(*fakeProvider)
+	if !ok {
+		t.Fatalf("provider is not built by the registered factory: %#v", p)
+	}
+	if fake.config.Region != "us-east-1" {
+		t.Errorf("config is not passed to the factory: %#v", fake.config)
+	}
+
+	_, err = r.Provider("unknown", Config{})
+	var unknownErr *UnknownProviderError
+	if !errors.As(err, &unknownErr) {
+		t.Errorf("unknown provider should return UnknownProviderError, but returned %v", err)
+	}
+}
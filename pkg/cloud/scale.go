@@ -0,0 +1,115 @@
+package cloud
+
+import (
+	"errors"
+	"sort"
+)
+
+// PlanScaleUp calculates new desired capacity of node groups to add instances equally across them.
+// It returns only node groups which should be resized, and these have new DesiredCapacity.
+// Cloud providers which do not have their own scaling logic use it.
+func PlanScaleUp(groups []NodeGroup, totalDesired int, currentNodesCount int) ([]NodeGroup, error) {
+	if totalDesired <= currentNodesCount {
+		return nil, NewDesiredInvalidErrorf("desired does not exceed current, totalDesired: %d, currentNodesCount: %d", totalDesired, currentNodesCount)
+	}
+	sumInstances := 0
+	// safetyGroups have same value desired capacity and current instances count.
+	var safetyGroups []NodeGroup
+	for _, group := range groups {
+		sumInstances += len(group.InstanceIDs)
+		if group.DesiredCapacity == len(group.InstanceIDs) {
+			safetyGroups = append(safetyGroups, group)
+		}
+	}
+	if currentNodesCount != sumInstances {
+		return nil, NewInstanceNotYetJoinErrorf("not all instances join the cluster as nodes, all instances: %d, current nodes: %d", sumInstances, currentNodesCount)
+	}
+	if len(safetyGroups) < 1 {
+		return nil, errors.New("there are no safety node groups, so could not add instances")
+	}
+
+	sort.SliceStable(safetyGroups, func(i, j int) bool {
+		return (safetyGroups[i].MaxSize - safetyGroups[i].DesiredCapacity) > (safetyGroups[j].MaxSize - safetyGroups[j].DesiredCapacity)
+	})
+
+	surplus := totalDesired - currentNodesCount
+	for surplus > 0 {
+		resized := false
+		for i := range safetyGroups {
+			if surplus < 1 {
+				break
+			}
+			if safetyGroups[i].MaxSize-safetyGroups[i].DesiredCapacity > 0 {
+				safetyGroups[i].DesiredCapacity += 1
+				surplus--
+				resized = true
+			}
+		}
+		// Exit this loop when all node groups are fullfilled.
+		if !resized {
+			break
+		}
+	}
+	return safetyGroups, nil
+}
+
+// PlanScaleDown calculates new desired capacity of node groups to delete instances equally across them.
+// It returns only node groups which should be resized, and these have new DesiredCapacity.
+// When desired capacity of a node group differs from its instances count, the node group is resized to the count at first.
+func PlanScaleDown(groups []NodeGroup, totalDesired int, currentNodesCount int) ([]NodeGroup, error) {
+	if totalDesired >= currentNodesCount {
+		return nil, NewDesiredInvalidErrorf("desired exceeds current, totalDesired: %d, currentNodesCount: %d", totalDesired, currentNodesCount)
+	}
+	sumInstances := 0
+	var safetyGroups []NodeGroup
+	// unsafetyGroups have different value desired capacity and current instances count.
+	var unsafetyGroups []NodeGroup
+	for _, group := range groups {
+		sumInstances += len(group.InstanceIDs)
+		if group.DesiredCapacity != len(group.InstanceIDs) {
+			unsafetyGroups = append(unsafetyGroups, group)
+		} else {
+			safetyGroups = append(safetyGroups, group)
+		}
+	}
+	if currentNodesCount != sumInstances {
+		return nil, NewInstanceNotYetJoinErrorf("not all instances join the cluster as nodes, all instances: %d, current nodes: %d", sumInstances, currentNodesCount)
+	}
+
+	var resized []NodeGroup
+	if len(unsafetyGroups) > 0 {
+		sort.SliceStable(unsafetyGroups, func(i, j int) bool {
+			return (unsafetyGroups[i].DesiredCapacity - unsafetyGroups[i].MinSize) > (unsafetyGroups[j].DesiredCapacity - unsafetyGroups[j].MinSize)
+		})
+		target := unsafetyGroups[0]
+		target.DesiredCapacity = len(target.InstanceIDs)
+		resized = append(resized, target)
+	}
+	if len(safetyGroups) < 1 {
+		return resized, errors.New("there are no safety node groups, so could not delete instances")
+	}
+
+	sort.SliceStable(safetyGroups, func(i, j int) bool {
+		return (safetyGroups[i].DesiredCapacity - safetyGroups[i].MinSize) > (safetyGroups[j].DesiredCapacity - safetyGroups[j].MinSize)
+	})
+
+	surplus := currentNodesCount - totalDesired
+	for surplus > 0 {
+		decreased := false
+		for i := range safetyGroups {
+			if surplus < 1 {
+				break
+			}
+			if safetyGroups[i].DesiredCapacity-safetyGroups[i].MinSize > 0 {
+				safetyGroups[i].DesiredCapacity -= 1
+				surplus--
+				decreased = true
+			}
+		}
+		// Exit this loop when all node groups are minimized.
+		if !decreased {
+			break
+		}
+	}
+	return append(resized, safetyGroups...), nil
+}
@@ -0,0 +1,137 @@
+package cloud
+
+import (
+	"errors"
+	"log"
+	"reflect"
+	"testing"
+)
+
+func TestPlanScaleUp(t *testing.T) {
+	cases := []struct {
+		title         string
+		groups        []NodeGroup
+		totalDesired  int
+		currentNodes  int
+		expected      []NodeGroup
+		expectedError error
+	}{
+		{
+			title: "Distribute instances equally",
+			groups: []NodeGroup{
+				{Name: "a", MaxSize: 5, DesiredCapacity: 1, InstanceIDs: []string{"i-1"}},
+				{Name: "b", MaxSize: 5, DesiredCapacity: 1, InstanceIDs: []string{"i-2"}},
+			},
+			totalDesired: 5,
+			currentNodes: 2,
+			expected: []NodeGroup{
+				{Name: "a", MaxSize: 5, DesiredCapacity: 3, InstanceIDs: []string{"i-1"}},
+				{Name: "b", MaxSize: 5, DesiredCapacity: 2, InstanceIDs: []string{"i-2"}},
+			},
+		},
+		{
+			title: "Skip groups which are not stable",
+			groups: []NodeGroup{
+				{Name: "a", MaxSize: 5, DesiredCapacity: 2, InstanceIDs: []string{"i-1"}},
+				{Name: "b", MaxSize: 2, DesiredCapacity: 1, InstanceIDs: []string{"i-2"}},
+			},
+			totalDesired: 4,
+			currentNodes: 2,
+			expected: []NodeGroup{
+				{Name: "b", MaxSize: 2, DesiredCapacity: 2, InstanceIDs: []string{"i-2"}},
+			},
+		},
+		{
+			title: "Instances do not join",
+			groups: []NodeGroup{
+				{Name: "a", MaxSize: 5, DesiredCapacity: 2, InstanceIDs: []string{"i-1", "i-2"}},
+			},
+			totalDesired:  3,
+			currentNodes:  1,
+			expectedError: NewInstanceNotYetJoinErrorf("not all instances join the cluster as nodes, all instances: %d, current nodes: %d", 2, 1),
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		groups, err := PlanScaleUp(c.groups, c.totalDesired, c.currentNodes)
+		if c.expectedError != nil {
+			if !errors.Is(err, c.expectedError) {
+				t.Errorf("CASE: %s : error %v is not expectedError %v", c.title, err, c.expectedError)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("CASE: %s : %v", c.title, err)
+			continue
+		}
+		if !reflect.DeepEqual(groups, c.expected) {
+			t.Errorf("CASE: %s : expected %#v, but got %#v", c.title, c.expected, groups)
+		}
+	}
+}
+
+func TestPlanScaleDown(t *testing.T) {
+	cases := []struct {
+		title         string
+		groups        []NodeGroup
+		totalDesired  int
+		currentNodes  int
+		expected      []NodeGroup
+		expectedError error
+	}{
+		{
+			title: "Remove instances equally",
+			groups: []NodeGroup{
+				{Name: "a", MinSize: 0, MaxSize: 5, DesiredCapacity: 2, InstanceIDs: []string{"i-1", "i-2"}},
+				{Name: "b", MinSize: 0, MaxSize: 5, DesiredCapacity: 2, InstanceIDs: []string{"i-3", "i-4"}},
+			},
+			totalDesired: 2,
+			currentNodes: 4,
+			expected: []NodeGroup{
+				{Name: "a", MinSize: 0, MaxSize: 5, DesiredCapacity: 1, InstanceIDs: []string{"i-1", "i-2"}},
+				{Name: "b", MinSize: 0, MaxSize: 5, DesiredCapacity: 1, InstanceIDs: []string{"i-3", "i-4"}},
+			},
+		},
+		{
+			title: "Fix a group which is not stable",
+			groups: []NodeGroup{
+				{Name: "a", MinSize: 0, MaxSize: 5, DesiredCapacity: 3, InstanceIDs: []string{"i-1", "i-2"}},
+				{Name: "b", MinSize: 1, MaxSize: 5, DesiredCapacity: 2, InstanceIDs: []string{"i-3", "i-4"}},
+			},
+			totalDesired: 3,
+			currentNodes: 4,
+			expected: []NodeGroup{
+				{Name: "a", MinSize: 0, MaxSize: 5, DesiredCapacity: 2, InstanceIDs: []string{"i-1", "i-2"}},
+				{Name: "b", MinSize: 1, MaxSize: 5, DesiredCapacity: 1, InstanceIDs: []string{"i-3", "i-4"}},
+			},
+		},
+		{
+			title: "Desired exceeds current",
+			groups: []NodeGroup{
+				{Name: "a", MaxSize: 5, DesiredCapacity: 1, InstanceIDs: []string{"i-1"}},
+			},
+			totalDesired:  2,
+			currentNodes:  1,
+			expectedError: NewDesiredInvalidErrorf("desired exceeds current, totalDesired: %d, currentNodesCount: %d", 2, 1),
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		groups, err := PlanScaleDown(c.groups, c.totalDesired, c.currentNodes)
+		if c.expectedError != nil {
+			if !errors.Is(err, c.expectedError) {
+				t.Errorf("CASE: %s : error %v is not expectedError %v", c.title, err, c.expectedError)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("CASE: %s : %v", c.title, err)
+			continue
+		}
+		if !reflect.DeepEqual(groups, c.expected) {
+			t.Errorf("CASE: %s : expected %#v, but got %#v", c.title, c.expected, groups)
+		}
+	}
+}
@@ -13,8 +13,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// refreshEnabled returns whether any of the schedule, drift trigger, amiSource, maxNodeAge, maintenanceWindow or refreshNow starts a refresh.
+func refreshEnabled(spec *operatorv1alpha1.AWSNodeManagerSpec) bool {
+	return spec.RefreshSchedule != "" || spec.RefreshTrigger == operatorv1alpha1.RefreshTriggerDrift || spec.AMISource != nil || spec.MaxNodeAge != nil || spec.MaintenanceWindow != nil || spec.RefreshNow != ""
+}
+
 func (r *AWSNodeManagerReconciler) syncAWSNodeRefresher(ctx context.Context, awsNodeManager *operatorv1alpha1.AWSNodeManager) (*operatorv1alpha1.AWSNodeRefresher, error) {
-	if awsNodeManager.Spec.RefreshSchedule == "" {
+	if !refreshEnabled(&awsNodeManager.Spec) {
 		return nil, nil
 	}
 	klog.Info(ctx, "checking if an existing AWSNodeRefresher")
@@ -75,9 +80,14 @@ func (r *AWSNodeManagerReconciler) createAWSNodeRefresher(ctx context.Context, a
 
 func (r *AWSNodeManagerReconciler) updateAWSNodeRefresher(ctx context.Context, existingRefresher *operatorv1alpha1.AWSNodeRefresher, awsNodeManager *operatorv1alpha1.AWSNodeManager) (*operatorv1alpha1.AWSNodeRefresher, error) {
 	newRefresher := generateAWSNodeRefresher(awsNodeManager)
-	if reflect.DeepEqual(existingRefresher.Spec, newRefresher.Spec) && reflect.DeepEqual(existingRefresher.Status.AWSNodes, newRefresher.Status.AWSNodes) {
+	refreshNow := awsNodeManager.Spec.RefreshNow
+	requested := refreshNow != "" && existingRefresher.GetAnnotations()[operatorv1alpha1.RefreshNowAnnotation] != refreshNow
+	if reflect.DeepEqual(existingRefresher.Spec, newRefresher.Spec) && reflect.DeepEqual(existingRefresher.Status.AWSNodes, newRefresher.Status.AWSNodes) && !requested {
 		return existingRefresher, nil
 	}
+	if requested {
+		metav1.SetMetaDataAnnotation(&existingRefresher.ObjectMeta, operatorv1alpha1.RefreshNowAnnotation, refreshNow)
+	}
 	existingRefresher.Spec = newRefresher.Spec
 	existingRefresher.Status.AWSNodes = newRefresher.Status.AWSNodes
 	existingRefresher.Status.Revision += 1
@@ -90,26 +100,51 @@ func (r *AWSNodeManagerReconciler) updateAWSNodeRefresher(ctx context.Context, e
 	return existingRefresher, nil
 }
 
+// refresherAnnotations returns annotations of AWSNodeManager with the refresh-now annotation of refreshNow.
+func refresherAnnotations(awsNodeManager *operatorv1alpha1.AWSNodeManager) map[string]string {
+	if awsNodeManager.Spec.RefreshNow == "" {
+		return awsNodeManager.GetAnnotations()
+	}
+	annotations := map[string]string{}
+	for k, v := range awsNodeManager.GetAnnotations() {
+		annotations[k] = v
+	}
+	annotations[operatorv1alpha1.RefreshNowAnnotation] = awsNodeManager.Spec.RefreshNow
+	return annotations
+}
+
 func generateAWSNodeRefresher(awsNodeManager *operatorv1alpha1.AWSNodeManager) *operatorv1alpha1.AWSNodeRefresher {
 	return &operatorv1alpha1.AWSNodeRefresher{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        awsNodeManager.Name,
 			Namespace:   awsNodeManager.Namespace,
 			Labels:      awsNodeManager.GetLabels(),
-			Annotations: awsNodeManager.GetAnnotations(),
+			Annotations: refresherAnnotations(awsNodeManager),
 			OwnerReferences: []metav1.OwnerReference{
 				*metav1.NewControllerRef(awsNodeManager, awsNodeManager.GroupVersionKind()),
 			},
 		},
 		Spec: operatorv1alpha1.AWSNodeRefresherSpec{
+			CloudTarget:              awsNodeManager.Spec.CloudTarget,
 			Region:                   awsNodeManager.Spec.Region,
-			AutoScalingGroups:        awsNodeManager.Spec.AutoScalingGroups,
+			AutoScalingGroups:        awsNodeManager.Status.AutoScalingGroups,
 			Desired:                  awsNodeManager.Spec.Desired,
 			ASGModifyCoolTimeSeconds: awsNodeManager.Spec.ASGModifyCoolTimeSeconds,
 			Role:                     awsNodeManager.Spec.Role,
 			Schedule:                 awsNodeManager.Spec.RefreshSchedule,
 			SurplusNodes:             awsNodeManager.Spec.SurplusNodes,
 			DrainGracePeriodSeconds:  awsNodeManager.Spec.DrainGracePeriodSeconds,
+			Strategy:                 awsNodeManager.Spec.RefreshStrategy,
+			InstanceRefresh:          awsNodeManager.Spec.InstanceRefresh,
+			Trigger:                  awsNodeManager.Spec.RefreshTrigger,
+			AMISource:                awsNodeManager.Spec.AMISource,
+			MaxNodeAge:               awsNodeManager.Spec.MaxNodeAge,
+			MaintenanceWindow:        awsNodeManager.Spec.MaintenanceWindow,
+			Suspend:                  awsNodeManager.Spec.SuspendRefresh,
+			Timeouts:                 awsNodeManager.Spec.RefreshTimeouts,
+			MaxSurge:                 awsNodeManager.Spec.MaxSurge,
+			MaxUnavailable:           awsNodeManager.Spec.MaxUnavailable,
+			ReplacementOrder:         awsNodeManager.Spec.ReplacementOrder,
 		},
 		Status: operatorv1alpha1.AWSNodeRefresherStatus{
 			AWSNodes: awsNodeManager.Status.AWSNodes,
@@ -0,0 +1,80 @@
+package awsnodemanager
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestSyncAWSNodeRefresher(t *testing.T) {
+	cases := []struct {
+		title              string
+		spec               operatorv1alpha1.AWSNodeManagerSpec
+		expectedCreated    bool
+		expectedRefreshNow string
+	}{
+		{
+			title: "Refresh is scheduled",
+			spec: operatorv1alpha1.AWSNodeManagerSpec{
+				RefreshSchedule: "0 3 * * *",
+			},
+			expectedCreated: true,
+		},
+		{
+			title: "Only refreshNow is specified",
+			spec: operatorv1alpha1.AWSNodeManagerSpec{
+				RefreshNow: "2026-10-17T10:00:00Z",
+			},
+			expectedCreated:    true,
+			expectedRefreshNow: "2026-10-17T10:00:00Z",
+		},
+		{
+			title:           "Refresh is not configured",
+			spec:            operatorv1alpha1.AWSNodeManagerSpec{},
+			expectedCreated: false,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		ctx := context.Background()
+		manager := &operatorv1alpha1.AWSNodeManager{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-manager",
+				Namespace: "default",
+			},
+			Spec: c.spec,
+		}
+		mockedClient := &mockedClient{
+			getFunc: func(obj client.Object) error {
+				return apierrors.NewNotFound(schema.GroupResource{Group: operatorv1alpha1.GroupVersion.Group, Resource: "awsnoderefreshers"}, "test-manager")
+			},
+		}
+		r := &AWSNodeManagerReconciler{
+			Client:   mockedClient,
+			Recorder: &mockedRecorder{},
+		}
+
+		refresher, err := r.syncAWSNodeRefresher(ctx, manager)
+		if err != nil {
+			t.Errorf("CASE: %s : %v", c.title, err)
+			continue
+		}
+		if created := mockedClient.createdObj != nil; created != c.expectedCreated {
+			t.Errorf("CASE: %s : created is not matched, expected %t, but returned %t", c.title, c.expectedCreated, created)
+			continue
+		}
+		if !c.expectedCreated {
+			continue
+		}
+		if value := refresher.GetAnnotations()[operatorv1alpha1.RefreshNowAnnotation]; value != c.expectedRefreshNow {
+			t.Errorf("CASE: %s : refresh-now annotation is not matched, expected %q, but returned %q", c.title, c.expectedRefreshNow, value)
+		}
+	}
+}
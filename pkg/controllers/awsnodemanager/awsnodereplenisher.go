@@ -105,8 +105,9 @@ func generateAWSNodeReplenisher(awsNodeManager *operatorv1alpha1.AWSNodeManager)
 			},
 		},
 		Spec: operatorv1alpha1.AWSNodeReplenisherSpec{
+			CloudTarget:              awsNodeManager.Spec.CloudTarget,
 			Region:                   awsNodeManager.Spec.Region,
-			AutoScalingGroups:        awsNodeManager.Spec.AutoScalingGroups,
+			AutoScalingGroups:        awsNodeManager.Status.AutoScalingGroups,
 			Desired:                  awsNodeManager.Spec.Desired,
 			ASGModifyCoolTimeSeconds: awsNodeManager.Spec.ASGModifyCoolTimeSeconds,
 			Role:                     awsNodeManager.Spec.Role,
@@ -0,0 +1,62 @@
+package awsnodemanager
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+	"github.com/h3poteto/node-manager/pkg/util/conditions"
+	"github.com/h3poteto/node-manager/pkg/util/klog"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// updateConditions reflects the current phase and the result of sync to status conditions.
+func (r *AWSNodeManagerReconciler) updateConditions(ctx context.Context, awsNodeManager *operatorv1alpha1.AWSNodeManager, syncErr error) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := operatorv1alpha1.AWSNodeManager{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: awsNodeManager.Namespace, Name: awsNodeManager.Name}, &current); err != nil {
+			klog.Errorf(ctx, "failed to get AWSNodeManager %s/%s: %v", awsNodeManager.Namespace, awsNodeManager.Name, err)
+			return err
+		}
+		before := current.Status.DeepCopy()
+		setConditions(&current, syncErr)
+		if reflect.DeepEqual(before.Conditions, current.Status.Conditions) {
+			return nil
+		}
+		if err := r.Client.Update(ctx, &current); err != nil {
+			klog.Errorf(ctx, "failed to update AWSNodeManager conditions %s/%s: %v", current.Namespace, current.Name, err)
+			return err
+		}
+		return nil
+	})
+}
+
+func setConditions(awsNodeManager *operatorv1alpha1.AWSNodeManager, syncErr error) {
+	generation := awsNodeManager.Generation
+	c := &awsNodeManager.Status.Conditions
+	switch awsNodeManager.Status.Phase {
+	case "", operatorv1alpha1.AWSNodeManagerInit:
+		conditions.Set(c, generation, operatorv1alpha1.ConditionReady, metav1.ConditionFalse, operatorv1alpha1.ReasonInitializing, "Nodes are not synced yet")
+	case operatorv1alpha1.AWSNodeManagerSynced:
+		message := fmt.Sprintf("%d nodes are running", len(awsNodeManager.Status.AWSNodes))
+		conditions.Set(c, generation, operatorv1alpha1.ConditionReady, metav1.ConditionTrue, operatorv1alpha1.ReasonSynced, message)
+	case operatorv1alpha1.AWSNodeManagerRefreshing:
+		conditions.Set(c, generation, operatorv1alpha1.ConditionReady, metav1.ConditionFalse, operatorv1alpha1.ReasonRefreshing, "Nodes are being refreshed")
+	case operatorv1alpha1.AWSNodeManagerReplenishing:
+		conditions.Set(c, generation, operatorv1alpha1.ConditionReady, metav1.ConditionFalse, operatorv1alpha1.ReasonReplenishing, "Nodes are being replenished")
+	}
+	if awsNodeManager.Status.Phase == operatorv1alpha1.AWSNodeManagerRefreshing {
+		conditions.Set(c, generation, operatorv1alpha1.ConditionRefreshing, metav1.ConditionTrue, operatorv1alpha1.ReasonRefreshing, "Nodes are being refreshed")
+	} else {
+		conditions.Set(c, generation, operatorv1alpha1.ConditionRefreshing, metav1.ConditionFalse, operatorv1alpha1.ReasonIdle, "")
+	}
+	if awsNodeManager.Status.Phase == operatorv1alpha1.AWSNodeManagerReplenishing {
+		conditions.Set(c, generation, operatorv1alpha1.ConditionReplenishing, metav1.ConditionTrue, operatorv1alpha1.ReasonReplenishing, "Nodes are being replenished")
+	} else {
+		conditions.Set(c, generation, operatorv1alpha1.ConditionReplenishing, metav1.ConditionFalse, operatorv1alpha1.ReasonIdle, "")
+	}
+	conditions.SetSyncResult(c, generation, syncErr)
+}
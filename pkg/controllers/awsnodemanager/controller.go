@@ -21,7 +21,6 @@ import (
 	"reflect"
 	"time"
 
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -32,7 +31,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
-	cloudaws "github.com/h3poteto/node-manager/pkg/cloud/aws"
+	"github.com/h3poteto/node-manager/pkg/cloud"
 	pkgctx "github.com/h3poteto/node-manager/pkg/util/context"
 	"github.com/h3poteto/node-manager/pkg/util/externalevent"
 	"github.com/h3poteto/node-manager/pkg/util/klog"
@@ -42,10 +41,11 @@ import (
 // AWSNodeManagerReconciler reconciles a AWSNodeManager object
 type AWSNodeManagerReconciler struct {
 	client.Client
-	Log      logr.Logger
-	Scheme   *runtime.Scheme
-	Recorder record.EventRecorder
-	cloud    *cloudaws.AWS
+	Log       logr.Logger
+	Scheme    *runtime.Scheme
+	Recorder  record.EventRecorder
+	Providers *cloud.Registry
+	cloud     cloud.Provider
 }
 
 // +kubebuilder:rbac:groups=operator.h3poteto.dev,resources=awsnodemanagers,verbs=get;list;watch;create;update;patch;delete
@@ -72,19 +72,29 @@ func (r *AWSNodeManagerReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	// Generate aws client
-	sess := session.Must(session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-	}))
-	r.cloud = cloudaws.New(sess, awsNodeManager.Spec.Region)
-
-	if err := r.syncAWSNodeManager(ctx, &awsNodeManager); err != nil {
-		klog.Errorf(ctx, "failed to sync AWSNodeManager: %v", err)
-		r.Recorder.Eventf(&awsNodeManager, corev1.EventTypeWarning, "Error", "Failed to sync: %v", err)
+	providerName, config := cloud.NewConfig(awsNodeManager.Spec.CloudTarget, awsNodeManager.Spec.Region)
+	provider, err := r.Providers.Provider(providerName, config)
+	if err != nil {
+		klog.Errorf(ctx, "failed to build cloud provider: %v", err)
 		return ctrl.Result{}, err
 	}
+	// Reconciles may run concurrently, so the provider of this request is kept in a copy of the reconciler.
+	rc := *r
+	rc.cloud = provider
+
+	syncErr := rc.syncAWSNodeManager(ctx, &awsNodeManager)
+	if syncErr != nil {
+		klog.Errorf(ctx, "failed to sync AWSNodeManager: %v", syncErr)
+		r.Recorder.Eventf(&awsNodeManager, corev1.EventTypeWarning, "Error", "Failed to sync: %v", syncErr)
+	}
+	if err := r.updateConditions(ctx, &awsNodeManager, syncErr); err != nil {
+		klog.Errorf(ctx, "failed to update conditions of AWSNodeManager: %v", err)
+		if syncErr == nil {
+			return ctrl.Result{}, err
+		}
+	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{}, syncErr
 }
 
 func (r *AWSNodeManagerReconciler) SetupWithManager(mgr ctrl.Manager) error {
@@ -153,7 +163,9 @@ func (r *AWSNodeManagerReconciler) syncAWSNodeManager(ctx context.Context, awsNo
 			operatorv1alpha1.AWSNodeRefresherDraining,
 			operatorv1alpha1.AWSNodeRefresherUpdateReplacing,
 			operatorv1alpha1.AWSNodeRefresherUpdateAWSWaiting,
-			operatorv1alpha1.AWSNodeRefresherUpdateDecreasing:
+			operatorv1alpha1.AWSNodeRefresherUpdateDecreasing,
+			operatorv1alpha1.AWSNodeRefresherPaused,
+			operatorv1alpha1.AWSNodeRefresherInstanceRefreshing:
 			awsNodeManager.Status.Phase = operatorv1alpha1.AWSNodeManagerRefreshing
 		}
 		awsNodeManager.Status.NodeRefresher = &operatorv1alpha1.AWSNodeRefresherRef{
@@ -35,6 +35,12 @@ type mockedClient struct {
 	client.Client
 	getFunc    func(obj client.Object) error
 	updatedObj client.Object
+	createdObj client.Object
+}
+
+func (m *mockedClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	m.createdObj = obj
+	return nil
 }
 
 func (m *mockedClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
@@ -2,11 +2,11 @@ package awsnodemanager
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 
-	"github.com/aws/aws-sdk-go/service/autoscaling"
 	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
-	cloudaws "github.com/h3poteto/node-manager/pkg/cloud/aws"
+	"github.com/h3poteto/node-manager/pkg/cloud"
 	"github.com/h3poteto/node-manager/pkg/util/klog"
 
 	corev1 "k8s.io/api/core/v1"
@@ -17,6 +17,9 @@ func (r *AWSNodeManagerReconciler) syncAWSNodes(ctx context.Context, awsNodeMana
 	if err := reflectInstances(ctx, r.cloud, awsNodeManager); err != nil {
 		return false, err
 	}
+	if err := resolveAutoScalingGroups(r.cloud, awsNodeManager); err != nil {
+		return false, err
+	}
 	klog.Infof(ctx, "Checking not joined instances for %s/%s", awsNodeManager.Namespace, awsNodeManager.Name)
 	if err := reflectNotJoinedInstances(r.cloud, awsNodeManager); err != nil {
 		return false, err
@@ -47,19 +50,18 @@ func (r *AWSNodeManagerReconciler) syncAWSNodes(ctx context.Context, awsNodeMana
 	return true, nil
 }
 
-func reflectInstances(ctx context.Context, cloud *cloudaws.AWS, awsNodeManager *operatorv1alpha1.AWSNodeManager) error {
+func reflectInstances(ctx context.Context, provider cloud.Provider, awsNodeManager *operatorv1alpha1.AWSNodeManager) error {
 	for i := range awsNodeManager.Status.AWSNodes {
 		node := &awsNodeManager.Status.AWSNodes[i]
 		if node.InstanceID != "" {
 			continue
 		}
-		instance, err := cloud.DescribeInstance(node)
+		n, err := provider.InstanceForNode(node)
 		if err != nil {
 			return err
 		}
-		n, err := cloudaws.ConvertInstanceToAWSNode(instance)
-		if err != nil {
-			klog.Warning(ctx, err)
+		if n == nil {
+			klog.Warningf(ctx, "could not find the instance of node %s", node.Name)
 			continue
 		}
 		awsNodeManager.Status.AWSNodes[i].InstanceID = n.InstanceID
@@ -70,25 +72,61 @@ func reflectInstances(ctx context.Context, cloud *cloudaws.AWS, awsNodeManager *
 	return nil
 }
 
-func reflectNotJoinedInstances(cloud *cloudaws.AWS, awsNodeManager *operatorv1alpha1.AWSNodeManager) error {
-	groups, err := cloud.DescribeAutoScalingGroups(awsNodeManager.Spec.AutoScalingGroups)
+// resolveAutoScalingGroups records AutoScalingGroups in spec and groups which match TagSelector in status.
+// Children of the AWSNodeManager manage the groups in status, so groups which are created later are also managed.
+func resolveAutoScalingGroups(provider cloud.Provider, awsNodeManager *operatorv1alpha1.AWSNodeManager) error {
+	groups := append([]operatorv1alpha1.AutoScalingGroup{}, awsNodeManager.Spec.AutoScalingGroups...)
+	if len(awsNodeManager.Spec.TagSelector) > 0 {
+		discoverer, ok := provider.(cloud.NodeGroupDiscoverer)
+		if !ok {
+			return fmt.Errorf("cloud provider %s does not support tagSelector", awsNodeManager.Spec.CloudProvider)
+		}
+		discovered, err := discoverer.DiscoverNodeGroups(awsNodeManager.Spec.TagSelector)
+		if err != nil {
+			return err
+		}
+		for _, group := range discovered {
+			if includedGroup(group.Name, groups) {
+				continue
+			}
+			groups = append(groups, group)
+		}
+	}
+	if len(groups) == 0 {
+		return fmt.Errorf("no AutoScalingGroups match tagSelector %v", awsNodeManager.Spec.TagSelector)
+	}
+	awsNodeManager.Status.AutoScalingGroups = groups
+	return nil
+}
+
+func includedGroup(name string, groups []operatorv1alpha1.AutoScalingGroup) bool {
+	for _, group := range groups {
+		if group.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func reflectNotJoinedInstances(provider cloud.Provider, awsNodeManager *operatorv1alpha1.AWSNodeManager) error {
+	groups, err := provider.DescribeNodeGroups(awsNodeManager.Status.AutoScalingGroups)
 	if err != nil {
 		return err
 	}
-	var instanceIDs []*string
+	var instanceIDs []string
 	for _, group := range groups {
-		for _, instance := range group.Instances {
-			if includedCluster(instance, awsNodeManager.Status.AWSNodes) {
+		for _, instanceID := range group.InstanceIDs {
+			if includedCluster(instanceID, awsNodeManager.Status.AWSNodes) {
 				continue
 			}
-			instanceIDs = append(instanceIDs, instance.InstanceId)
+			instanceIDs = append(instanceIDs, instanceID)
 		}
 	}
 	if len(instanceIDs) == 0 {
 		awsNodeManager.Status.NotJoinedAWSNodes = nil
 		return nil
 	}
-	nodes, err := cloud.GetAWSNodes(instanceIDs)
+	nodes, err := provider.DescribeInstances(instanceIDs)
 	if err != nil {
 		return err
 	}
@@ -96,9 +134,9 @@ func reflectNotJoinedInstances(cloud *cloudaws.AWS, awsNodeManager *operatorv1al
 	return nil
 }
 
-func includedCluster(instance *autoscaling.Instance, awsNodes []operatorv1alpha1.AWSNode) bool {
+func includedCluster(instanceID string, awsNodes []operatorv1alpha1.AWSNode) bool {
 	for _, node := range awsNodes {
-		if *instance.InstanceId == node.InstanceID {
+		if instanceID == node.InstanceID {
 			return true
 		}
 	}
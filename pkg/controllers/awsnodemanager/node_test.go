@@ -45,7 +45,7 @@ func TestSyncAWSNodes(t *testing.T) {
 								PrivateIpAddress: aws.String("172.32.16.0"),
 								Tags: []*ec2.Tag{
 									&ec2.Tag{
-										Key:   aws.String("Name"),
+										Key:   aws.String("aws:autoscaling:groupName"),
 										Value: aws.String("asg-1"),
 									},
 								},
@@ -69,7 +69,7 @@ func TestSyncAWSNodes(t *testing.T) {
 								PrivateIpAddress: aws.String("172.32.16.1"),
 								Tags: []*ec2.Tag{
 									&ec2.Tag{
-										Key:   aws.String("Name"),
+										Key:   aws.String("aws:autoscaling:groupName"),
 										Value: aws.String("asg-1"),
 									},
 								},
@@ -231,6 +231,11 @@ func TestSyncAWSNodes(t *testing.T) {
 						},
 					},
 				},
+				AutoScalingGroups: []operatorv1alpha1.AutoScalingGroup{
+					{
+						Name: "asg-1",
+					},
+				},
 				LastASGModifiedTime: &metav1.Time{
 					Time: time.Time{},
 				},
@@ -255,7 +260,7 @@ func TestSyncAWSNodes(t *testing.T) {
 								PrivateIpAddress: aws.String("172.32.16.0"),
 								Tags: []*ec2.Tag{
 									&ec2.Tag{
-										Key:   aws.String("Name"),
+										Key:   aws.String("aws:autoscaling:groupName"),
 										Value: aws.String("asg-1"),
 									},
 								},
@@ -279,7 +284,7 @@ func TestSyncAWSNodes(t *testing.T) {
 								PrivateIpAddress: aws.String("172.32.16.1"),
 								Tags: []*ec2.Tag{
 									&ec2.Tag{
-										Key:   aws.String("Name"),
+										Key:   aws.String("aws:autoscaling:groupName"),
 										Value: aws.String("asg-1"),
 									},
 								},
@@ -425,6 +430,11 @@ func TestSyncAWSNodes(t *testing.T) {
 					},
 				},
 				NotJoinedAWSNodes: nil,
+				AutoScalingGroups: []operatorv1alpha1.AutoScalingGroup{
+					{
+						Name: "asg-1",
+					},
+				},
 				LastASGModifiedTime: &metav1.Time{
 					Time: time.Time{},
 				},
@@ -496,6 +506,11 @@ func TestSyncAWSNodes(t *testing.T) {
 						},
 					},
 					NotJoinedAWSNodes: nil,
+					AutoScalingGroups: []operatorv1alpha1.AutoScalingGroup{
+						{
+							Name: "asg-1",
+						},
+					},
 					LastASGModifiedTime: &metav1.Time{
 						Time: time.Time{},
 					},
@@ -543,6 +558,11 @@ func TestSyncAWSNodes(t *testing.T) {
 						},
 					},
 					NotJoinedAWSNodes: nil,
+					AutoScalingGroups: []operatorv1alpha1.AutoScalingGroup{
+						{
+							Name: "asg-1",
+						},
+					},
 					LastASGModifiedTime: &metav1.Time{
 						Time: time.Time{},
 					},
@@ -573,6 +593,11 @@ func TestSyncAWSNodes(t *testing.T) {
 					},
 				},
 				NotJoinedAWSNodes: nil,
+				AutoScalingGroups: []operatorv1alpha1.AutoScalingGroup{
+					{
+						Name: "asg-1",
+					},
+				},
 				LastASGModifiedTime: &metav1.Time{
 					Time: time.Time{},
 				},
@@ -0,0 +1,63 @@
+package awsnoderefresher
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+	"github.com/h3poteto/node-manager/pkg/cloud"
+	"github.com/h3poteto/node-manager/pkg/util/klog"
+)
+
+// syncAMISource reads the parameter of amiSource, and records the value in the status when it changes.
+// The first value is recorded as refreshed, so tracking an existing parameter does not start a refresh.
+func (r *AWSNodeRefresherReconciler) syncAMISource(ctx context.Context, refresher *operatorv1alpha1.AWSNodeRefresher) error {
+	reader, ok := r.cloud.(cloud.ParameterReader)
+	if !ok {
+		return fmt.Errorf("cloud provider %s does not support amiSource", refresher.Spec.CloudProvider)
+	}
+	parameter := refresher.Spec.AMISource.SSMParameter
+	value, err := reader.GetParameter(parameter)
+	if err != nil {
+		return err
+	}
+	status := refresher.Status.AMISource
+	switch {
+	case status == nil:
+		status = &operatorv1alpha1.AMISourceStatus{RefreshedValue: value}
+		r.Recorder.Eventf(refresher, corev1.EventTypeNormal, "Track AMI", "Track AMI %s in %s", value, parameter)
+	case status.LastSeenValue != value:
+		r.Recorder.Eventf(refresher, corev1.EventTypeNormal, "AMI changed", "AMI in %s is changed from %s to %s", parameter, status.LastSeenValue, value)
+	default:
+		return nil
+	}
+	status.LastSeenValue = value
+	refresher.Status.AMISource = status
+	refresher.Status.Revision += 1
+	if err := r.Client.Update(ctx, refresher); err != nil {
+		klog.Errorf(ctx, "failed to update refresher: %v", err)
+		return err
+	}
+	return nil
+}
+
+// amiRefreshPending returns whether a new AMI is published after the last refresh.
+func amiRefreshPending(refresher *operatorv1alpha1.AWSNodeRefresher) bool {
+	status := refresher.Status.AMISource
+	return status != nil && status.LastSeenValue != status.RefreshedValue
+}
+
+// amiSourceMessage describes when the next refresh starts with amiSource.
+func amiSourceMessage(refresher *operatorv1alpha1.AWSNodeRefresher) string {
+	window := ""
+	if refresher.Status.NextUpdateTime != nil {
+		window = fmt.Sprintf(" in the window at %s", refresher.Status.NextUpdateTime.Format(metav1.RFC3339Micro))
+	}
+	if amiRefreshPending(refresher) {
+		return fmt.Sprintf("Next refresh starts%s for AMI %s", window, refresher.Status.AMISource.LastSeenValue)
+	}
+	return fmt.Sprintf("Next refresh starts%s when %s changes", window, refresher.Spec.AMISource.SSMParameter)
+}
@@ -0,0 +1,140 @@
+package awsnoderefresher
+
+import (
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+	cloudaws "github.com/h3poteto/node-manager/pkg/cloud/aws"
+)
+
+func TestSyncAMISource(t *testing.T) {
+	ctx := context.Background()
+	refresher := &operatorv1alpha1.AWSNodeRefresher{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-refresher",
+		},
+		Spec: operatorv1alpha1.AWSNodeRefresherSpec{
+			Region: "us-east-1",
+			AMISource: &operatorv1alpha1.AMISource{
+				SSMParameter: "/aws/service/eks/optimized-ami/1.30/amazon-linux-2/recommended/image_id",
+			},
+		},
+		Status: operatorv1alpha1.AWSNodeRefresherStatus{
+			Phase: operatorv1alpha1.AWSNodeRefresherScheduled,
+		},
+	}
+	mockedSSM := &mockedSSMAPI{
+		Value: "ami-1",
+	}
+	r := &AWSNodeRefresherReconciler{
+		cloud: &cloudaws.AWS{
+			SSM: mockedSSM,
+		},
+		Client:   &mockedClient{},
+		Recorder: &mockedRecorder{},
+	}
+	now := metav1.Now()
+
+	if err := r.syncAMISource(ctx, refresher); err != nil {
+		t.Fatal(err)
+	}
+	if refresher.Status.AMISource == nil || refresher.Status.AMISource.LastSeenValue != "ami-1" {
+		t.Fatalf("the first AMI is not recorded: %v", refresher.Status.AMISource)
+	}
+	if refreshDue(refresher, &now) {
+		t.Errorf("the first AMI should not start a refresh")
+	}
+
+	mockedSSM.Value = "ami-2"
+	if err := r.syncAMISource(ctx, refresher); err != nil {
+		t.Fatal(err)
+	}
+	if refresher.Status.AMISource.LastSeenValue != "ami-2" || refresher.Status.AMISource.RefreshedValue != "ami-1" {
+		t.Errorf("the new AMI is not recorded: %v", refresher.Status.AMISource)
+	}
+	if !refreshDue(refresher, &now) {
+		t.Errorf("refresh should be due when the AMI is changed")
+	}
+
+	acknowledgeRefresh(refresher)
+	if refreshDue(refresher, &now) {
+		t.Errorf("refresh should not be due after the AMI is refreshed")
+	}
+}
+
+func TestRefreshDueWithAMISource(t *testing.T) {
+	cases := []struct {
+		title          string
+		status         *operatorv1alpha1.AMISourceStatus
+		nextUpdateTime *metav1.Time
+		expected       bool
+	}{
+		{
+			title:          "AMI is not polled yet",
+			status:         nil,
+			nextUpdateTime: nil,
+			expected:       false,
+		},
+		{
+			title: "AMI is not changed",
+			status: &operatorv1alpha1.AMISourceStatus{
+				LastSeenValue:  "ami-1",
+				RefreshedValue: "ami-1",
+			},
+			nextUpdateTime: &metav1.Time{Time: time.Now().Add(-1 * time.Minute)},
+			expected:       false,
+		},
+		{
+			title: "AMI is changed without a window",
+			status: &operatorv1alpha1.AMISourceStatus{
+				LastSeenValue:  "ami-2",
+				RefreshedValue: "ami-1",
+			},
+			nextUpdateTime: nil,
+			expected:       true,
+		},
+		{
+			title: "AMI is changed before the window",
+			status: &operatorv1alpha1.AMISourceStatus{
+				LastSeenValue:  "ami-2",
+				RefreshedValue: "ami-1",
+			},
+			nextUpdateTime: &metav1.Time{Time: time.Now().Add(1 * time.Hour)},
+			expected:       false,
+		},
+		{
+			title: "AMI is changed in the window",
+			status: &operatorv1alpha1.AMISourceStatus{
+				LastSeenValue:  "ami-2",
+				RefreshedValue: "ami-1",
+			},
+			nextUpdateTime: &metav1.Time{Time: time.Now().Add(-1 * time.Minute)},
+			expected:       true,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		refresher := &operatorv1alpha1.AWSNodeRefresher{
+			Spec: operatorv1alpha1.AWSNodeRefresherSpec{
+				AMISource: &operatorv1alpha1.AMISource{
+					SSMParameter: "ami",
+				},
+			},
+			Status: operatorv1alpha1.AWSNodeRefresherStatus{
+				NextUpdateTime: c.nextUpdateTime,
+				AMISource:      c.status,
+			},
+		}
+		now := metav1.Now()
+		result := refreshDue(refresher, &now)
+		if result != c.expected {
+			t.Errorf("CASE: %s : expected %v, but returned %v", c.title, c.expected, result)
+		}
+	}
+}
@@ -16,6 +16,10 @@ func (r *AWSNodeRefresherReconciler) refreshComplete(ctx context.Context, refres
 	refresher.Status.Phase = operatorv1alpha1.AWSNodeRefresherCompleted
 	refresher.Status.UpdateStartTime = nil
 	refresher.Status.ReplaceTargetNode = nil
+	refresher.Status.ReplaceTargetNodes = nil
+	refresher.Status.BlockingPodDisruptionBudgets = nil
+	refresher.Status.OnDemand = false
+	refresher.Status.PhaseStartTime = nil
 	refresher.Status.Revision += 1
 	if err := r.Client.Update(ctx, refresher); err != nil {
 		klog.Errorf(ctx, "failed to update refresher: %v", err)
@@ -0,0 +1,103 @@
+package awsnoderefresher
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+	"github.com/h3poteto/node-manager/pkg/util/conditions"
+	"github.com/h3poteto/node-manager/pkg/util/klog"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// updateConditions reflects the current phase and the result of sync to status conditions.
+func (r *AWSNodeRefresherReconciler) updateConditions(ctx context.Context, refresher *operatorv1alpha1.AWSNodeRefresher, syncErr error) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := operatorv1alpha1.AWSNodeRefresher{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: refresher.Namespace, Name: refresher.Name}, &current); err != nil {
+			klog.Errorf(ctx, "failed to get AWSNodeRefresher %s/%s: %v", refresher.Namespace, refresher.Name, err)
+			return err
+		}
+		before := current.Status.DeepCopy()
+		setConditions(&current, syncErr)
+		if reflect.DeepEqual(before.Conditions, current.Status.Conditions) {
+			return nil
+		}
+		if err := r.Client.Update(ctx, &current); err != nil {
+			klog.Errorf(ctx, "failed to update AWSNodeRefresher conditions %s/%s: %v", current.Namespace, current.Name, err)
+			return err
+		}
+		return nil
+	})
+}
+
+func setConditions(refresher *operatorv1alpha1.AWSNodeRefresher, syncErr error) {
+	generation := refresher.Generation
+	c := &refresher.Status.Conditions
+	switch refresher.Status.Phase {
+	case "", operatorv1alpha1.AWSNodeRefresherInit:
+		conditions.Set(c, generation, operatorv1alpha1.ConditionReady, metav1.ConditionFalse, operatorv1alpha1.ReasonInitializing, "Refresh is not scheduled yet")
+		conditions.Set(c, generation, operatorv1alpha1.ConditionRefreshing, metav1.ConditionFalse, operatorv1alpha1.ReasonIdle, "")
+	case operatorv1alpha1.AWSNodeRefresherScheduled, operatorv1alpha1.AWSNodeRefresherCompleted:
+		message := ""
+		if refresher.Spec.AMISource != nil {
+			message = amiSourceMessage(refresher)
+		} else if refresher.Spec.MaxNodeAge != nil {
+			now := metav1.Now()
+			message = maxNodeAgeMessage(refresher, &now)
+		} else if refresher.Status.NextUpdateTime != nil {
+			message = fmt.Sprintf("Next refresh is scheduled at %s", refresher.Status.NextUpdateTime.Format(metav1.RFC3339Micro))
+		} else if refresher.Spec.Trigger == operatorv1alpha1.RefreshTriggerDrift {
+			message = fmt.Sprintf("Next refresh starts when nodes drift, %d nodes are drifted now", len(refresher.Status.DriftedNodes))
+		}
+		if refresher.Spec.Suspend {
+			message = "Refresher is suspended"
+		}
+		if refresher.Status.FailureReason != "" {
+			message = strings.TrimSpace(fmt.Sprintf("Last refresh failed: %s. %s", refresher.Status.FailureReason, message))
+		}
+		conditions.Set(c, generation, operatorv1alpha1.ConditionReady, metav1.ConditionTrue, conditions.Reason(string(refresher.Status.Phase)), message)
+		conditions.Set(c, generation, operatorv1alpha1.ConditionRefreshing, metav1.ConditionFalse, operatorv1alpha1.ReasonIdle, "")
+	case operatorv1alpha1.AWSNodeRefresherPaused:
+		message := "Refresh is paused until the maintenance window opens"
+		if refresher.Spec.Suspend {
+			message = "Refresh is paused because the refresher is suspended"
+		} else if refresher.Status.NextUpdateTime != nil {
+			message = fmt.Sprintf("Refresh is paused until the maintenance window opens at %s", refresher.Status.NextUpdateTime.Format(metav1.RFC3339Micro))
+		}
+		conditions.Set(c, generation, operatorv1alpha1.ConditionReady, metav1.ConditionFalse, operatorv1alpha1.ReasonRefreshing, message)
+		conditions.Set(c, generation, operatorv1alpha1.ConditionRefreshing, metav1.ConditionTrue, conditions.Reason(string(refresher.Status.Phase)), message)
+	case operatorv1alpha1.AWSNodeRefresherFailed:
+		conditions.Set(c, generation, operatorv1alpha1.ConditionReady, metav1.ConditionFalse, conditions.Reason(string(refresher.Status.Phase)), refresher.Status.FailureReason)
+		conditions.Set(c, generation, operatorv1alpha1.ConditionRefreshing, metav1.ConditionFalse, operatorv1alpha1.ReasonIdle, "")
+	case operatorv1alpha1.AWSNodeRefresherInstanceRefreshing:
+		message := instanceRefreshMessage(refresher.Status.InstanceRefreshes)
+		conditions.Set(c, generation, operatorv1alpha1.ConditionReady, metav1.ConditionFalse, operatorv1alpha1.ReasonRefreshing, message)
+		conditions.Set(c, generation, operatorv1alpha1.ConditionRefreshing, metav1.ConditionTrue, conditions.Reason(string(refresher.Status.Phase)), message)
+	default:
+		message := fmt.Sprintf("Refresh is in %s phase", refresher.Status.Phase)
+		if targets := replaceTargets(refresher); len(targets) > 0 {
+			message = fmt.Sprintf("Refresh is in %s phase for node %s", refresher.Status.Phase, strings.Join(nodeNames(targets), ", "))
+		}
+		conditions.Set(c, generation, operatorv1alpha1.ConditionReady, metav1.ConditionFalse, operatorv1alpha1.ReasonRefreshing, message)
+		conditions.Set(c, generation, operatorv1alpha1.ConditionRefreshing, metav1.ConditionTrue, conditions.Reason(string(refresher.Status.Phase)), message)
+	}
+	conditions.SetSyncResult(c, generation, syncErr)
+}
+
+// instanceRefreshMessage summarizes Instance Refreshes, for example "Instance refresh of asg-a is InProgress (50%)".
+func instanceRefreshMessage(refreshes []operatorv1alpha1.InstanceRefreshStatus) string {
+	var states []string
+	for _, r := range refreshes {
+		state := fmt.Sprintf("%s is %s (%d%%)", r.AutoScalingGroupName, r.Status, r.PercentageComplete)
+		if r.Status == "" {
+			state = fmt.Sprintf("%s is started", r.AutoScalingGroupName)
+		}
+		states = append(states, state)
+	}
+	return "Instance refresh of " + strings.Join(states, ", ")
+}
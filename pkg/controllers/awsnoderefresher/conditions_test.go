@@ -0,0 +1,92 @@
+package awsnoderefresher
+
+import (
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetConditions(t *testing.T) {
+	cases := []struct {
+		title              string
+		phase              operatorv1alpha1.AWSNodeRefresherPhase
+		err                error
+		expectedReady      metav1.ConditionStatus
+		expectedRefreshing metav1.ConditionStatus
+		expectedDegraded   metav1.ConditionStatus
+	}{
+		{
+			title:              "Init",
+			phase:              operatorv1alpha1.AWSNodeRefresherInit,
+			err:                nil,
+			expectedReady:      metav1.ConditionFalse,
+			expectedRefreshing: metav1.ConditionFalse,
+			expectedDegraded:   metav1.ConditionFalse,
+		},
+		{
+			title:              "Scheduled",
+			phase:              operatorv1alpha1.AWSNodeRefresherScheduled,
+			err:                nil,
+			expectedReady:      metav1.ConditionTrue,
+			expectedRefreshing: metav1.ConditionFalse,
+			expectedDegraded:   metav1.ConditionFalse,
+		},
+		{
+			title:              "Draining",
+			phase:              operatorv1alpha1.AWSNodeRefresherDraining,
+			err:                nil,
+			expectedReady:      metav1.ConditionFalse,
+			expectedRefreshing: metav1.ConditionTrue,
+			expectedDegraded:   metav1.ConditionFalse,
+		},
+		{
+			title:              "Failed to sync",
+			phase:              operatorv1alpha1.AWSNodeRefresherScheduled,
+			err:                errors.New("failed to update"),
+			expectedReady:      metav1.ConditionFalse,
+			expectedRefreshing: metav1.ConditionFalse,
+			expectedDegraded:   metav1.ConditionTrue,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+
+		refresher := &operatorv1alpha1.AWSNodeRefresher{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "test-refresher",
+				Generation: 2,
+			},
+			Status: operatorv1alpha1.AWSNodeRefresherStatus{
+				Phase: c.phase,
+				NextUpdateTime: &metav1.Time{
+					Time: time.Now().Add(24 * time.Hour),
+				},
+				ReplaceTargetNode: &operatorv1alpha1.AWSNode{
+					Name: "node-1",
+				},
+			},
+		}
+		setConditions(refresher, c.err)
+
+		if !meta.IsStatusConditionPresentAndEqual(refresher.Status.Conditions, operatorv1alpha1.ConditionReady, c.expectedReady) {
+			t.Errorf("CASE: %s : Ready condition is not matched, expected %s: %v", c.title, c.expectedReady, refresher.Status.Conditions)
+		}
+		if !meta.IsStatusConditionPresentAndEqual(refresher.Status.Conditions, operatorv1alpha1.ConditionRefreshing, c.expectedRefreshing) {
+			t.Errorf("CASE: %s : Refreshing condition is not matched, expected %s: %v", c.title, c.expectedRefreshing, refresher.Status.Conditions)
+		}
+		if !meta.IsStatusConditionPresentAndEqual(refresher.Status.Conditions, operatorv1alpha1.ConditionDegraded, c.expectedDegraded) {
+			t.Errorf("CASE: %s : Degraded condition is not matched, expected %s: %v", c.title, c.expectedDegraded, refresher.Status.Conditions)
+		}
+		for _, condition := range refresher.Status.Conditions {
+			if condition.ObservedGeneration != 2 {
+				t.Errorf("CASE: %s : ObservedGeneration of %s is not matched: %d", c.title, condition.Type, condition.ObservedGeneration)
+			}
+		}
+	}
+}
@@ -20,9 +20,9 @@ import (
 	"context"
 	"time"
 
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -31,7 +31,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
-	cloudaws "github.com/h3poteto/node-manager/pkg/cloud/aws"
+	"github.com/h3poteto/node-manager/pkg/cloud"
 	pkgctx "github.com/h3poteto/node-manager/pkg/util/context"
 	"github.com/h3poteto/node-manager/pkg/util/externalevent"
 	"github.com/h3poteto/node-manager/pkg/util/klog"
@@ -41,17 +41,20 @@ import (
 // AWSNodeRefresherReconciler reconciles a AWSNodeRefresher object
 type AWSNodeRefresherReconciler struct {
 	client.Client
-	Log      logr.Logger
-	Recorder record.EventRecorder
-	Scheme   *runtime.Scheme
-	cloud    *cloudaws.AWS
+	Log       logr.Logger
+	Recorder  record.EventRecorder
+	Scheme    *runtime.Scheme
+	Providers *cloud.Registry
+	cloud     cloud.Provider
 }
 
 // +kubebuilder:rbac:groups=operator.h3poteto.dev,resources=awsnoderefreshers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=operator.h3poteto.dev,resources=awsnoderefreshers/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch;update;patch
-// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=core,resources=pods/eviction,verbs=create
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch
 
 func (r *AWSNodeRefresherReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	_ = r.Log.WithValues("awsnoderefresher", req.NamespacedName)
@@ -69,19 +72,29 @@ func (r *AWSNodeRefresherReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	// Generate aws client
-	sess := session.Must(session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-	}))
-	r.cloud = cloudaws.New(sess, refresher.Spec.Region)
-
-	if err := r.syncRefresher(ctx, &refresher); err != nil {
-		klog.Errorf(ctx, "failed to sync AWSNodeRefresher: %v", err)
-		r.Recorder.Eventf(&refresher, corev1.EventTypeWarning, "Error", "Failed to sync: %v", err)
+	providerName, config := cloud.NewConfig(refresher.Spec.CloudTarget, refresher.Spec.Region)
+	provider, err := r.Providers.Provider(providerName, config)
+	if err != nil {
+		klog.Errorf(ctx, "failed to build cloud provider: %v", err)
 		return ctrl.Result{}, err
 	}
+	// Other reconciles may use another provider at the same time, so do not set it to the shared reconciler.
+	rc := *r
+	rc.cloud = provider
 
-	return ctrl.Result{}, nil
+	syncErr := rc.syncRefresher(ctx, &refresher)
+	if syncErr != nil {
+		klog.Errorf(ctx, "failed to sync AWSNodeRefresher: %v", syncErr)
+		r.Recorder.Eventf(&refresher, corev1.EventTypeWarning, "Error", "Failed to sync: %v", syncErr)
+	}
+	if err := r.updateConditions(ctx, &refresher, syncErr); err != nil {
+		klog.Errorf(ctx, "failed to update conditions of AWSNodeRefresher: %v", err)
+		if syncErr == nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, syncErr
 }
 
 func (r *AWSNodeRefresherReconciler) SetupWithManager(mgr ctrl.Manager) error {
@@ -115,10 +128,35 @@ func (r *AWSNodeRefresherReconciler) SetupWithManager(mgr ctrl.Manager) error {
 }
 
 func (r *AWSNodeRefresherReconciler) syncRefresher(ctx context.Context, refresher *operatorv1alpha1.AWSNodeRefresher) error {
+	if err := r.migrateReplaceTarget(ctx, refresher); err != nil {
+		return err
+	}
+	if reason := phaseTimeout(refresher, &metav1.Time{Time: time.Now()}); reason != "" {
+		return r.refreshFail(ctx, refresher, reason)
+	}
 	switch refresher.Status.Phase {
 	case operatorv1alpha1.AWSNodeRefresherInit:
 		return r.scheduleNext(ctx, refresher)
 	case operatorv1alpha1.AWSNodeRefresherScheduled:
+		if refresher.Spec.Trigger == operatorv1alpha1.RefreshTriggerDrift {
+			if err := r.syncDriftedNodes(ctx, refresher); err != nil {
+				return err
+			}
+		}
+		if refresher.Spec.AMISource != nil {
+			if err := r.syncAMISource(ctx, refresher); err != nil {
+				return err
+			}
+		}
+		if windowPassed(refresher, &metav1.Time{Time: time.Now()}) {
+			return r.scheduleNext(ctx, refresher)
+		}
+		if refresher.Spec.Suspend {
+			return nil
+		}
+		if refresher.Spec.Strategy == operatorv1alpha1.RefreshStrategyInstanceRefresh {
+			return r.startInstanceRefresh(ctx, refresher)
+		}
 		return r.refreshIncrease(ctx, refresher)
 	case operatorv1alpha1.AWSNodeRefresherUpdateIncreasing:
 		waiting, retried, err := r.retryIncrease(ctx, refresher)
@@ -162,14 +200,26 @@ func (r *AWSNodeRefresherReconciler) syncRefresher(ctx context.Context, refreshe
 			return nil
 		}
 		if !enough {
-			return nil
+			return r.restoreCapacity(ctx, refresher)
 		}
 		klog.Info(ctx, "finish waiting")
+		if refresher.Spec.Trigger == operatorv1alpha1.RefreshTriggerDrift {
+			if err := r.syncDriftedNodes(ctx, refresher); err != nil {
+				return err
+			}
+		}
 		if r.allReplaced(ctx, refresher) {
 			return r.refreshDecrease(ctx, refresher)
+		} else if refresher.Spec.Suspend || !windowOpen(refresher, &metav1.Time{Time: time.Now()}) {
+			return r.pauseRefresh(ctx, refresher)
 		} else {
 			return r.refreshNextReplace(ctx, refresher)
 		}
+	case operatorv1alpha1.AWSNodeRefresherPaused:
+		if refresher.Spec.Suspend || !windowOpen(refresher, &metav1.Time{Time: time.Now()}) {
+			return nil
+		}
+		return r.refreshNextReplace(ctx, refresher)
 	case operatorv1alpha1.AWSNodeRefresherUpdateDecreasing:
 		waiting, retried, err := r.retryDecrease(ctx, refresher)
 		if err != nil {
@@ -182,7 +232,25 @@ func (r *AWSNodeRefresherReconciler) syncRefresher(ctx context.Context, refreshe
 			return nil
 		}
 		return r.refreshComplete(ctx, refresher)
+	case operatorv1alpha1.AWSNodeRefresherInstanceRefreshing:
+		return r.syncInstanceRefresh(ctx, refresher)
 	case operatorv1alpha1.AWSNodeRefresherCompleted:
+		if _, requested := refreshRequested(refresher); requested && !refresher.Spec.Suspend {
+			return r.startRequestedRefresh(ctx, refresher)
+		}
+		return r.scheduleNext(ctx, refresher)
+	case operatorv1alpha1.AWSNodeRefresherFailed:
+		if err := r.rollbackSurplus(ctx, refresher); err != nil {
+			return err
+		}
+		// The requested refresh waits for the rollback, otherwise surplus instances are added on top of the remaining ones.
+		if !refresher.Status.SurplusRolledBack {
+			return nil
+		}
+		if _, requested := refreshRequested(refresher); requested && !refresher.Spec.Suspend {
+			return r.startRequestedRefresh(ctx, refresher)
+		}
+		// The next schedule or trigger retries the refresh, so one failure does not stop later refreshes.
 		return r.scheduleNext(ctx, refresher)
 	default:
 		klog.Warningf(ctx, "Unknown phase %s for AWSNodeRefrehser", refresher.Status.Phase)
@@ -16,6 +16,7 @@ func (r *AWSNodeRefresherReconciler) refreshDecrease(ctx context.Context, refres
 	should, skip := r.shouldDecrease(ctx, refresher)
 	if skip {
 		refresher.Status.Phase = operatorv1alpha1.AWSNodeRefresherUpdateDecreasing
+		refresher.Status.PhaseStartTime = &now
 		refresher.Status.Revision += 1
 		if err := r.Client.Update(ctx, refresher); err != nil {
 			klog.Errorf(ctx, "failed to update refresher: %v", err)
@@ -29,6 +30,7 @@ func (r *AWSNodeRefresherReconciler) refreshDecrease(ctx context.Context, refres
 
 	refresher.Status.Phase = operatorv1alpha1.AWSNodeRefresherUpdateDecreasing
 	refresher.Status.LastASGModifiedTime = &now
+	refresher.Status.PhaseStartTime = &now
 	refresher.Status.Revision += 1
 	if err := r.Client.Update(ctx, refresher); err != nil {
 		klog.Errorf(ctx, "failed to update refresher: %v", err)
@@ -36,7 +38,7 @@ func (r *AWSNodeRefresherReconciler) refreshDecrease(ctx context.Context, refres
 	}
 	r.Recorder.Event(refresher, corev1.EventTypeNormal, "Decrease instance", "Decrease instance in ASG for refresh")
 
-	return r.cloud.DeleteInstancesToAutoScalingGroups(refresher.Spec.AutoScalingGroups, int(refresher.Spec.Desired), len(refresher.Status.AWSNodes))
+	return r.cloud.ScaleDownNodeGroups(refresher.Spec.AutoScalingGroups, int(refresher.Spec.Desired), len(refresher.Status.AWSNodes))
 }
 
 func (r *AWSNodeRefresherReconciler) shouldDecrease(ctx context.Context, refresher *operatorv1alpha1.AWSNodeRefresher) (bool, bool) {
@@ -44,10 +46,10 @@ func (r *AWSNodeRefresherReconciler) shouldDecrease(ctx context.Context, refresh
 		klog.Warningf(ctx, "AWSNodeRefresher phase is not matched: %s, so should not decrease", refresher.Status.Phase)
 		return false, false
 	}
-	if refresher.Spec.SurplusNodes == 0 {
+	if surplusNodes(refresher) == 0 {
 		return false, true
 	}
-	if len(refresher.Status.AWSNodes) >= (int(refresher.Spec.Desired) + int(refresher.Spec.SurplusNodes)) {
+	if len(refresher.Status.AWSNodes) >= (int(refresher.Spec.Desired) + surplusNodes(refresher)) {
 		return true, false
 	}
 	return false, false
@@ -71,7 +73,7 @@ func (r *AWSNodeRefresherReconciler) retryDecrease(ctx context.Context, refreshe
 	}
 	r.Recorder.Eventf(refresher, corev1.EventTypeNormal, "Retry decrease", "Retry to decrease instances for AWSNodeRefresher %s/%s", refresher.Namespace, refresher.Name)
 
-	err := r.cloud.DeleteInstancesToAutoScalingGroups(
+	err := r.cloud.ScaleDownNodeGroups(
 		refresher.Spec.AutoScalingGroups,
 		int(refresher.Spec.Desired),
 		len(refresher.Status.AWSNodes),
@@ -2,12 +2,18 @@ package awsnoderefresher
 
 import (
 	"context"
+	"reflect"
+	"sort"
+	"strings"
 	"time"
 
 	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
 	"github.com/h3poteto/node-manager/pkg/util/klog"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -16,23 +22,46 @@ func (r *AWSNodeRefresherReconciler) refreshDrain(ctx context.Context, refresher
 		return nil
 	}
 
-	target, err := findDeleteTarget(refresher.Status.AWSNodes)
+	now := metav1.Now()
+	candidates := nodesOlderThan(replaceCandidates(refresher, &now), refresher.Status.UpdateStartTime)
+	if len(candidates) == 0 && (refresher.Spec.Trigger == operatorv1alpha1.RefreshTriggerDrift || refresher.Spec.MaxNodeAge != nil) {
+		// Drifted or expired nodes may be removed by others after the refresh starts, then nothing is left to replace.
+		refresher.Status.Phase = operatorv1alpha1.AWSNodeRefresherUpdateAWSWaiting
+		refresher.Status.LastASGModifiedTime = &now
+		refresher.Status.PhaseStartTime = &now
+		refresher.Status.Revision += 1
+		if err := r.Client.Update(ctx, refresher); err != nil {
+			klog.Errorf(ctx, "failed to update refresher: %v", err)
+			return err
+		}
+		r.Recorder.Event(refresher, corev1.EventTypeNormal, "Skip drain", "No nodes are left to replace")
+		return nil
+	}
+	targets, err := r.selectDeleteTargets(ctx, refresher, candidates)
 	if err != nil {
 		return err
 	}
 
-	now := metav1.Now()
 	refresher.Status.Phase = operatorv1alpha1.AWSNodeRefresherDraining
 	refresher.Status.LastASGModifiedTime = &now
-	refresher.Status.ReplaceTargetNode = target
+	refresher.Status.ReplaceTargetNode = nil
+	refresher.Status.ReplaceTargetNodes = targets
 	refresher.Status.Revision += 1
 	if err := r.Client.Update(ctx, refresher); err != nil {
 		klog.Errorf(ctx, "failed to update refresher: %v", err)
 		return err
 	}
-	r.Recorder.Eventf(refresher, corev1.EventTypeNormal, "Drain node", "Drain node %s", target.Name)
+	r.Recorder.Eventf(refresher, corev1.EventTypeNormal, "Drain node", "Drain node %s", strings.Join(nodeNames(targets), ", "))
 
-	return r.drain(ctx, target.Name)
+	var pdbs []string
+	for i := range targets {
+		blocking, err := r.drain(ctx, targets[i].Name)
+		if err != nil {
+			return err
+		}
+		pdbs = mergePodDisruptionBudgets(pdbs, blocking)
+	}
+	return r.updateBlockingPodDisruptionBudgets(ctx, refresher, pdbs)
 }
 
 func shouldDrain(ctx context.Context, refresher *operatorv1alpha1.AWSNodeRefresher) bool {
@@ -40,8 +69,8 @@ func shouldDrain(ctx context.Context, refresher *operatorv1alpha1.AWSNodeRefresh
 		klog.Warningf(ctx, "AWSNodeRefresher phase is not matched: %s, so should not drain", refresher.Status.Phase)
 		return false
 	}
-	if len(refresher.Status.AWSNodes) != int(refresher.Spec.Desired)+int(refresher.Spec.SurplusNodes) {
-		klog.Infof(ctx, "Node is not enough, current: %d, desired: %d + %d", len(refresher.Status.AWSNodes), refresher.Spec.Desired, refresher.Spec.SurplusNodes)
+	if len(refresher.Status.AWSNodes) != int(refresher.Spec.Desired)+surplusNodes(refresher) {
+		klog.Infof(ctx, "Node is not enough, current: %d, desired: %d + %d", len(refresher.Status.AWSNodes), refresher.Spec.Desired, surplusNodes(refresher))
 		return false
 	}
 	return true
@@ -52,40 +81,53 @@ func (r *AWSNodeRefresherReconciler) retryDrain(ctx context.Context, refresher *
 		return true, false, nil
 	}
 
-	target := refresher.Status.ReplaceTargetNode
-
-	if !r.shouldRetryDrain(ctx, refresher, target.Name) {
+	var targets []string
+	for _, target := range replaceTargets(refresher) {
+		if r.shouldRetryDrain(ctx, refresher, target.Name) {
+			targets = append(targets, target.Name)
+		}
+	}
+	if len(targets) == 0 {
 		return false, false, nil
 	}
 
 	r.Recorder.Event(refresher, corev1.EventTypeNormal, "Retry drain", "Drain to replace instance in ASG for refresh")
 
-	err := r.drain(ctx, target.Name)
-	return false, true, err
+	var pdbs []string
+	for _, name := range targets {
+		blocking, err := r.drain(ctx, name)
+		if err != nil {
+			return false, true, err
+		}
+		pdbs = mergePodDisruptionBudgets(pdbs, blocking)
+	}
+	return false, true, r.updateBlockingPodDisruptionBudgets(ctx, refresher, pdbs)
 }
 
-func (r *AWSNodeRefresherReconciler) drain(ctx context.Context, nodeName string) error {
+// drain cordons the node and evicts pods on it through the Eviction API.
+// It returns PodDisruptionBudgets which block the eviction, so the caller can retry it later.
+func (r *AWSNodeRefresherReconciler) drain(ctx context.Context, nodeName string) ([]string, error) {
 	// Node
 	var node corev1.Node
 	if err := r.Client.Get(ctx, client.ObjectKey{
 		Name: nodeName,
 	}, &node); err != nil {
 		klog.Errorf(ctx, "Failed to get node: %v", err)
-		return err
+		return nil, err
 	}
 	node.Spec.Unschedulable = true
 	if err := r.Client.Update(ctx, &node); err != nil {
 		klog.Errorf(ctx, "Failed to update node: %v", err)
-		return err
+		return nil, err
 	}
 
 	// Pods
 	var podList corev1.PodList
 	if err := r.Client.List(ctx, &podList); err != nil {
 		klog.Errorf(ctx, "Failed to list pods: %v", err)
-		return err
+		return nil, err
 	}
-
+	var blocked []*corev1.Pod
 	for i := range podList.Items {
 		pod := podList.Items[i]
 		if pod.Spec.NodeName != nodeName {
@@ -95,12 +137,100 @@ func (r *AWSNodeRefresherReconciler) drain(ctx context.Context, nodeName string)
 		if podIsDaemonSet(pod) || podIsStaticPod(pod) {
 			continue
 		}
-		if err := r.Client.Delete(ctx, &pod); err != nil {
-			klog.Errorf(ctx, "Failed to delete pod: %v", err)
-			return err
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		err := r.evict(ctx, &pod)
+		if apierrors.IsTooManyRequests(err) {
+			klog.Infof(ctx, "Eviction of pod %s/%s is not allowed now, so retry it later", pod.Namespace, pod.Name)
+			blocked = append(blocked, &pod)
+			continue
+		}
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			klog.Errorf(ctx, "Failed to evict pod: %v", err)
+			return nil, err
+		}
+	}
+	if len(blocked) == 0 {
+		return nil, nil
+	}
+	return r.blockingPodDisruptionBudgets(ctx, blocked)
+}
+
+func (r *AWSNodeRefresherReconciler) evict(ctx context.Context, pod *corev1.Pod) error {
+	eviction := &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+		},
+	}
+	return r.Client.SubResource("eviction").Create(ctx, pod, eviction)
+}
+
+func (r *AWSNodeRefresherReconciler) blockingPodDisruptionBudgets(ctx context.Context, pods []*corev1.Pod) ([]string, error) {
+	var pdbList policyv1.PodDisruptionBudgetList
+	if err := r.Client.List(ctx, &pdbList); err != nil {
+		klog.Errorf(ctx, "Failed to list PodDisruptionBudgets: %v", err)
+		return nil, err
+	}
+	return matchPodDisruptionBudgets(pods, pdbList.Items), nil
+}
+
+// matchPodDisruptionBudgets returns namespaced names of PodDisruptionBudgets which select any of the pods.
+func matchPodDisruptionBudgets(pods []*corev1.Pod, pdbs []policyv1.PodDisruptionBudget) []string {
+	var names []string
+	for i := range pdbs {
+		pdb := &pdbs[i]
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			continue
+		}
+		for _, pod := range pods {
+			if pod.Namespace != pdb.Namespace {
+				continue
+			}
+			if selector.Matches(labels.Set(pod.Labels)) {
+				names = append(names, pdb.Namespace+"/"+pdb.Name)
+				break
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// mergePodDisruptionBudgets adds names which are not in the list yet, and keeps the list sorted.
+func mergePodDisruptionBudgets(names []string, added []string) []string {
+	for _, name := range added {
+		i := sort.SearchStrings(names, name)
+		if i < len(names) && names[i] == name {
+			continue
 		}
+		names = append(names, "")
+		copy(names[i+1:], names[i:])
+		names[i] = name
 	}
+	return names
+}
 
+// updateBlockingPodDisruptionBudgets records PodDisruptionBudgets which block the eviction.
+// The event is recorded only when they change, because drain is retried on every reconcile.
+func (r *AWSNodeRefresherReconciler) updateBlockingPodDisruptionBudgets(ctx context.Context, refresher *operatorv1alpha1.AWSNodeRefresher, pdbs []string) error {
+	if reflect.DeepEqual(refresher.Status.BlockingPodDisruptionBudgets, pdbs) {
+		return nil
+	}
+	if len(pdbs) > 0 {
+		r.Recorder.Eventf(refresher, corev1.EventTypeWarning, "EvictionBlocked", "Eviction is blocked by PodDisruptionBudgets: %s", strings.Join(pdbs, ", "))
+	}
+	refresher.Status.BlockingPodDisruptionBudgets = pdbs
+	refresher.Status.Revision += 1
+	if err := r.Client.Update(ctx, refresher); err != nil {
+		klog.Errorf(ctx, "failed to update refresher: %v", err)
+		return err
+	}
 	return nil
 }
 
@@ -3,13 +3,17 @@ package awsnoderefresher
 import (
 	"context"
 	"log"
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-sdk-go/service/ec2"
 	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -403,3 +407,274 @@ func TestShouldRetryDrain(t *testing.T) {
 		}
 	}
 }
+
+func TestDrain(t *testing.T) {
+	cases := []struct {
+		title         string
+		pods          []corev1.Pod
+		pdbs          []policyv1.PodDisruptionBudget
+		blockedPods   []string
+		expectedEvict []string
+		expectedPDBs  []string
+	}{
+		{
+			title: "All pods are evicted",
+			pods: []corev1.Pod{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pod1",
+						Namespace: "default",
+					},
+					Spec: corev1.PodSpec{
+						NodeName: "node-1",
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pod2",
+						Namespace: "default",
+					},
+					Spec: corev1.PodSpec{
+						NodeName: "node-2",
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pod3",
+						Namespace: "kube-system",
+						OwnerReferences: []metav1.OwnerReference{
+							{
+								APIVersion: "apps/v1",
+								Kind:       "DaemonSet",
+							},
+						},
+					},
+					Spec: corev1.PodSpec{
+						NodeName: "node-1",
+					},
+				},
+			},
+			pdbs:          nil,
+			blockedPods:   nil,
+			expectedEvict: []string{"pod1"},
+			expectedPDBs:  nil,
+		},
+		{
+			title: "Eviction is blocked by PodDisruptionBudget",
+			pods: []corev1.Pod{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pod1",
+						Namespace: "default",
+						Labels: map[string]string{
+							"app": "etcd",
+						},
+					},
+					Spec: corev1.PodSpec{
+						NodeName: "node-1",
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pod2",
+						Namespace: "default",
+						Labels: map[string]string{
+							"app": "web",
+						},
+					},
+					Spec: corev1.PodSpec{
+						NodeName: "node-1",
+					},
+				},
+			},
+			pdbs: []policyv1.PodDisruptionBudget{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "etcd",
+						Namespace: "default",
+					},
+					Spec: policyv1.PodDisruptionBudgetSpec{
+						Selector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{
+								"app": "etcd",
+							},
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "etcd",
+						Namespace: "kube-system",
+					},
+					Spec: policyv1.PodDisruptionBudgetSpec{
+						Selector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{
+								"app": "etcd",
+							},
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "web",
+						Namespace: "default",
+					},
+					Spec: policyv1.PodDisruptionBudgetSpec{
+						Selector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{
+								"app": "web",
+							},
+						},
+					},
+				},
+			},
+			blockedPods:   []string{"pod1"},
+			expectedEvict: []string{"pod1", "pod2"},
+			expectedPDBs:  []string{"default/etcd"},
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+
+		var evicted []string
+		cli := &mockedClient{
+			getFunc: func(obj client.Object) error {
+				return nil
+			},
+			listFunc: func(listObj client.ObjectList) error {
+				switch l := listObj.(type) {
+				case *corev1.PodList:
+					l.Items = c.pods
+				case *policyv1.PodDisruptionBudgetList:
+					l.Items = c.pdbs
+				}
+				return nil
+			},
+			evictFunc: func(obj client.Object) error {
+				evicted = append(evicted, obj.GetName())
+				for _, name := range c.blockedPods {
+					if obj.GetName() == name {
+						return apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 10)
+					}
+				}
+				return nil
+			},
+		}
+		r := &AWSNodeRefresherReconciler{
+			Client:   cli,
+			Recorder: &mockedRecorder{},
+		}
+
+		pdbs, err := r.drain(context.Background(), "node-1")
+		if err != nil {
+			t.Errorf("CASE: %s : Failed to drain: %v", c.title, err)
+		}
+		if !reflect.DeepEqual(evicted, c.expectedEvict) {
+			t.Errorf("CASE: %s : evicted pods are not matched, expected %v, but returned %v", c.title, c.expectedEvict, evicted)
+		}
+		if !reflect.DeepEqual(pdbs, c.expectedPDBs) {
+			t.Errorf("CASE: %s : blocking PodDisruptionBudgets are not matched, expected %v, but returned %v", c.title, c.expectedPDBs, pdbs)
+		}
+	}
+}
+
+func TestDrainEvictionError(t *testing.T) {
+	cli := &mockedClient{
+		getFunc: func(obj client.Object) error {
+			return nil
+		},
+		listFunc: func(listObj client.ObjectList) error {
+			listObj.(*corev1.PodList).Items = []corev1.Pod{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pod1",
+						Namespace: "default",
+					},
+					Spec: corev1.PodSpec{
+						NodeName: "node-1",
+					},
+				},
+			}
+			return nil
+		},
+		evictFunc: func(obj client.Object) error {
+			return apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, obj.GetName(), nil)
+		},
+	}
+	r := &AWSNodeRefresherReconciler{
+		Client:   cli,
+		Recorder: &mockedRecorder{},
+	}
+
+	_, err := r.drain(context.Background(), "node-1")
+	if err == nil {
+		t.Error("Error should be returned when eviction is failed")
+	}
+}
+
+func TestMergePodDisruptionBudgets(t *testing.T) {
+	merged := mergePodDisruptionBudgets([]string{"default/a", "default/c"}, []string{"default/b", "default/c"})
+	expected := []string{"default/a", "default/b", "default/c"}
+	if !reflect.DeepEqual(merged, expected) {
+		t.Errorf("PodDisruptionBudgets are not matched, expected %v, but returned %v", expected, merged)
+	}
+}
+
+func TestUpdateBlockingPodDisruptionBudgets(t *testing.T) {
+	cases := []struct {
+		title    string
+		current  []string
+		pdbs     []string
+		expected []string
+	}{
+		{
+			title:    "New PodDisruptionBudget blocks eviction",
+			current:  nil,
+			pdbs:     []string{"default/a"},
+			expected: []string{"EvictionBlocked"},
+		},
+		{
+			title:    "Same PodDisruptionBudgets block eviction again",
+			current:  []string{"default/a"},
+			pdbs:     []string{"default/a"},
+			expected: nil,
+		},
+		{
+			title:    "PodDisruptionBudgets are changed",
+			current:  []string{"default/a"},
+			pdbs:     []string{"default/a", "default/b"},
+			expected: []string{"EvictionBlocked"},
+		},
+		{
+			title:    "Eviction is not blocked anymore",
+			current:  []string{"default/a"},
+			pdbs:     nil,
+			expected: nil,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		refresher := &operatorv1alpha1.AWSNodeRefresher{
+			Status: operatorv1alpha1.AWSNodeRefresherStatus{
+				BlockingPodDisruptionBudgets: c.current,
+			},
+		}
+		recorder := &mockedRecorder{}
+		r := &AWSNodeRefresherReconciler{
+			Client:   &mockedClient{},
+			Recorder: recorder,
+		}
+		if err := r.updateBlockingPodDisruptionBudgets(context.Background(), refresher, c.pdbs); err != nil {
+			t.Errorf("CASE: %s : %v", c.title, err)
+			continue
+		}
+		if !reflect.DeepEqual(recorder.reasons, c.expected) {
+			t.Errorf("CASE: %s : events are not matched, expected %v, but returned %v", c.title, c.expected, recorder.reasons)
+		}
+		if !reflect.DeepEqual(refresher.Status.BlockingPodDisruptionBudgets, c.pdbs) {
+			t.Errorf("CASE: %s : PodDisruptionBudgets are not matched, expected %v, but returned %v", c.title, c.pdbs, refresher.Status.BlockingPodDisruptionBudgets)
+		}
+	}
+}
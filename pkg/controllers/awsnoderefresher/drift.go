@@ -0,0 +1,114 @@
+package awsnoderefresher
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+	"github.com/h3poteto/node-manager/pkg/cloud"
+	"github.com/h3poteto/node-manager/pkg/util/klog"
+)
+
+// syncDriftedNodes detects nodes which are out of date against their AutoScalingGroups, and records them in the status.
+func (r *AWSNodeRefresherReconciler) syncDriftedNodes(ctx context.Context, refresher *operatorv1alpha1.AWSNodeRefresher) error {
+	detector, ok := r.cloud.(cloud.DriftDetector)
+	if !ok {
+		return fmt.Errorf("cloud provider %s does not support %s trigger", refresher.Spec.CloudProvider, operatorv1alpha1.RefreshTriggerDrift)
+	}
+	instances, err := detector.DriftedInstances(refresher.Spec.AutoScalingGroups)
+	if err != nil {
+		return err
+	}
+	drifted := driftedNodes(refresher.Status.AWSNodes, instances)
+	if reflect.DeepEqual(refresher.Status.DriftedNodes, drifted) {
+		return nil
+	}
+	if len(drifted) > len(refresher.Status.DriftedNodes) {
+		r.Recorder.Eventf(refresher, corev1.EventTypeNormal, "Detect drift", "%d nodes are out of date", len(drifted))
+	}
+	refresher.Status.DriftedNodes = drifted
+	refresher.Status.Revision += 1
+	if err := r.Client.Update(ctx, refresher); err != nil {
+		klog.Errorf(ctx, "failed to update refresher: %v", err)
+		return err
+	}
+	return nil
+}
+
+// driftedNodes returns nodes of the drifted instances sorted by name. Instances which have not joined the cluster are ignored.
+func driftedNodes(nodes []operatorv1alpha1.AWSNode, instances map[string]string) []operatorv1alpha1.DriftedNode {
+	var drifted []operatorv1alpha1.DriftedNode
+	for i := range nodes {
+		reason, ok := instances[nodes[i].InstanceID]
+		if !ok {
+			continue
+		}
+		drifted = append(drifted, operatorv1alpha1.DriftedNode{
+			Name:                 nodes[i].Name,
+			InstanceID:           nodes[i].InstanceID,
+			AutoScalingGroupName: nodes[i].AutoScalingGroupName,
+			Reason:               reason,
+		})
+	}
+	sort.Slice(drifted, func(i, j int) bool { return drifted[i].Name < drifted[j].Name })
+	return drifted
+}
+
+// refreshDue returns whether a refresh should start now.
+// In drift trigger, it starts when any node is drifted regardless of the schedule.
+// With amiSource or maxNodeAge, it starts when a new AMI is published or nodes are expired, and waits for the schedule if it is specified.
+// Any of them starts only while the maintenance window is open, but the refresh-now annotation starts it immediately.
+func refreshDue(refresher *operatorv1alpha1.AWSNodeRefresher, now *metav1.Time) bool {
+	if _, requested := refreshRequested(refresher); requested {
+		return true
+	}
+	if !windowOpen(refresher, now) {
+		return false
+	}
+	if refresher.Spec.Trigger == operatorv1alpha1.RefreshTriggerDrift {
+		return len(refresher.Status.DriftedNodes) > 0
+	}
+	if refresher.Spec.AMISource != nil {
+		if !amiRefreshPending(refresher) {
+			return false
+		}
+		return refresher.Status.NextUpdateTime == nil || refresher.Status.NextUpdateTime.Before(now)
+	}
+	if refresher.Spec.MaxNodeAge != nil {
+		if len(expiredNodes(refresher, now)) == 0 {
+			return false
+		}
+		return refresher.Status.NextUpdateTime == nil || refresher.Status.NextUpdateTime.Before(now)
+	}
+	return refresher.Status.NextUpdateTime != nil && refresher.Status.NextUpdateTime.Before(now)
+}
+
+// replaceCandidates returns nodes which should be replaced in the refresh.
+// In drift trigger they are only drifted nodes, with maxNodeAge they are only expired nodes,
+// otherwise all nodes are replaced from the oldest one. An on-demand refresh always replaces all nodes.
+func replaceCandidates(refresher *operatorv1alpha1.AWSNodeRefresher, now *metav1.Time) []operatorv1alpha1.AWSNode {
+	if refresher.Status.OnDemand {
+		return refresher.Status.AWSNodes
+	}
+	if refresher.Spec.MaxNodeAge != nil {
+		return expiredNodes(refresher, now)
+	}
+	if refresher.Spec.Trigger != operatorv1alpha1.RefreshTriggerDrift {
+		return refresher.Status.AWSNodes
+	}
+	var candidates []operatorv1alpha1.AWSNode
+	for _, node := range refresher.Status.AWSNodes {
+		for _, d := range refresher.Status.DriftedNodes {
+			if node.InstanceID == d.InstanceID {
+				candidates = append(candidates, node)
+				break
+			}
+		}
+	}
+	return candidates
+}
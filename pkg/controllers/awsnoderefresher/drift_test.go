@@ -0,0 +1,185 @@
+package awsnoderefresher
+
+import (
+	"context"
+	"log"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+	cloudaws "github.com/h3poteto/node-manager/pkg/cloud/aws"
+)
+
+func TestSyncDriftedNodes(t *testing.T) {
+	ctx := context.Background()
+	refresher := &operatorv1alpha1.AWSNodeRefresher{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-refresher",
+		},
+		Spec: operatorv1alpha1.AWSNodeRefresherSpec{
+			Region:            "us-east-1",
+			AutoScalingGroups: []operatorv1alpha1.AutoScalingGroup{{Name: "autoscaling-group"}},
+			Trigger:           operatorv1alpha1.RefreshTriggerDrift,
+		},
+		Status: operatorv1alpha1.AWSNodeRefresherStatus{
+			AWSNodes: []operatorv1alpha1.AWSNode{
+				{
+					Name:                 "worker-1",
+					InstanceID:           "instanceId-1",
+					AutoScalingGroupName: "autoscaling-group",
+				},
+				{
+					Name:                 "worker-2",
+					InstanceID:           "instanceId-2",
+					AutoScalingGroupName: "autoscaling-group",
+				},
+			},
+			Phase: operatorv1alpha1.AWSNodeRefresherScheduled,
+		},
+	}
+	instance := func(id, version string) *autoscaling.Instance {
+		return &autoscaling.Instance{
+			InstanceId:     aws.String(id),
+			LifecycleState: aws.String(autoscaling.LifecycleStateInService),
+			LaunchTemplate: &autoscaling.LaunchTemplateSpecification{
+				LaunchTemplateId: aws.String("lt-1"),
+				Version:          aws.String(version),
+			},
+		}
+	}
+	mockedASG := &mockedASGAPI{
+		DescribeAutoScalingGroupsOutput: &autoscaling.DescribeAutoScalingGroupsOutput{
+			AutoScalingGroups: []*autoscaling.Group{
+				{
+					AutoScalingGroupName: aws.String("autoscaling-group"),
+					LaunchTemplate: &autoscaling.LaunchTemplateSpecification{
+						LaunchTemplateId: aws.String("lt-1"),
+						Version:          aws.String("$Latest"),
+					},
+					Instances: []*autoscaling.Instance{
+						instance("instanceId-1", "1"),
+						instance("instanceId-2", "2"),
+						// Not joined the cluster yet.
+						instance("instanceId-3", "1"),
+					},
+				},
+			},
+		},
+	}
+	mockedEC2 := &mockedEC2API{
+		DescribeLaunchTemplateVersionsResp: &ec2.DescribeLaunchTemplateVersionsOutput{
+			LaunchTemplateVersions: []*ec2.LaunchTemplateVersion{
+				{
+					LaunchTemplateId: aws.String("lt-1"),
+					VersionNumber:    aws.Int64(2),
+				},
+			},
+		},
+	}
+	r := &AWSNodeRefresherReconciler{
+		cloud: &cloudaws.AWS{
+			Autoscaling: mockedASG,
+			EC2:         mockedEC2,
+		},
+		Client:   &mockedClient{},
+		Recorder: &mockedRecorder{},
+	}
+
+	if err := r.syncDriftedNodes(ctx, refresher); err != nil {
+		t.Fatal(err)
+	}
+	expected := []operatorv1alpha1.DriftedNode{
+		{
+			Name:                 "worker-1",
+			InstanceID:           "instanceId-1",
+			AutoScalingGroupName: "autoscaling-group",
+			Reason:               "launch template version 1 is not the current version 2",
+		},
+	}
+	if !reflect.DeepEqual(refresher.Status.DriftedNodes, expected) {
+		t.Errorf("drifted nodes are not matched, expected %v, but returned %v", expected, refresher.Status.DriftedNodes)
+	}
+	if !refreshDue(refresher, &metav1.Time{Time: time.Now()}) {
+		t.Errorf("refresh should be due when nodes are drifted")
+	}
+	candidates := replaceCandidates(refresher, &metav1.Time{Time: time.Now()})
+	if len(candidates) != 1 || candidates[0].Name != "worker-1" {
+		t.Errorf("only drifted nodes should be replaced, but returned %v", candidates)
+	}
+}
+
+func TestRefreshDue(t *testing.T) {
+	cases := []struct {
+		title     string
+		refresher *operatorv1alpha1.AWSNodeRefresher
+		expected  bool
+	}{
+		{
+			title: "Schedule has come",
+			refresher: &operatorv1alpha1.AWSNodeRefresher{
+				Status: operatorv1alpha1.AWSNodeRefresherStatus{
+					NextUpdateTime: &metav1.Time{
+						Time: time.Now().Add(-1 * time.Minute),
+					},
+				},
+			},
+			expected: true,
+		},
+		{
+			title: "Schedule has not come",
+			refresher: &operatorv1alpha1.AWSNodeRefresher{
+				Status: operatorv1alpha1.AWSNodeRefresherStatus{
+					NextUpdateTime: &metav1.Time{
+						Time: time.Now().Add(1 * time.Hour),
+					},
+				},
+			},
+			expected: false,
+		},
+		{
+			title: "No nodes are drifted",
+			refresher: &operatorv1alpha1.AWSNodeRefresher{
+				Spec: operatorv1alpha1.AWSNodeRefresherSpec{
+					Trigger: operatorv1alpha1.RefreshTriggerDrift,
+				},
+				Status: operatorv1alpha1.AWSNodeRefresherStatus{
+					NextUpdateTime: nil,
+				},
+			},
+			expected: false,
+		},
+		{
+			title: "Nodes are drifted",
+			refresher: &operatorv1alpha1.AWSNodeRefresher{
+				Spec: operatorv1alpha1.AWSNodeRefresherSpec{
+					Trigger: operatorv1alpha1.RefreshTriggerDrift,
+				},
+				Status: operatorv1alpha1.AWSNodeRefresherStatus{
+					NextUpdateTime: nil,
+					DriftedNodes: []operatorv1alpha1.DriftedNode{
+						{
+							Name:       "worker-1",
+							InstanceID: "instanceId-1",
+						},
+					},
+				},
+			},
+			expected: true,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		now := metav1.Now()
+		result := refreshDue(c.refresher, &now)
+		if result != c.expected {
+			t.Errorf("CASE: %s : expected %v, but returned %v", c.title, c.expected, result)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package awsnoderefresher
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+	"github.com/h3poteto/node-manager/pkg/util/klog"
+)
+
+// phaseTimeout returns the reason of the failure when the current phase does not finish in its deadline, or empty.
+func phaseTimeout(refresher *operatorv1alpha1.AWSNodeRefresher, now *metav1.Time) string {
+	timeouts := refresher.Spec.Timeouts
+	if timeouts == nil || refresher.Status.PhaseStartTime == nil {
+		return ""
+	}
+	var timeout *metav1.Duration
+	switch refresher.Status.Phase {
+	case operatorv1alpha1.AWSNodeRefresherUpdateIncreasing:
+		timeout = timeouts.Increase
+	case operatorv1alpha1.AWSNodeRefresherUpdateReplacing:
+		timeout = timeouts.Replace
+	case operatorv1alpha1.AWSNodeRefresherUpdateAWSWaiting:
+		timeout = timeouts.AWSWait
+	case operatorv1alpha1.AWSNodeRefresherUpdateDecreasing:
+		timeout = timeouts.Decrease
+	}
+	if timeout == nil || now.Time.Before(refresher.Status.PhaseStartTime.Add(timeout.Duration)) {
+		return ""
+	}
+	return fmt.Sprintf("%s phase did not finish in %s, current nodes: %d, desired: %d + %d", refresher.Status.Phase, timeout.Duration, len(refresher.Status.AWSNodes), refresher.Spec.Desired, surplusNodes(refresher))
+}
+
+// refreshFail aborts the refresh. It uncordons the drain targets, and removes the surplus nodes in failed phase.
+func (r *AWSNodeRefresherReconciler) refreshFail(ctx context.Context, refresher *operatorv1alpha1.AWSNodeRefresher, reason string) error {
+	for _, target := range replaceTargets(refresher) {
+		if err := r.uncordon(ctx, target.Name); err != nil {
+			return err
+		}
+	}
+
+	refresher.Status.Phase = operatorv1alpha1.AWSNodeRefresherFailed
+	refresher.Status.FailureReason = reason
+	refresher.Status.SurplusRolledBack = false
+	refresher.Status.UpdateStartTime = nil
+	refresher.Status.PhaseStartTime = nil
+	refresher.Status.ReplaceTargetNode = nil
+	refresher.Status.ReplaceTargetNodes = nil
+	refresher.Status.BlockingPodDisruptionBudgets = nil
+	refresher.Status.OnDemand = false
+	refresher.Status.Revision += 1
+	if err := r.Client.Update(ctx, refresher); err != nil {
+		klog.Errorf(ctx, "failed to update refresher: %v", err)
+		return err
+	}
+	r.Recorder.Eventf(refresher, corev1.EventTypeWarning, "Refresh failed", "Abort refresh: %s", reason)
+
+	return r.rollbackSurplus(ctx, refresher)
+}
+
+// rollbackSurplus removes the surplus instances which are added for the failed refresh.
+// Instances are counted in the cloud provider instead of nodes, because the new instance may never join the cluster.
+// It is retried until the instances go back to desired, and it is not called again after that.
+func (r *AWSNodeRefresherReconciler) rollbackSurplus(ctx context.Context, refresher *operatorv1alpha1.AWSNodeRefresher) error {
+	if refresher.Status.SurplusRolledBack {
+		return nil
+	}
+	if surplusNodes(refresher) == 0 {
+		return r.finishRollback(ctx, refresher)
+	}
+	now := metav1.Now()
+	if refresher.Status.LastASGModifiedTime != nil && waiting(refresher, &now) {
+		return nil
+	}
+	groups, err := r.cloud.DescribeNodeGroups(refresher.Spec.AutoScalingGroups)
+	if err != nil {
+		return err
+	}
+	instances := 0
+	for _, group := range groups {
+		instances += len(group.InstanceIDs)
+	}
+	if instances <= int(refresher.Spec.Desired) {
+		return r.finishRollback(ctx, refresher)
+	}
+
+	refresher.Status.LastASGModifiedTime = &now
+	refresher.Status.Revision += 1
+	if err := r.Client.Update(ctx, refresher); err != nil {
+		klog.Errorf(ctx, "failed to update refresher: %v", err)
+		return err
+	}
+	r.Recorder.Eventf(refresher, corev1.EventTypeNormal, "Rollback surplus", "Remove surplus instances of the failed refresh, current: %d, desired: %d", instances, refresher.Spec.Desired)
+
+	return r.cloud.ScaleDownNodeGroups(refresher.Spec.AutoScalingGroups, int(refresher.Spec.Desired), instances)
+}
+
+func (r *AWSNodeRefresherReconciler) finishRollback(ctx context.Context, refresher *operatorv1alpha1.AWSNodeRefresher) error {
+	refresher.Status.SurplusRolledBack = true
+	refresher.Status.Revision += 1
+	if err := r.Client.Update(ctx, refresher); err != nil {
+		klog.Errorf(ctx, "failed to update refresher: %v", err)
+		return err
+	}
+	klog.Infof(ctx, "Surplus instances of the failed refresh are removed")
+	return nil
+}
+
+// uncordon makes the node schedulable again. It does nothing when the node has already gone.
+func (r *AWSNodeRefresherReconciler) uncordon(ctx context.Context, nodeName string) error {
+	var node corev1.Node
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: nodeName}, &node); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		klog.Errorf(ctx, "Failed to get node: %v", err)
+		return err
+	}
+	if !node.Spec.Unschedulable {
+		return nil
+	}
+	node.Spec.Unschedulable = false
+	if err := r.Client.Update(ctx, &node); err != nil {
+		klog.Errorf(ctx, "Failed to update node: %v", err)
+		return err
+	}
+	klog.Infof(ctx, "Uncordon node %s", nodeName)
+	return nil
+}
@@ -0,0 +1,276 @@
+package awsnoderefresher
+
+import (
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+	cloudaws "github.com/h3poteto/node-manager/pkg/cloud/aws"
+)
+
+func TestPhaseTimeout(t *testing.T) {
+	timeouts := &operatorv1alpha1.PhaseTimeouts{
+		Increase: &metav1.Duration{Duration: 30 * time.Minute},
+		AWSWait:  &metav1.Duration{Duration: 10 * time.Minute},
+	}
+	cases := []struct {
+		title          string
+		timeouts       *operatorv1alpha1.PhaseTimeouts
+		phase          operatorv1alpha1.AWSNodeRefresherPhase
+		phaseStartTime *metav1.Time
+		expected       bool
+	}{
+		{
+			title:          "Timeouts are not specified",
+			timeouts:       nil,
+			phase:          operatorv1alpha1.AWSNodeRefresherUpdateIncreasing,
+			phaseStartTime: &metav1.Time{Time: time.Now().Add(-1 * time.Hour)},
+			expected:       false,
+		},
+		{
+			title:          "Increase is in the deadline",
+			timeouts:       timeouts,
+			phase:          operatorv1alpha1.AWSNodeRefresherUpdateIncreasing,
+			phaseStartTime: &metav1.Time{Time: time.Now().Add(-20 * time.Minute)},
+			expected:       false,
+		},
+		{
+			title:          "AWS wait passes the deadline",
+			timeouts:       timeouts,
+			phase:          operatorv1alpha1.AWSNodeRefresherUpdateAWSWaiting,
+			phaseStartTime: &metav1.Time{Time: time.Now().Add(-20 * time.Minute)},
+			expected:       true,
+		},
+		{
+			title:          "Decrease does not have a deadline",
+			timeouts:       timeouts,
+			phase:          operatorv1alpha1.AWSNodeRefresherUpdateDecreasing,
+			phaseStartTime: &metav1.Time{Time: time.Now().Add(-1 * time.Hour)},
+			expected:       false,
+		},
+		{
+			title:          "Scheduled phase does not time out",
+			timeouts:       timeouts,
+			phase:          operatorv1alpha1.AWSNodeRefresherScheduled,
+			phaseStartTime: &metav1.Time{Time: time.Now().Add(-1 * time.Hour)},
+			expected:       false,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		refresher := &operatorv1alpha1.AWSNodeRefresher{
+			Spec: operatorv1alpha1.AWSNodeRefresherSpec{
+				Timeouts: c.timeouts,
+			},
+			Status: operatorv1alpha1.AWSNodeRefresherStatus{
+				Phase:          c.phase,
+				PhaseStartTime: c.phaseStartTime,
+			},
+		}
+		now := metav1.Now()
+		reason := phaseTimeout(refresher, &now)
+		if (reason != "") != c.expected {
+			t.Errorf("CASE: %s : expected %v, but returned %q", c.title, c.expected, reason)
+		}
+	}
+}
+
+func TestRefreshFail(t *testing.T) {
+	ctx := context.Background()
+	refresher := &operatorv1alpha1.AWSNodeRefresher{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-refresher",
+		},
+		Spec: operatorv1alpha1.AWSNodeRefresherSpec{
+			Region: "us-east-1",
+			AutoScalingGroups: []operatorv1alpha1.AutoScalingGroup{
+				{
+					Name: "asg-1",
+				},
+			},
+			Desired:                  2,
+			ASGModifyCoolTimeSeconds: 600,
+			Schedule:                 "0 3 * * *",
+			SurplusNodes:             1,
+			Timeouts: &operatorv1alpha1.PhaseTimeouts{
+				AWSWait: &metav1.Duration{Duration: 10 * time.Minute},
+			},
+		},
+		Status: operatorv1alpha1.AWSNodeRefresherStatus{
+			// The new instance has never joined the cluster.
+			AWSNodes: []operatorv1alpha1.AWSNode{
+				{
+					Name:                 "worker-2",
+					InstanceID:           "instanceId-2",
+					AutoScalingGroupName: "asg-1",
+				},
+				{
+					Name:                 "worker-3",
+					InstanceID:           "instanceId-3",
+					AutoScalingGroupName: "asg-1",
+				},
+			},
+			LastASGModifiedTime: &metav1.Time{Time: time.Now().Add(-30 * time.Minute)},
+			Phase:               operatorv1alpha1.AWSNodeRefresherUpdateAWSWaiting,
+			PhaseStartTime:      &metav1.Time{Time: time.Now().Add(-30 * time.Minute)},
+			UpdateStartTime:     &metav1.Time{Time: time.Now().Add(-1 * time.Hour)},
+			ReplaceTargetNode: &operatorv1alpha1.AWSNode{
+				Name:       "worker-1",
+				InstanceID: "instanceId-1",
+			},
+		},
+	}
+	mockedASG := &mockedASGAPI{
+		DescribeAutoScalingGroupsOutput: &autoscaling.DescribeAutoScalingGroupsOutput{
+			AutoScalingGroups: []*autoscaling.Group{
+				{
+					AutoScalingGroupName: aws.String("asg-1"),
+					DesiredCapacity:      aws.Int64(3),
+					MinSize:              aws.Int64(0),
+					MaxSize:              aws.Int64(5),
+					Instances: []*autoscaling.Instance{
+						{InstanceId: aws.String("instanceId-2")},
+						{InstanceId: aws.String("instanceId-3")},
+						{InstanceId: aws.String("instanceId-4")},
+					},
+				},
+			},
+		},
+		UpdateAutoScalingGroupOutput: &autoscaling.UpdateAutoScalingGroupOutput{},
+	}
+	cordoned := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "worker-1",
+		},
+		Spec: corev1.NodeSpec{
+			Unschedulable: true,
+		},
+	}
+	r := &AWSNodeRefresherReconciler{
+		cloud: &cloudaws.AWS{
+			Autoscaling: mockedASG,
+		},
+		Client: &mockedClient{
+			getFunc: func(obj client.Object) error {
+				node := obj.(*corev1.Node)
+				*node = *cordoned
+				// Nodes are updated through the pointer in the mock, so keep the result.
+				cordoned = node
+				return nil
+			},
+		},
+		Recorder: &mockedRecorder{},
+	}
+
+	if err := r.syncRefresher(ctx, refresher); err != nil {
+		t.Fatal(err)
+	}
+	if refresher.Status.Phase != operatorv1alpha1.AWSNodeRefresherFailed {
+		t.Errorf("phase is not matched, expected %s, but returned %s", operatorv1alpha1.AWSNodeRefresherFailed, refresher.Status.Phase)
+	}
+	if refresher.Status.FailureReason == "" {
+		t.Errorf("failure reason is not recorded")
+	}
+	if refresher.Status.ReplaceTargetNode != nil || len(refresher.Status.ReplaceTargetNodes) != 0 {
+		t.Errorf("replace target should be cleared, but returned %v", refresher.Status.ReplaceTargetNodes)
+	}
+	if cordoned.Spec.Unschedulable {
+		t.Errorf("drain target should be uncordoned")
+	}
+	if refresher.Status.LastASGModifiedTime.Before(&metav1.Time{Time: time.Now().Add(-1 * time.Minute)}) {
+		t.Errorf("surplus instances should be removed")
+	}
+}
+
+func TestRollbackSurplus(t *testing.T) {
+	ctx := context.Background()
+	refresher := &operatorv1alpha1.AWSNodeRefresher{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-refresher",
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(&operatorv1alpha1.AWSNodeManager{ObjectMeta: metav1.ObjectMeta{Name: "test-manager"}}, operatorv1alpha1.GroupVersion.WithKind("AWSNodeManager")),
+			},
+		},
+		Spec: operatorv1alpha1.AWSNodeRefresherSpec{
+			Region: "us-east-1",
+			AutoScalingGroups: []operatorv1alpha1.AutoScalingGroup{
+				{
+					Name: "asg-1",
+				},
+			},
+			Desired:                  2,
+			ASGModifyCoolTimeSeconds: 600,
+			Schedule:                 "0 3 * * *",
+			SurplusNodes:             1,
+		},
+		Status: operatorv1alpha1.AWSNodeRefresherStatus{
+			LastASGModifiedTime: &metav1.Time{Time: time.Now().Add(-30 * time.Minute)},
+			Phase:               operatorv1alpha1.AWSNodeRefresherFailed,
+			FailureReason:       "awsWait phase did not finish in 10m0s",
+		},
+	}
+	mockedASG := &mockedASGAPI{
+		DescribeAutoScalingGroupsOutput: &autoscaling.DescribeAutoScalingGroupsOutput{
+			AutoScalingGroups: []*autoscaling.Group{
+				{
+					AutoScalingGroupName: aws.String("asg-1"),
+					DesiredCapacity:      aws.Int64(2),
+					MinSize:              aws.Int64(0),
+					MaxSize:              aws.Int64(5),
+					Instances: []*autoscaling.Instance{
+						{InstanceId: aws.String("instanceId-2")},
+						{InstanceId: aws.String("instanceId-3")},
+					},
+				},
+			},
+		},
+		UpdateAutoScalingGroupOutput: &autoscaling.UpdateAutoScalingGroupOutput{},
+	}
+	r := &AWSNodeRefresherReconciler{
+		cloud: &cloudaws.AWS{
+			Autoscaling: mockedASG,
+		},
+		Client: &mockedClient{
+			getFunc: func(obj client.Object) error {
+				return nil
+			},
+		},
+		Recorder: &mockedRecorder{},
+	}
+
+	if err := r.syncRefresher(ctx, refresher); err != nil {
+		t.Fatal(err)
+	}
+	if !refresher.Status.SurplusRolledBack {
+		t.Errorf("rollback should be finished when instances go back to desired")
+	}
+	// The next schedule retries the refresh, and the reason of the last failure is kept until then.
+	if refresher.Status.Phase != operatorv1alpha1.AWSNodeRefresherScheduled || refresher.Status.NextUpdateTime == nil {
+		t.Errorf("next refresh should be scheduled after the rollback, but phase is %s", refresher.Status.Phase)
+	}
+	if refresher.Status.FailureReason == "" {
+		t.Errorf("failure reason should be kept until the next refresh starts")
+	}
+
+	// An instance which is launched after the rollback is not a surplus of the failed refresh.
+	lastModified := refresher.Status.LastASGModifiedTime
+	mockedASG.DescribeAutoScalingGroupsOutput.AutoScalingGroups[0].Instances = append(mockedASG.DescribeAutoScalingGroupsOutput.AutoScalingGroups[0].Instances, &autoscaling.Instance{InstanceId: aws.String("instanceId-4")})
+	if err := r.syncRefresher(ctx, refresher); err != nil {
+		t.Fatal(err)
+	}
+	if refresher.Status.LastASGModifiedTime != lastModified {
+		t.Errorf("node groups should not be scaled down after the rollback is finished")
+	}
+	if refresher.Status.Phase != operatorv1alpha1.AWSNodeRefresherScheduled {
+		t.Errorf("phase is not matched, expected %s, but returned %s", operatorv1alpha1.AWSNodeRefresherScheduled, refresher.Status.Phase)
+	}
+}
@@ -21,6 +21,8 @@ func (r *AWSNodeRefresherReconciler) refreshIncrease(ctx context.Context, refres
 	if skip {
 		refresher.Status.Phase = operatorv1alpha1.AWSNodeRefresherUpdateIncreasing
 		refresher.Status.UpdateStartTime = &now
+		refresher.Status.PhaseStartTime = &now
+		acknowledgeRefresh(refresher)
 		refresher.Status.Revision += 1
 		err := r.Client.Update(ctx, refresher)
 		if err != nil {
@@ -37,6 +39,8 @@ func (r *AWSNodeRefresherReconciler) refreshIncrease(ctx context.Context, refres
 	refresher.Status.Phase = operatorv1alpha1.AWSNodeRefresherUpdateIncreasing
 	refresher.Status.UpdateStartTime = &now
 	refresher.Status.LastASGModifiedTime = &now
+	refresher.Status.PhaseStartTime = &now
+	acknowledgeRefresh(refresher)
 	refresher.Status.Revision += 1
 	if err := r.Client.Update(ctx, refresher); err != nil {
 		klog.Errorf(ctx, "failed to update refresher: %v", err)
@@ -44,7 +48,7 @@ func (r *AWSNodeRefresherReconciler) refreshIncrease(ctx context.Context, refres
 	}
 	r.Recorder.Event(refresher, corev1.EventTypeNormal, "Increase instance", "Increase instance to ASG for refresh")
 
-	return r.cloud.AddInstancesToAutoScalingGroups(refresher.Spec.AutoScalingGroups, int(refresher.Spec.Desired)+int(refresher.Spec.SurplusNodes), len(refresher.Status.AWSNodes))
+	return r.cloud.ScaleUpNodeGroups(refresher.Spec.AutoScalingGroups, int(refresher.Spec.Desired)+surplusNodes(refresher), len(refresher.Status.AWSNodes))
 }
 
 func shouldIncrease(ctx context.Context, refresher *operatorv1alpha1.AWSNodeRefresher, now *metav1.Time, owner *operatorv1alpha1.AWSNodeManager) (bool, bool) {
@@ -56,8 +60,8 @@ func shouldIncrease(ctx context.Context, refresher *operatorv1alpha1.AWSNodeRefr
 		klog.Info(ctx, "Now replenishing, so skip refresh")
 		return false, false
 	}
-	if refresher.Status.NextUpdateTime.Before(now) {
-		if refresher.Spec.SurplusNodes == 0 {
+	if refreshDue(refresher, now) {
+		if surplusNodes(refresher) == 0 {
 			return false, true
 		} else {
 			return true, false
@@ -83,16 +87,16 @@ func (r *AWSNodeRefresherReconciler) retryIncrease(ctx context.Context, refreshe
 	}
 	r.Recorder.Event(refresher, corev1.EventTypeNormal, "Retry increase", "Retry to increase instance to ASG for refresh")
 
-	err := r.cloud.AddInstancesToAutoScalingGroups(
+	err := r.cloud.ScaleUpNodeGroups(
 		refresher.Spec.AutoScalingGroups,
-		int(refresher.Spec.Desired)+int(refresher.Spec.SurplusNodes),
+		int(refresher.Spec.Desired)+surplusNodes(refresher),
 		len(refresher.Status.AWSNodes),
 	)
 	return false, true, err
 }
 
 func waitingIncrease(ctx context.Context, refresher *operatorv1alpha1.AWSNodeRefresher, now *metav1.Time) bool {
-	if len(refresher.Status.AWSNodes) >= int(refresher.Spec.Desired)+int(refresher.Spec.SurplusNodes) {
+	if len(refresher.Status.AWSNodes) >= int(refresher.Spec.Desired)+surplusNodes(refresher) {
 		return false
 	}
 	if now.Time.After(refresher.Status.LastASGModifiedTime.Add(time.Duration(refresher.Spec.ASGModifyCoolTimeSeconds) * time.Second)) {
@@ -107,7 +111,7 @@ func shouldRetryIncrease(ctx context.Context, refresher *operatorv1alpha1.AWSNod
 		klog.Warningf(ctx, "AWSNodeRefresher phase is not matched: %s, so should not retry to increase", refresher.Status.Phase)
 		return false
 	}
-	if len(refresher.Status.AWSNodes) < int(refresher.Spec.Desired)+int(refresher.Spec.SurplusNodes) {
+	if len(refresher.Status.AWSNodes) < int(refresher.Spec.Desired)+surplusNodes(refresher) {
 		return true
 	}
 	return false
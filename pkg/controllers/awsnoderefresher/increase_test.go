@@ -876,8 +876,12 @@ func TestRetryIncrease(t *testing.T) {
 					Name: "test-refresher",
 				},
 				Spec: operatorv1alpha1.AWSNodeRefresherSpec{
-					Region:                   "us-east-1",
-					AutoScalingGroups:        nil,
+					Region: "us-east-1",
+					AutoScalingGroups: []operatorv1alpha1.AutoScalingGroup{
+						{
+							Name: "asg-1",
+						},
+					},
 					Desired:                  2,
 					ASGModifyCoolTimeSeconds: 600,
 					Role:                     operatorv1alpha1.Worker,
@@ -0,0 +1,249 @@
+package awsnoderefresher
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+	"github.com/h3poteto/node-manager/pkg/cloud"
+	"github.com/h3poteto/node-manager/pkg/util/klog"
+)
+
+// startInstanceRefresh starts Instance Refresh in every AutoScalingGroup instead of replacing nodes one by one.
+func (r *AWSNodeRefresherReconciler) startInstanceRefresh(ctx context.Context, refresher *operatorv1alpha1.AWSNodeRefresher) error {
+	owner, err := r.ownerAWSNodeManager(ctx, refresher)
+	if err != nil {
+		return err
+	}
+	now := metav1.Now()
+	if !shouldStartInstanceRefresh(ctx, refresher, &now, owner) {
+		return nil
+	}
+	instanceRefresher, err := r.instanceRefresher(refresher)
+	if err != nil {
+		return err
+	}
+
+	preferences := refresher.Spec.InstanceRefresh
+	_, requested := refreshRequested(refresher)
+	if refresher.Spec.Trigger == operatorv1alpha1.RefreshTriggerDrift && !requested {
+		// Replace only instances whose launch template is out of date.
+		preferences = preferences.DeepCopy()
+		if preferences == nil {
+			preferences = &operatorv1alpha1.InstanceRefreshPreferences{}
+		}
+		preferences.SkipMatching = true
+	}
+	var refreshes []operatorv1alpha1.InstanceRefreshStatus
+	for _, group := range refresher.Spec.AutoScalingGroups {
+		id, err := instanceRefresher.StartInstanceRefresh(group.Name, preferences)
+		if err != nil {
+			return err
+		}
+		refreshes = append(refreshes, operatorv1alpha1.InstanceRefreshStatus{
+			AutoScalingGroupName: group.Name,
+			InstanceRefreshID:    id,
+		})
+	}
+
+	refresher.Status.Phase = operatorv1alpha1.AWSNodeRefresherInstanceRefreshing
+	refresher.Status.UpdateStartTime = &now
+	refresher.Status.InstanceRefreshes = refreshes
+	refresher.Status.DrainingNodes = nil
+	acknowledgeRefresh(refresher)
+	refresher.Status.Revision += 1
+	if err := r.Client.Update(ctx, refresher); err != nil {
+		klog.Errorf(ctx, "failed to update refresher: %v", err)
+		return err
+	}
+	r.Recorder.Eventf(refresher, corev1.EventTypeNormal, "Start instance refresh", "Start instance refresh of %s", strings.Join(refreshGroupNames(refreshes), ", "))
+	return nil
+}
+
+func shouldStartInstanceRefresh(ctx context.Context, refresher *operatorv1alpha1.AWSNodeRefresher, now *metav1.Time, owner *operatorv1alpha1.AWSNodeManager) bool {
+	if refresher.Status.Phase != operatorv1alpha1.AWSNodeRefresherScheduled {
+		klog.Warningf(ctx, "AWSNodeRefresher phase is not matched: %s, so should not start instance refresh", refresher.Status.Phase)
+		return false
+	}
+	if owner != nil && owner.Status.Phase == operatorv1alpha1.AWSNodeManagerReplenishing {
+		klog.Info(ctx, "Now replenishing, so skip refresh")
+		return false
+	}
+	return refreshDue(refresher, now)
+}
+
+// syncInstanceRefresh drains nodes which wait for the lifecycle hook, and reflects progress of Instance Refreshes to the status.
+func (r *AWSNodeRefresherReconciler) syncInstanceRefresh(ctx context.Context, refresher *operatorv1alpha1.AWSNodeRefresher) error {
+	instanceRefresher, err := r.instanceRefresher(refresher)
+	if err != nil {
+		return err
+	}
+	if refresher.Spec.InstanceRefresh != nil && refresher.Spec.InstanceRefresh.LifecycleHookName != "" {
+		if err := r.drainTerminatingNodes(ctx, refresher, instanceRefresher, refresher.Spec.InstanceRefresh.LifecycleHookName); err != nil {
+			return err
+		}
+	}
+
+	finished := true
+	var failed []string
+	refreshes := make([]operatorv1alpha1.InstanceRefreshStatus, 0, len(refresher.Status.InstanceRefreshes))
+	for _, s := range refresher.Status.InstanceRefreshes {
+		state, err := instanceRefresher.DescribeInstanceRefresh(s.AutoScalingGroupName, s.InstanceRefreshID)
+		if err != nil {
+			return err
+		}
+		refreshes = append(refreshes, operatorv1alpha1.InstanceRefreshStatus{
+			AutoScalingGroupName: s.AutoScalingGroupName,
+			InstanceRefreshID:    s.InstanceRefreshID,
+			Status:               state.Status,
+			StatusReason:         state.StatusReason,
+			PercentageComplete:   int64(state.PercentageComplete),
+			InstancesToUpdate:    int64(state.InstancesToUpdate),
+		})
+		if !state.Finished {
+			finished = false
+		} else if !state.Succeeded {
+			failed = append(failed, fmt.Sprintf("%s(%s)", s.AutoScalingGroupName, state.Status))
+		}
+	}
+	if !finished {
+		if reflect.DeepEqual(refresher.Status.InstanceRefreshes, refreshes) {
+			return nil
+		}
+		refresher.Status.InstanceRefreshes = refreshes
+		refresher.Status.Revision += 1
+		if err := r.Client.Update(ctx, refresher); err != nil {
+			klog.Errorf(ctx, "failed to update refresher: %v", err)
+			return err
+		}
+		return nil
+	}
+
+	// Keep the result of Instance Refreshes in the status until the next refresh.
+	refresher.Status.Phase = operatorv1alpha1.AWSNodeRefresherCompleted
+	refresher.Status.InstanceRefreshes = refreshes
+	refresher.Status.UpdateStartTime = nil
+	refresher.Status.DrainingNodes = nil
+	refresher.Status.BlockingPodDisruptionBudgets = nil
+	refresher.Status.OnDemand = false
+	if len(failed) > 0 {
+		// EC2 Auto Scaling does not add surplus instances for the controller, so there is nothing to roll back.
+		refresher.Status.Phase = operatorv1alpha1.AWSNodeRefresherFailed
+		refresher.Status.FailureReason = fmt.Sprintf("Instance refresh did not succeed in %s", strings.Join(failed, ", "))
+		refresher.Status.SurplusRolledBack = true
+	}
+	refresher.Status.Revision += 1
+	if err := r.Client.Update(ctx, refresher); err != nil {
+		klog.Errorf(ctx, "failed to update refresher: %v", err)
+		return err
+	}
+	if len(failed) > 0 {
+		r.Recorder.Event(refresher, corev1.EventTypeWarning, "Instance refresh failed", refresher.Status.FailureReason)
+		return nil
+	}
+	r.Recorder.Event(refresher, corev1.EventTypeNormal, "Completed refresh", "Completed to refresh")
+	return nil
+}
+
+// drainTerminatingNodes drains nodes of instances which wait for the termination lifecycle hook,
+// and completes the lifecycle action when no pods remain or drainGracePeriodSeconds passes.
+func (r *AWSNodeRefresherReconciler) drainTerminatingNodes(ctx context.Context, refresher *operatorv1alpha1.AWSNodeRefresher, instanceRefresher cloud.InstanceRefresher, hook string) error {
+	now := metav1.Now()
+	var draining []operatorv1alpha1.DrainingNode
+	var blocking []string
+	for _, s := range refresher.Status.InstanceRefreshes {
+		ids, err := instanceRefresher.TerminationWaitingInstances(s.AutoScalingGroupName)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			node := findDrainingNode(refresher.Status.DrainingNodes, id)
+			if node == nil {
+				awsNode := findNodeByInstanceID(refresher.Status.AWSNodes, id)
+				if awsNode == nil {
+					klog.Infof(ctx, "Instance %s is not a node of the cluster, so it is terminated without drain", id)
+					if err := instanceRefresher.CompleteTerminationLifecycleAction(s.AutoScalingGroupName, hook, id); err != nil {
+						return err
+					}
+					continue
+				}
+				node = &operatorv1alpha1.DrainingNode{AWSNode: *awsNode, DrainStartTime: now}
+				r.Recorder.Eventf(refresher, corev1.EventTypeNormal, "Drain node", "Drain node %s", node.Name)
+			}
+			pdbs, err := r.drain(ctx, node.Name)
+			if apierrors.IsNotFound(err) {
+				klog.Infof(ctx, "Node %s has already been removed", node.Name)
+			} else if err != nil {
+				return err
+			} else if r.shouldRetryDrain(ctx, refresher, node.Name) && !drainTimedOut(refresher, node, &now) {
+				draining = append(draining, *node)
+				blocking = mergePodDisruptionBudgets(blocking, pdbs)
+				continue
+			}
+			if err := instanceRefresher.CompleteTerminationLifecycleAction(s.AutoScalingGroupName, hook, id); err != nil {
+				return err
+			}
+			r.Recorder.Eventf(refresher, corev1.EventTypeNormal, "Replace instance", "Let instance %s of node %s be terminated", id, node.Name)
+		}
+	}
+	if len(blocking) > 0 && !reflect.DeepEqual(refresher.Status.BlockingPodDisruptionBudgets, blocking) {
+		r.Recorder.Eventf(refresher, corev1.EventTypeWarning, "EvictionBlocked", "Eviction is blocked by PodDisruptionBudgets: %s", strings.Join(blocking, ", "))
+	}
+	if reflect.DeepEqual(refresher.Status.DrainingNodes, draining) && reflect.DeepEqual(refresher.Status.BlockingPodDisruptionBudgets, blocking) {
+		return nil
+	}
+	refresher.Status.DrainingNodes = draining
+	refresher.Status.BlockingPodDisruptionBudgets = blocking
+	refresher.Status.Revision += 1
+	if err := r.Client.Update(ctx, refresher); err != nil {
+		klog.Errorf(ctx, "failed to update refresher: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (r *AWSNodeRefresherReconciler) instanceRefresher(refresher *operatorv1alpha1.AWSNodeRefresher) (cloud.InstanceRefresher, error) {
+	instanceRefresher, ok := r.cloud.(cloud.InstanceRefresher)
+	if !ok {
+		return nil, fmt.Errorf("cloud provider %s does not support %s strategy", refresher.Spec.CloudProvider, operatorv1alpha1.RefreshStrategyInstanceRefresh)
+	}
+	return instanceRefresher, nil
+}
+
+func drainTimedOut(refresher *operatorv1alpha1.AWSNodeRefresher, node *operatorv1alpha1.DrainingNode, now *metav1.Time) bool {
+	return now.Time.After(node.DrainStartTime.Add(time.Duration(refresher.Spec.DrainGracePeriodSeconds) * time.Second))
+}
+
+func findDrainingNode(nodes []operatorv1alpha1.DrainingNode, instanceID string) *operatorv1alpha1.DrainingNode {
+	for i := range nodes {
+		if nodes[i].InstanceID == instanceID {
+			node := nodes[i]
+			return &node
+		}
+	}
+	return nil
+}
+
+func findNodeByInstanceID(nodes []operatorv1alpha1.AWSNode, instanceID string) *operatorv1alpha1.AWSNode {
+	for i := range nodes {
+		if nodes[i].InstanceID == instanceID {
+			return &nodes[i]
+		}
+	}
+	return nil
+}
+
+func refreshGroupNames(refreshes []operatorv1alpha1.InstanceRefreshStatus) []string {
+	var names []string
+	for _, r := range refreshes {
+		names = append(names, r.AutoScalingGroupName)
+	}
+	return names
+}
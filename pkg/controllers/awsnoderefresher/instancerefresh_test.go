@@ -0,0 +1,301 @@
+package awsnoderefresher
+
+import (
+	"context"
+	"log"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+	cloudaws "github.com/h3poteto/node-manager/pkg/cloud/aws"
+)
+
+func TestShouldStartInstanceRefresh(t *testing.T) {
+	cases := []struct {
+		title     string
+		refresher *operatorv1alpha1.AWSNodeRefresher
+		owner     *operatorv1alpha1.AWSNodeManager
+		expected  bool
+	}{
+		{
+			title: "Next update time has come",
+			refresher: &operatorv1alpha1.AWSNodeRefresher{
+				Status: operatorv1alpha1.AWSNodeRefresherStatus{
+					Phase: operatorv1alpha1.AWSNodeRefresherScheduled,
+					NextUpdateTime: &metav1.Time{
+						Time: time.Now().Add(-1 * time.Minute),
+					},
+				},
+			},
+			owner:    nil,
+			expected: true,
+		},
+		{
+			title: "Next update time has not come",
+			refresher: &operatorv1alpha1.AWSNodeRefresher{
+				Status: operatorv1alpha1.AWSNodeRefresherStatus{
+					Phase: operatorv1alpha1.AWSNodeRefresherScheduled,
+					NextUpdateTime: &metav1.Time{
+						Time: time.Now().Add(1 * time.Hour),
+					},
+				},
+			},
+			owner:    nil,
+			expected: false,
+		},
+		{
+			title: "Instance refresh is already running",
+			refresher: &operatorv1alpha1.AWSNodeRefresher{
+				Status: operatorv1alpha1.AWSNodeRefresherStatus{
+					Phase: operatorv1alpha1.AWSNodeRefresherInstanceRefreshing,
+					NextUpdateTime: &metav1.Time{
+						Time: time.Now().Add(-1 * time.Minute),
+					},
+				},
+			},
+			owner:    nil,
+			expected: false,
+		},
+		{
+			title: "Owner is replenishing",
+			refresher: &operatorv1alpha1.AWSNodeRefresher{
+				Status: operatorv1alpha1.AWSNodeRefresherStatus{
+					Phase: operatorv1alpha1.AWSNodeRefresherScheduled,
+					NextUpdateTime: &metav1.Time{
+						Time: time.Now().Add(-1 * time.Minute),
+					},
+				},
+			},
+			owner: &operatorv1alpha1.AWSNodeManager{
+				Status: operatorv1alpha1.AWSNodeManagerStatus{
+					Phase: operatorv1alpha1.AWSNodeManagerReplenishing,
+				},
+			},
+			expected: false,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		now := metav1.Now()
+		result := shouldStartInstanceRefresh(context.Background(), c.refresher, &now, c.owner)
+		if result != c.expected {
+			t.Errorf("CASE: %s : expected %v, but returned %v", c.title, c.expected, result)
+		}
+	}
+}
+
+func TestSyncInstanceRefresh(t *testing.T) {
+	cases := []struct {
+		title           string
+		status          string
+		percentage      int64
+		expectedPhase   operatorv1alpha1.AWSNodeRefresherPhase
+		expectedStarted bool
+	}{
+		{
+			title:           "Instance refresh is in progress",
+			status:          autoscaling.InstanceRefreshStatusInProgress,
+			percentage:      50,
+			expectedPhase:   operatorv1alpha1.AWSNodeRefresherInstanceRefreshing,
+			expectedStarted: true,
+		},
+		{
+			title:           "Instance refresh succeeded",
+			status:          autoscaling.InstanceRefreshStatusSuccessful,
+			percentage:      100,
+			expectedPhase:   operatorv1alpha1.AWSNodeRefresherCompleted,
+			expectedStarted: false,
+		},
+		{
+			title:           "Instance refresh was cancelled",
+			status:          autoscaling.InstanceRefreshStatusCancelled,
+			percentage:      50,
+			expectedPhase:   operatorv1alpha1.AWSNodeRefresherFailed,
+			expectedStarted: false,
+		},
+		{
+			title:           "Instance refresh was rolled back",
+			status:          autoscaling.InstanceRefreshStatusRollbackSuccessful,
+			percentage:      50,
+			expectedPhase:   operatorv1alpha1.AWSNodeRefresherFailed,
+			expectedStarted: false,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		ctx := context.Background()
+		refresher := &operatorv1alpha1.AWSNodeRefresher{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-refresher",
+			},
+			Spec: operatorv1alpha1.AWSNodeRefresherSpec{
+				Region:            "us-east-1",
+				AutoScalingGroups: []operatorv1alpha1.AutoScalingGroup{{Name: "autoscaling-group"}},
+				Strategy:          operatorv1alpha1.RefreshStrategyInstanceRefresh,
+			},
+			Status: operatorv1alpha1.AWSNodeRefresherStatus{
+				Phase: operatorv1alpha1.AWSNodeRefresherInstanceRefreshing,
+				UpdateStartTime: &metav1.Time{
+					Time: time.Now().Add(-10 * time.Minute),
+				},
+				InstanceRefreshes: []operatorv1alpha1.InstanceRefreshStatus{
+					{
+						AutoScalingGroupName: "autoscaling-group",
+						InstanceRefreshID:    "refresh-1",
+					},
+				},
+			},
+		}
+		mockedASG := &mockedASGAPI{
+			DescribeInstanceRefreshesOutput: &autoscaling.DescribeInstanceRefreshesOutput{
+				InstanceRefreshes: []*autoscaling.InstanceRefresh{
+					{
+						AutoScalingGroupName: aws.String("autoscaling-group"),
+						InstanceRefreshId:    aws.String("refresh-1"),
+						Status:               aws.String(c.status),
+						PercentageComplete:   aws.Int64(c.percentage),
+					},
+				},
+			},
+		}
+		r := &AWSNodeRefresherReconciler{
+			cloud: &cloudaws.AWS{
+				Autoscaling: mockedASG,
+			},
+			Client:   &mockedClient{},
+			Recorder: &mockedRecorder{},
+		}
+
+		if err := r.syncInstanceRefresh(ctx, refresher); err != nil {
+			t.Errorf("CASE: %s : %v", c.title, err)
+			continue
+		}
+		if refresher.Status.Phase != c.expectedPhase {
+			t.Errorf("CASE: %s : phase is not matched, expected %s, but returned %s", c.title, c.expectedPhase, refresher.Status.Phase)
+		}
+		if (refresher.Status.UpdateStartTime != nil) != c.expectedStarted {
+			t.Errorf("CASE: %s : updateStartTime is not expected: %v", c.title, refresher.Status.UpdateStartTime)
+		}
+		if failed := c.expectedPhase == operatorv1alpha1.AWSNodeRefresherFailed; failed != (refresher.Status.FailureReason != "") {
+			t.Errorf("CASE: %s : failure reason is not expected: %q", c.title, refresher.Status.FailureReason)
+		}
+		s := refresher.Status.InstanceRefreshes[0]
+		if s.Status != c.status || s.PercentageComplete != c.percentage {
+			t.Errorf("CASE: %s : instance refresh status is not reflected: %+v", c.title, s)
+		}
+	}
+}
+
+func TestDrainTerminatingNodes(t *testing.T) {
+	ctx := context.Background()
+	refresher := &operatorv1alpha1.AWSNodeRefresher{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-refresher",
+		},
+		Spec: operatorv1alpha1.AWSNodeRefresherSpec{
+			Region:                  "us-east-1",
+			AutoScalingGroups:       []operatorv1alpha1.AutoScalingGroup{{Name: "autoscaling-group"}},
+			Strategy:                operatorv1alpha1.RefreshStrategyInstanceRefresh,
+			DrainGracePeriodSeconds: 600,
+		},
+		Status: operatorv1alpha1.AWSNodeRefresherStatus{
+			Phase: operatorv1alpha1.AWSNodeRefresherInstanceRefreshing,
+			AWSNodes: []operatorv1alpha1.AWSNode{
+				{Name: "node-1", InstanceID: "instance-1"},
+				{Name: "node-2", InstanceID: "instance-2"},
+			},
+			InstanceRefreshes: []operatorv1alpha1.InstanceRefreshStatus{
+				{
+					AutoScalingGroupName: "autoscaling-group",
+					InstanceRefreshID:    "refresh-1",
+				},
+			},
+		},
+	}
+	mockedASG := &mockedASGAPI{
+		DescribeAutoScalingGroupsOutput: &autoscaling.DescribeAutoScalingGroupsOutput{
+			AutoScalingGroups: []*autoscaling.Group{
+				{
+					AutoScalingGroupName: aws.String("autoscaling-group"),
+					Instances: []*autoscaling.Instance{
+						{InstanceId: aws.String("instance-1"), LifecycleState: aws.String(autoscaling.LifecycleStateTerminatingWait)},
+						{InstanceId: aws.String("instance-2"), LifecycleState: aws.String(autoscaling.LifecycleStateTerminatingWait)},
+					},
+				},
+			},
+		},
+	}
+	// Both nodes run a pod of the same PodDisruptionBudget.
+	var pods []corev1.Pod
+	for _, node := range []string{"node-1", "node-2"} {
+		pods = append(pods, corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "web-" + node,
+				Namespace: "default",
+				Labels:    map[string]string{"app": "web"},
+			},
+			Spec: corev1.PodSpec{
+				NodeName: node,
+			},
+		})
+	}
+	pdbs := []policyv1.PodDisruptionBudget{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "web",
+				Namespace: "default",
+			},
+			Spec: policyv1.PodDisruptionBudgetSpec{
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": "web"},
+				},
+			},
+		},
+	}
+	cloud := &cloudaws.AWS{
+		Autoscaling: mockedASG,
+	}
+	r := &AWSNodeRefresherReconciler{
+		cloud: cloud,
+		Client: &mockedClient{
+			getFunc: func(obj client.Object) error {
+				return nil
+			},
+			listFunc: func(listObj client.ObjectList) error {
+				switch l := listObj.(type) {
+				case *corev1.PodList:
+					l.Items = pods
+				case *policyv1.PodDisruptionBudgetList:
+					l.Items = pdbs
+				}
+				return nil
+			},
+			evictFunc: func(obj client.Object) error {
+				return apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 10)
+			},
+		},
+		Recorder: &mockedRecorder{},
+	}
+
+	if err := r.drainTerminatingNodes(ctx, refresher, cloud, "node-manager-drain"); err != nil {
+		t.Fatal(err)
+	}
+	if len(refresher.Status.DrainingNodes) != 2 {
+		t.Errorf("both nodes should be draining, but returned %v", refresher.Status.DrainingNodes)
+	}
+	expected := []string{"default/web"}
+	if !reflect.DeepEqual(refresher.Status.BlockingPodDisruptionBudgets, expected) {
+		t.Errorf("PodDisruptionBudgets are not matched, expected %v, but returned %v", expected, refresher.Status.BlockingPodDisruptionBudgets)
+	}
+}
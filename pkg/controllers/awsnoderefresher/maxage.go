@@ -0,0 +1,29 @@
+package awsnoderefresher
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+)
+
+// expiredNodes returns nodes which are older than maxNodeAge at now.
+func expiredNodes(refresher *operatorv1alpha1.AWSNodeRefresher, now *metav1.Time) []operatorv1alpha1.AWSNode {
+	var expired []operatorv1alpha1.AWSNode
+	for _, node := range refresher.Status.AWSNodes {
+		if node.CreationTimestamp.Add(refresher.Spec.MaxNodeAge.Duration).Before(now.Time) {
+			expired = append(expired, node)
+		}
+	}
+	return expired
+}
+
+// maxNodeAgeMessage describes when the next refresh starts with maxNodeAge.
+func maxNodeAgeMessage(refresher *operatorv1alpha1.AWSNodeRefresher, now *metav1.Time) string {
+	window := ""
+	if refresher.Status.NextUpdateTime != nil {
+		window = fmt.Sprintf(" in the window at %s", refresher.Status.NextUpdateTime.Format(metav1.RFC3339Micro))
+	}
+	return fmt.Sprintf("Next refresh starts%s when nodes are older than %s, %d nodes are expired now", window, refresher.Spec.MaxNodeAge.Duration, len(expiredNodes(refresher, now)))
+}
@@ -0,0 +1,114 @@
+package awsnoderefresher
+
+import (
+	"log"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+)
+
+func TestRefreshDueWithMaxNodeAge(t *testing.T) {
+	now := metav1.Now()
+	nodes := []operatorv1alpha1.AWSNode{
+		{
+			Name:              "worker-1",
+			InstanceID:        "instanceId-1",
+			CreationTimestamp: metav1.NewTime(now.Add(-200 * time.Hour)),
+		},
+		{
+			Name:              "worker-2",
+			InstanceID:        "instanceId-2",
+			CreationTimestamp: metav1.NewTime(now.Add(-1 * time.Hour)),
+		},
+	}
+	cases := []struct {
+		title          string
+		maxNodeAge     time.Duration
+		nextUpdateTime *metav1.Time
+		expected       bool
+		candidates     []string
+	}{
+		{
+			title:          "No nodes are expired",
+			maxNodeAge:     300 * time.Hour,
+			nextUpdateTime: nil,
+			expected:       false,
+			candidates:     nil,
+		},
+		{
+			title:          "Nodes are expired without a window",
+			maxNodeAge:     168 * time.Hour,
+			nextUpdateTime: nil,
+			expected:       true,
+			candidates:     []string{"worker-1"},
+		},
+		{
+			title:          "Nodes are expired before the window",
+			maxNodeAge:     168 * time.Hour,
+			nextUpdateTime: &metav1.Time{Time: now.Add(1 * time.Hour)},
+			expected:       false,
+			candidates:     []string{"worker-1"},
+		},
+		{
+			title:          "Nodes are expired in the window",
+			maxNodeAge:     30 * time.Minute,
+			nextUpdateTime: &metav1.Time{Time: now.Add(-1 * time.Minute)},
+			expected:       true,
+			candidates:     []string{"worker-1", "worker-2"},
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		refresher := &operatorv1alpha1.AWSNodeRefresher{
+			Spec: operatorv1alpha1.AWSNodeRefresherSpec{
+				MaxNodeAge: &metav1.Duration{Duration: c.maxNodeAge},
+			},
+			Status: operatorv1alpha1.AWSNodeRefresherStatus{
+				AWSNodes:       nodes,
+				NextUpdateTime: c.nextUpdateTime,
+			},
+		}
+		result := refreshDue(refresher, &now)
+		if result != c.expected {
+			t.Errorf("CASE: %s : expected %v, but returned %v", c.title, c.expected, result)
+		}
+		var candidates []string
+		for _, node := range replaceCandidates(refresher, &now) {
+			candidates = append(candidates, node.Name)
+		}
+		if len(candidates) != len(c.candidates) {
+			t.Errorf("CASE: %s : candidates are not matched, expected %v, but returned %v", c.title, c.candidates, candidates)
+		}
+	}
+}
+
+func TestWindowPassed(t *testing.T) {
+	now := metav1.Now()
+	refresher := &operatorv1alpha1.AWSNodeRefresher{
+		Spec: operatorv1alpha1.AWSNodeRefresherSpec{
+			Schedule:   "0 3 * * *",
+			MaxNodeAge: &metav1.Duration{Duration: 168 * time.Hour},
+		},
+		Status: operatorv1alpha1.AWSNodeRefresherStatus{
+			AWSNodes: []operatorv1alpha1.AWSNode{
+				{
+					Name:              "worker-1",
+					CreationTimestamp: metav1.NewTime(now.Add(-1 * time.Hour)),
+				},
+			},
+			NextUpdateTime: &metav1.Time{Time: now.Add(-1 * time.Minute)},
+		},
+	}
+	if !windowPassed(refresher, &now) {
+		t.Errorf("window should pass when no nodes are expired")
+	}
+
+	refresher.Spec.MaxNodeAge = nil
+	if windowPassed(refresher, &now) {
+		t.Errorf("window should not pass with a plain schedule")
+	}
+}
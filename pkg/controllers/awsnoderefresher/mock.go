@@ -8,6 +8,8 @@ import (
 	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
@@ -18,6 +20,7 @@ type mockedASGAPI struct {
 	autoscalingiface.AutoScalingAPI
 	DescribeAutoScalingGroupsOutput *autoscaling.DescribeAutoScalingGroupsOutput
 	UpdateAutoScalingGroupOutput    *autoscaling.UpdateAutoScalingGroupOutput
+	DescribeInstanceRefreshesOutput *autoscaling.DescribeInstanceRefreshesOutput
 }
 
 func (m *mockedASGAPI) DescribeAutoScalingGroups(in *autoscaling.DescribeAutoScalingGroupsInput) (*autoscaling.DescribeAutoScalingGroupsOutput, error) {
@@ -28,11 +31,21 @@ func (m *mockedASGAPI) UpdateAutoScalingGroup(in *autoscaling.UpdateAutoScalingG
 	return m.UpdateAutoScalingGroupOutput, nil
 }
 
+func (m *mockedASGAPI) DescribeInstanceRefreshes(in *autoscaling.DescribeInstanceRefreshesInput) (*autoscaling.DescribeInstanceRefreshesOutput, error) {
+	return m.DescribeInstanceRefreshesOutput, nil
+}
+
 type mockedEC2API struct {
 	ec2iface.EC2API
 	DescribeInstancesResp  *ec2.DescribeInstancesOutput
 	TerminateInstancesResp *ec2.TerminateInstancesOutput
 	terminateInstanceID    *string
+	// DescribeLaunchTemplateVersionsResp is used to detect drift.
+	DescribeLaunchTemplateVersionsResp *ec2.DescribeLaunchTemplateVersionsOutput
+}
+
+func (m *mockedEC2API) DescribeLaunchTemplateVersions(in *ec2.DescribeLaunchTemplateVersionsInput) (*ec2.DescribeLaunchTemplateVersionsOutput, error) {
+	return m.DescribeLaunchTemplateVersionsResp, nil
 }
 
 func (m *mockedEC2API) DescribeInstances(in *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error) {
@@ -48,10 +61,25 @@ func (m *mockedEC2API) TerminateInstances(in *ec2.TerminateInstancesInput) (*ec2
 	return m.TerminateInstancesResp, nil
 }
 
+type mockedSSMAPI struct {
+	ssmiface.SSMAPI
+	Value string
+}
+
+func (m *mockedSSMAPI) GetParameter(in *ssm.GetParameterInput) (*ssm.GetParameterOutput, error) {
+	return &ssm.GetParameterOutput{
+		Parameter: &ssm.Parameter{
+			Name:  in.Name,
+			Value: &m.Value,
+		},
+	}, nil
+}
+
 type mockedClient struct {
 	client.Client
-	getFunc  func(obj client.Object) error
-	listFunc func(listObj client.ObjectList) error
+	getFunc   func(obj client.Object) error
+	listFunc  func(listObj client.ObjectList) error
+	evictFunc func(obj client.Object) error
 }
 
 func (m *mockedClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
@@ -66,12 +94,31 @@ func (m *mockedClient) List(ctx context.Context, list client.ObjectList, opts ..
 	return m.listFunc(list)
 }
 
+func (m *mockedClient) SubResource(subResource string) client.SubResourceClient {
+	return &mockedSubResourceClient{
+		createFunc: m.evictFunc,
+	}
+}
+
+type mockedSubResourceClient struct {
+	client.SubResourceClient
+	createFunc func(obj client.Object) error
+}
+
+func (m *mockedSubResourceClient) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	return m.createFunc(obj)
+}
+
 type mockedRecorder struct {
 	record.EventRecorder
+	// reasons of recorded events
+	reasons []string
 }
 
 func (m *mockedRecorder) Event(object runtime.Object, eventtype, reason, messageFmt string) {
+	m.reasons = append(m.reasons, reason)
 }
 
 func (m *mockedRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	m.reasons = append(m.reasons, reason)
 }
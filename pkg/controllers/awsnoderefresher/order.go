@@ -0,0 +1,142 @@
+package awsnoderefresher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+	"github.com/h3poteto/node-manager/pkg/cloud"
+	"github.com/h3poteto/node-manager/pkg/util/klog"
+)
+
+// selectDeleteTargets chooses nodes to replace in the batch among the candidates in spec.replacementOrder.
+func (r *AWSNodeRefresherReconciler) selectDeleteTargets(ctx context.Context, refresher *operatorv1alpha1.AWSNodeRefresher, candidates []operatorv1alpha1.AWSNode) ([]operatorv1alpha1.AWSNode, error) {
+	size := batchSize(refresher)
+	switch refresher.Spec.ReplacementOrder {
+	case operatorv1alpha1.ReplacementOrderAZBalanced:
+		return findAZBalancedTargets(refresher.Status.AWSNodes, candidates, size)
+	case operatorv1alpha1.ReplacementOrderLeastPods:
+		pods, err := r.podCounts(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return findLeastPodsTargets(candidates, pods, size)
+	case operatorv1alpha1.ReplacementOrderInstanceTypeMismatch:
+		resolver, ok := r.cloud.(cloud.InstanceTypeResolver)
+		if !ok {
+			return nil, fmt.Errorf("cloud provider %s does not support %s replacement order", refresher.Spec.CloudProvider, operatorv1alpha1.ReplacementOrderInstanceTypeMismatch)
+		}
+		types, err := resolver.LaunchInstanceTypes(refresher.Spec.AutoScalingGroups)
+		if err != nil {
+			return nil, err
+		}
+		return findInstanceTypeMismatchTargets(candidates, types, size)
+	default:
+		return findDeleteTargets(candidates, size)
+	}
+}
+
+// findAZBalancedTargets picks the oldest candidate in the availability zone which has the most nodes, one by one.
+// Zones are counted with all nodes including new ones, so the replacements keep zones balanced.
+func findAZBalancedTargets(nodes []operatorv1alpha1.AWSNode, candidates []operatorv1alpha1.AWSNode, size int) ([]operatorv1alpha1.AWSNode, error) {
+	if len(candidates) == 0 {
+		return nil, errors.New("No nodes are running")
+	}
+	zones := map[string]int{}
+	for _, node := range nodes {
+		zones[node.AvailabilityZone]++
+	}
+	remaining := oldestFirst(candidates)
+	var targets []operatorv1alpha1.AWSNode
+	for len(targets) < size && len(remaining) > 0 {
+		picked := 0
+		for i := 1; i < len(remaining); i++ {
+			zone, pickedZone := remaining[i].AvailabilityZone, remaining[picked].AvailabilityZone
+			if zones[zone] > zones[pickedZone] || (zones[zone] == zones[pickedZone] && zone < pickedZone) {
+				picked = i
+			}
+		}
+		target := remaining[picked]
+		targets = append(targets, target)
+		zones[target.AvailabilityZone]--
+		remaining = append(remaining[:picked], remaining[picked+1:]...)
+	}
+	return targets, nil
+}
+
+// findLeastPodsTargets picks candidates which run the fewest pods, and the oldest one among the same count.
+func findLeastPodsTargets(candidates []operatorv1alpha1.AWSNode, pods map[string]int, size int) ([]operatorv1alpha1.AWSNode, error) {
+	if len(candidates) == 0 {
+		return nil, errors.New("No nodes are running")
+	}
+	sorted := oldestFirst(candidates)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return pods[sorted[i].Name] < pods[sorted[j].Name]
+	})
+	return firstNodes(sorted, size), nil
+}
+
+// findInstanceTypeMismatchTargets picks candidates whose instance type is not launched by their AutoScalingGroups first, then the oldest ones.
+// Nodes in AutoScalingGroups whose types are not known are not regarded as mismatched.
+func findInstanceTypeMismatchTargets(candidates []operatorv1alpha1.AWSNode, types map[string][]string, size int) ([]operatorv1alpha1.AWSNode, error) {
+	if len(candidates) == 0 {
+		return nil, errors.New("No nodes are running")
+	}
+	sorted := oldestFirst(candidates)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return instanceTypeMismatched(sorted[i], types) && !instanceTypeMismatched(sorted[j], types)
+	})
+	return firstNodes(sorted, size), nil
+}
+
+func instanceTypeMismatched(node operatorv1alpha1.AWSNode, types map[string][]string) bool {
+	launched, ok := types[node.AutoScalingGroupName]
+	if !ok {
+		return false
+	}
+	for _, t := range launched {
+		if t == node.InstanceType {
+			return false
+		}
+	}
+	return true
+}
+
+// podCounts returns the number of pods which are evicted by drain on each node.
+func (r *AWSNodeRefresherReconciler) podCounts(ctx context.Context) (map[string]int, error) {
+	var podList corev1.PodList
+	if err := r.Client.List(ctx, &podList); err != nil {
+		klog.Errorf(ctx, "Failed to list pods: %v", err)
+		return nil, err
+	}
+	counts := map[string]int{}
+	for i := range podList.Items {
+		pod := podList.Items[i]
+		if pod.Spec.NodeName == "" || podIsDaemonSet(pod) || podIsStaticPod(pod) {
+			continue
+		}
+		counts[pod.Spec.NodeName]++
+	}
+	return counts, nil
+}
+
+// oldestFirst returns a copy of the nodes sorted by creation timestamp.
+func oldestFirst(nodes []operatorv1alpha1.AWSNode) []operatorv1alpha1.AWSNode {
+	sorted := make([]operatorv1alpha1.AWSNode, len(nodes))
+	copy(sorted, nodes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreationTimestamp.Before(&sorted[j].CreationTimestamp)
+	})
+	return sorted
+}
+
+func firstNodes(nodes []operatorv1alpha1.AWSNode, size int) []operatorv1alpha1.AWSNode {
+	if len(nodes) > size {
+		return nodes[:size]
+	}
+	return nodes
+}
@@ -0,0 +1,215 @@
+package awsnoderefresher
+
+import (
+	"context"
+	"log"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+)
+
+func newOrderTestNode(name, zone, instanceType string, age time.Duration) operatorv1alpha1.AWSNode {
+	return operatorv1alpha1.AWSNode{
+		Name:                 name,
+		InstanceID:           "i-" + name,
+		AvailabilityZone:     zone,
+		InstanceType:         instanceType,
+		AutoScalingGroupName: "asg-1",
+		CreationTimestamp:    metav1.Time{Time: time.Now().Add(-age)},
+	}
+}
+
+func TestSelectDeleteTargets(t *testing.T) {
+	nodes := []operatorv1alpha1.AWSNode{
+		newOrderTestNode("node-a1", "us-east-1a", "m5.large", 5*time.Hour),
+		newOrderTestNode("node-b1", "us-east-1b", "m5.large", 4*time.Hour),
+		newOrderTestNode("node-b2", "us-east-1b", "m4.large", 3*time.Hour),
+		newOrderTestNode("node-b3", "us-east-1b", "m5.large", 2*time.Hour),
+		newOrderTestNode("node-c1", "us-east-1c", "m4.large", 1*time.Hour),
+	}
+	pods := []corev1.Pod{
+		{Spec: corev1.PodSpec{NodeName: "node-a1"}},
+		{Spec: corev1.PodSpec{NodeName: "node-a1"}},
+		{Spec: corev1.PodSpec{NodeName: "node-b1"}},
+		{Spec: corev1.PodSpec{NodeName: "node-b2"}},
+		{Spec: corev1.PodSpec{NodeName: "node-b3"}},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				OwnerReferences: []metav1.OwnerReference{{Kind: "DaemonSet"}},
+			},
+			Spec: corev1.PodSpec{NodeName: "node-c1"},
+		},
+	}
+	surge := intstr.FromInt32(2)
+	cases := []struct {
+		title    string
+		order    operatorv1alpha1.ReplacementOrder
+		expected []string
+	}{
+		{
+			title:    "Empty order is oldest first",
+			order:    "",
+			expected: []string{"node-a1", "node-b1"},
+		},
+		{
+			title:    "Oldest first",
+			order:    operatorv1alpha1.ReplacementOrderOldestFirst,
+			expected: []string{"node-a1", "node-b1"},
+		},
+		{
+			title:    "AZ balanced picks from the zone which has the most nodes",
+			order:    operatorv1alpha1.ReplacementOrderAZBalanced,
+			expected: []string{"node-b1", "node-b2"},
+		},
+		{
+			title:    "Least pods ignores DaemonSet pods",
+			order:    operatorv1alpha1.ReplacementOrderLeastPods,
+			expected: []string{"node-c1", "node-b1"},
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		refresher := &operatorv1alpha1.AWSNodeRefresher{
+			Spec: operatorv1alpha1.AWSNodeRefresherSpec{
+				Desired:          3,
+				MaxSurge:         &surge,
+				ReplacementOrder: c.order,
+			},
+			Status: operatorv1alpha1.AWSNodeRefresherStatus{
+				AWSNodes: nodes,
+			},
+		}
+		r := &AWSNodeRefresherReconciler{
+			Client: &mockedClient{
+				listFunc: func(listObj client.ObjectList) error {
+					listObj.(*corev1.PodList).Items = pods
+					return nil
+				},
+			},
+			Recorder: &mockedRecorder{},
+		}
+		targets, err := r.selectDeleteTargets(context.Background(), refresher, nodes)
+		if err != nil {
+			t.Errorf("CASE: %s : %v", c.title, err)
+			continue
+		}
+		if names := nodeNames(targets); !reflect.DeepEqual(names, c.expected) {
+			t.Errorf("CASE: %s : targets are not matched, expected %v, but returned %v", c.title, c.expected, names)
+		}
+	}
+}
+
+func TestFindAZBalancedTargets(t *testing.T) {
+	nodes := []operatorv1alpha1.AWSNode{
+		newOrderTestNode("node-a1", "us-east-1a", "m5.large", 6*time.Hour),
+		newOrderTestNode("node-a2", "us-east-1a", "m5.large", 5*time.Hour),
+		newOrderTestNode("node-b1", "us-east-1b", "m5.large", 4*time.Hour),
+		newOrderTestNode("node-b2", "us-east-1b", "m5.large", 3*time.Hour),
+		newOrderTestNode("node-b3", "us-east-1b", "m5.large", 2*time.Hour),
+		newOrderTestNode("node-c1", "us-east-1c", "m5.large", 1*time.Hour),
+	}
+	cases := []struct {
+		title      string
+		candidates []operatorv1alpha1.AWSNode
+		size       int
+		expected   []string
+	}{
+		{
+			title:      "One node from the largest zone",
+			candidates: nodes,
+			size:       1,
+			expected:   []string{"node-b1"},
+		},
+		{
+			title:      "Zones are counted again after each pick",
+			candidates: nodes,
+			size:       3,
+			expected:   []string{"node-b1", "node-a1", "node-b2"},
+		},
+		{
+			title:      "Largest zone without candidates",
+			candidates: []operatorv1alpha1.AWSNode{nodes[0], nodes[5]},
+			size:       1,
+			expected:   []string{"node-a1"},
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		targets, err := findAZBalancedTargets(nodes, c.candidates, c.size)
+		if err != nil {
+			t.Errorf("CASE: %s : %v", c.title, err)
+			continue
+		}
+		if names := nodeNames(targets); !reflect.DeepEqual(names, c.expected) {
+			t.Errorf("CASE: %s : targets are not matched, expected %v, but returned %v", c.title, c.expected, names)
+		}
+	}
+}
+
+func TestFindLeastPodsTargets(t *testing.T) {
+	nodes := []operatorv1alpha1.AWSNode{
+		newOrderTestNode("node-1", "us-east-1a", "m5.large", 3*time.Hour),
+		newOrderTestNode("node-2", "us-east-1a", "m5.large", 2*time.Hour),
+		newOrderTestNode("node-3", "us-east-1a", "m5.large", 1*time.Hour),
+	}
+	pods := map[string]int{"node-1": 5, "node-2": 1, "node-3": 1}
+
+	targets, err := findLeastPodsTargets(nodes, pods, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"node-2", "node-3"}
+	if names := nodeNames(targets); !reflect.DeepEqual(names, expected) {
+		t.Errorf("targets are not matched, expected %v, but returned %v", expected, names)
+	}
+}
+
+func TestFindInstanceTypeMismatchTargets(t *testing.T) {
+	nodes := []operatorv1alpha1.AWSNode{
+		newOrderTestNode("node-1", "us-east-1a", "m5.large", 3*time.Hour),
+		newOrderTestNode("node-2", "us-east-1a", "m4.large", 2*time.Hour),
+		newOrderTestNode("node-3", "us-east-1a", "m5.large", 1*time.Hour),
+	}
+	cases := []struct {
+		title    string
+		types    map[string][]string
+		expected []string
+	}{
+		{
+			title:    "Mismatched node first",
+			types:    map[string][]string{"asg-1": {"m5.large"}},
+			expected: []string{"node-2", "node-1"},
+		},
+		{
+			title:    "Any of the types of mixed instances policy matches",
+			types:    map[string][]string{"asg-1": {"m5.large", "m4.large"}},
+			expected: []string{"node-1", "node-2"},
+		},
+		{
+			title:    "Types are not known",
+			types:    map[string][]string{},
+			expected: []string{"node-1", "node-2"},
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		targets, err := findInstanceTypeMismatchTargets(nodes, c.types, 2)
+		if err != nil {
+			t.Errorf("CASE: %s : %v", c.title, err)
+			continue
+		}
+		if names := nodeNames(targets); !reflect.DeepEqual(names, c.expected) {
+			t.Errorf("CASE: %s : targets are not matched, expected %v, but returned %v", c.title, c.expected, names)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package awsnoderefresher
+
+import (
+	"context"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+)
+
+// refreshRequested returns the value of the refresh-now annotation when it has not been handled yet.
+func refreshRequested(refresher *operatorv1alpha1.AWSNodeRefresher) (string, bool) {
+	value := refresher.GetAnnotations()[operatorv1alpha1.RefreshNowAnnotation]
+	return value, value != "" && value != refresher.Status.LastRefreshNow
+}
+
+// acknowledgeRefresh marks the refresh-now annotation and the last seen AMI as handled when a refresh starts.
+func acknowledgeRefresh(refresher *operatorv1alpha1.AWSNodeRefresher) {
+	value, requested := refreshRequested(refresher)
+	refresher.Status.OnDemand = requested
+	refresher.Status.FailureReason = ""
+	refresher.Status.SurplusRolledBack = false
+	if requested {
+		refresher.Status.LastRefreshNow = value
+	}
+	if status := refresher.Status.AMISource; status != nil {
+		status.RefreshedValue = status.LastSeenValue
+	}
+}
+
+// startRequestedRefresh starts the refresh which is requested by the refresh-now annotation in completed or failed phase.
+// It is started as scheduled right away, so it does not wait for another reconcile after the next schedule is recorded.
+func (r *AWSNodeRefresherReconciler) startRequestedRefresh(ctx context.Context, refresher *operatorv1alpha1.AWSNodeRefresher) error {
+	refresher.Status.Phase = operatorv1alpha1.AWSNodeRefresherScheduled
+	if refresher.Spec.Strategy == operatorv1alpha1.RefreshStrategyInstanceRefresh {
+		return r.startInstanceRefresh(ctx, refresher)
+	}
+	return r.refreshIncrease(ctx, refresher)
+}
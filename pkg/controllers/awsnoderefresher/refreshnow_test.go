@@ -0,0 +1,268 @@
+package awsnoderefresher
+
+import (
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+)
+
+func TestRefreshDueWithRefreshNow(t *testing.T) {
+	cases := []struct {
+		title          string
+		annotation     string
+		lastRefreshNow string
+		expected       bool
+	}{
+		{
+			title:          "Annotation is not specified",
+			annotation:     "",
+			lastRefreshNow: "",
+			expected:       false,
+		},
+		{
+			title:          "Annotation is new",
+			annotation:     "2026-10-17T10:00:00Z",
+			lastRefreshNow: "",
+			expected:       true,
+		},
+		{
+			title:          "Annotation is handled",
+			annotation:     "2026-10-17T10:00:00Z",
+			lastRefreshNow: "2026-10-17T10:00:00Z",
+			expected:       false,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		refresher := &operatorv1alpha1.AWSNodeRefresher{
+			Spec: operatorv1alpha1.AWSNodeRefresherSpec{
+				// The window is closed, and the schedule has not come.
+				MaintenanceWindow: &operatorv1alpha1.MaintenanceWindow{
+					Schedule: "0 0 1 1 *",
+					Duration: metav1.Duration{Duration: time.Minute},
+				},
+			},
+			Status: operatorv1alpha1.AWSNodeRefresherStatus{
+				NextUpdateTime: &metav1.Time{Time: time.Now().Add(24 * time.Hour)},
+				LastRefreshNow: c.lastRefreshNow,
+			},
+		}
+		if c.annotation != "" {
+			metav1.SetMetaDataAnnotation(&refresher.ObjectMeta, operatorv1alpha1.RefreshNowAnnotation, c.annotation)
+		}
+		now := metav1.Now()
+		result := refreshDue(refresher, &now)
+		if result != c.expected {
+			t.Errorf("CASE: %s : expected %v, but returned %v", c.title, c.expected, result)
+		}
+	}
+}
+
+func TestAcknowledgeRefresh(t *testing.T) {
+	refresher := &operatorv1alpha1.AWSNodeRefresher{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				operatorv1alpha1.RefreshNowAnnotation: "2026-10-17T10:00:00Z",
+			},
+		},
+		Spec: operatorv1alpha1.AWSNodeRefresherSpec{
+			Trigger: operatorv1alpha1.RefreshTriggerDrift,
+		},
+		Status: operatorv1alpha1.AWSNodeRefresherStatus{
+			AWSNodes: []operatorv1alpha1.AWSNode{
+				{
+					Name:       "worker-1",
+					InstanceID: "instanceId-1",
+				},
+				{
+					Name:       "worker-2",
+					InstanceID: "instanceId-2",
+				},
+			},
+		},
+	}
+	now := metav1.Now()
+
+	acknowledgeRefresh(refresher)
+	if refresher.Status.LastRefreshNow != "2026-10-17T10:00:00Z" || !refresher.Status.OnDemand {
+		t.Errorf("refresh-now is not handled: %s, %v", refresher.Status.LastRefreshNow, refresher.Status.OnDemand)
+	}
+	if refreshDue(refresher, &now) {
+		t.Errorf("handled refresh-now should not start a refresh again")
+	}
+	if candidates := replaceCandidates(refresher, &now); len(candidates) != 2 {
+		t.Errorf("on-demand refresh should replace all nodes, but returned %v", candidates)
+	}
+
+	acknowledgeRefresh(refresher)
+	if refresher.Status.OnDemand {
+		t.Errorf("refresh should not be on-demand without a new refresh-now")
+	}
+}
+
+func TestSuspend(t *testing.T) {
+	ctx := context.Background()
+	cases := []struct {
+		title    string
+		phase    operatorv1alpha1.AWSNodeRefresherPhase
+		suspend  bool
+		expected operatorv1alpha1.AWSNodeRefresherPhase
+	}{
+		{
+			title:    "Suspended refresher does not start a refresh",
+			phase:    operatorv1alpha1.AWSNodeRefresherScheduled,
+			suspend:  true,
+			expected: operatorv1alpha1.AWSNodeRefresherScheduled,
+		},
+		{
+			title:    "Suspended refresher keeps pausing",
+			phase:    operatorv1alpha1.AWSNodeRefresherPaused,
+			suspend:  true,
+			expected: operatorv1alpha1.AWSNodeRefresherPaused,
+		},
+		{
+			title:    "Resumed refresher replaces next node",
+			phase:    operatorv1alpha1.AWSNodeRefresherPaused,
+			suspend:  false,
+			expected: operatorv1alpha1.AWSNodeRefresherUpdateIncreasing,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		refresher := &operatorv1alpha1.AWSNodeRefresher{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-refresher",
+				Annotations: map[string]string{
+					operatorv1alpha1.RefreshNowAnnotation: "2026-10-17T10:00:00Z",
+				},
+			},
+			Spec: operatorv1alpha1.AWSNodeRefresherSpec{
+				Schedule: "0 3 * * *",
+				Suspend:  c.suspend,
+			},
+			Status: operatorv1alpha1.AWSNodeRefresherStatus{
+				NextUpdateTime: &metav1.Time{Time: time.Now().Add(-1 * time.Minute)},
+				Phase:          c.phase,
+			},
+		}
+		r := &AWSNodeRefresherReconciler{
+			Client:   &mockedClient{},
+			Recorder: &mockedRecorder{},
+		}
+		if err := r.syncRefresher(ctx, refresher); err != nil {
+			t.Errorf("CASE: %s : %v", c.title, err)
+			continue
+		}
+		if refresher.Status.Phase != c.expected {
+			t.Errorf("CASE: %s : phase is not matched, expected %s, but returned %s", c.title, c.expected, refresher.Status.Phase)
+		}
+	}
+}
+
+func TestRefreshNowFromCompleted(t *testing.T) {
+	ctx := context.Background()
+	cases := []struct {
+		title     string
+		phase     operatorv1alpha1.AWSNodeRefresherPhase
+		requested bool
+		suspend   bool
+		// rollingBack is true when surplus instances of the failed refresh are not removed yet.
+		rollingBack bool
+		expected    operatorv1alpha1.AWSNodeRefresherPhase
+	}{
+		{
+			title:     "Completed refresher starts the requested refresh right away",
+			phase:     operatorv1alpha1.AWSNodeRefresherCompleted,
+			requested: true,
+			expected:  operatorv1alpha1.AWSNodeRefresherUpdateIncreasing,
+		},
+		{
+			title:     "Failed refresher starts the requested refresh right away",
+			phase:     operatorv1alpha1.AWSNodeRefresherFailed,
+			requested: true,
+			expected:  operatorv1alpha1.AWSNodeRefresherUpdateIncreasing,
+		},
+		{
+			title:       "Failed refresher waits for the rollback even if requested",
+			phase:       operatorv1alpha1.AWSNodeRefresherFailed,
+			requested:   true,
+			rollingBack: true,
+			expected:    operatorv1alpha1.AWSNodeRefresherFailed,
+		},
+		{
+			title:     "Completed refresher schedules next without request",
+			phase:     operatorv1alpha1.AWSNodeRefresherCompleted,
+			requested: false,
+			expected:  operatorv1alpha1.AWSNodeRefresherScheduled,
+		},
+		{
+			title:     "Suspended refresher schedules next even if requested",
+			phase:     operatorv1alpha1.AWSNodeRefresherCompleted,
+			requested: true,
+			suspend:   true,
+			expected:  operatorv1alpha1.AWSNodeRefresherScheduled,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		annotations := map[string]string{}
+		if c.requested {
+			annotations[operatorv1alpha1.RefreshNowAnnotation] = "2026-10-17T10:00:00Z"
+		}
+		refresher := &operatorv1alpha1.AWSNodeRefresher{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "test-refresher",
+				Annotations: annotations,
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(&operatorv1alpha1.AWSNodeManager{ObjectMeta: metav1.ObjectMeta{Name: "test-manager"}}, operatorv1alpha1.GroupVersion.WithKind("AWSNodeManager")),
+				},
+			},
+			Spec: operatorv1alpha1.AWSNodeRefresherSpec{
+				Schedule:     "0 3 * * *",
+				Suspend:      c.suspend,
+				SurplusNodes: 0,
+			},
+			Status: operatorv1alpha1.AWSNodeRefresherStatus{
+				Phase:             c.phase,
+				SurplusRolledBack: true,
+			},
+		}
+		if c.rollingBack {
+			// The cool time of the last scale down has not passed, so the rollback does not finish in this sync.
+			refresher.Spec.SurplusNodes = 1
+			refresher.Spec.ASGModifyCoolTimeSeconds = 600
+			refresher.Status.SurplusRolledBack = false
+			refresher.Status.LastASGModifiedTime = &metav1.Time{Time: time.Now()}
+		}
+		r := &AWSNodeRefresherReconciler{
+			Client: &mockedClient{
+				getFunc: func(obj client.Object) error {
+					return nil
+				},
+			},
+			Recorder: &mockedRecorder{},
+		}
+		if err := r.syncRefresher(ctx, refresher); err != nil {
+			t.Errorf("CASE: %s : %v", c.title, err)
+			continue
+		}
+		if refresher.Status.Phase != c.expected {
+			t.Errorf("CASE: %s : phase is not matched, expected %s, but returned %s", c.title, c.expected, refresher.Status.Phase)
+		}
+		if c.expected == operatorv1alpha1.AWSNodeRefresherUpdateIncreasing && refresher.Status.LastRefreshNow != "2026-10-17T10:00:00Z" {
+			t.Errorf("CASE: %s : refresh-now should be acknowledged, but returned %q", c.title, refresher.Status.LastRefreshNow)
+		}
+		if c.rollingBack && refresher.Status.LastRefreshNow != "" {
+			t.Errorf("CASE: %s : refresh-now should not be acknowledged during the rollback, but returned %q", c.title, refresher.Status.LastRefreshNow)
+		}
+	}
+}
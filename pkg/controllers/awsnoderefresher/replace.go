@@ -3,9 +3,9 @@ package awsnoderefresher
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
-	"github.com/aws/aws-sdk-go/service/ec2"
 	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
 	"github.com/h3poteto/node-manager/pkg/util/klog"
 
@@ -19,9 +19,11 @@ func (r *AWSNodeRefresherReconciler) refreshReplace(ctx context.Context, refresh
 	}
 
 	now := metav1.Now()
-	target := refresher.Status.ReplaceTargetNode
+	targets := replaceTargets(refresher)
 	refresher.Status.Phase = operatorv1alpha1.AWSNodeRefresherUpdateReplacing
 	refresher.Status.LastASGModifiedTime = &now
+	refresher.Status.PhaseStartTime = &now
+	refresher.Status.BlockingPodDisruptionBudgets = nil
 	refresher.Status.Revision += 1
 	if err := r.Client.Update(ctx, refresher); err != nil {
 		klog.Errorf(ctx, "failed to update refresher: %v", err)
@@ -29,7 +31,16 @@ func (r *AWSNodeRefresherReconciler) refreshReplace(ctx context.Context, refresh
 	}
 	r.Recorder.Event(refresher, corev1.EventTypeNormal, "Replace instance", "Replace instance in ASG for refresh")
 
-	return r.cloud.DeleteInstance(target)
+	return r.terminateInstances(targets)
+}
+
+func (r *AWSNodeRefresherReconciler) terminateInstances(targets []operatorv1alpha1.AWSNode) error {
+	for i := range targets {
+		if err := r.cloud.TerminateInstance(&targets[i]); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func shouldReplace(ctx context.Context, refresher *operatorv1alpha1.AWSNodeRefresher) bool {
@@ -37,33 +48,73 @@ func shouldReplace(ctx context.Context, refresher *operatorv1alpha1.AWSNodeRefre
 		klog.Warningf(ctx, "AWSNodeRefresher phase is not matched: %s, so should not replace", refresher.Status.Phase)
 		return false
 	}
-	if len(refresher.Status.AWSNodes) != int(refresher.Spec.Desired)+int(refresher.Spec.SurplusNodes) {
-		klog.Infof(ctx, "Node is not enough, current: %d, desired: %d + %d", len(refresher.Status.AWSNodes), refresher.Spec.Desired, refresher.Spec.SurplusNodes)
+	if len(refresher.Status.AWSNodes) != int(refresher.Spec.Desired)+surplusNodes(refresher) {
+		klog.Infof(ctx, "Node is not enough, current: %d, desired: %d + %d", len(refresher.Status.AWSNodes), refresher.Spec.Desired, surplusNodes(refresher))
 		return false
 	}
 	return true
 }
 
-func findDeleteTarget(nodes []operatorv1alpha1.AWSNode) (*operatorv1alpha1.AWSNode, error) {
-	var target *operatorv1alpha1.AWSNode
-	for i := range nodes {
-		node := &nodes[i]
-		if target == nil {
-			target = node
-			continue
-		}
-		if node.CreationTimestamp.Before(&target.CreationTimestamp) {
-			target = node
-		}
+// replaceTargets returns the nodes in the current batch.
+// The target in the deprecated replaceTargetNode is returned until it is moved to replaceTargetNodes.
+func replaceTargets(refresher *operatorv1alpha1.AWSNodeRefresher) []operatorv1alpha1.AWSNode {
+	if len(refresher.Status.ReplaceTargetNodes) == 0 && refresher.Status.ReplaceTargetNode != nil {
+		return []operatorv1alpha1.AWSNode{*refresher.Status.ReplaceTargetNode}
+	}
+	return refresher.Status.ReplaceTargetNodes
+}
+
+// migrateReplaceTarget moves the deprecated replaceTargetNode to replaceTargetNodes,
+// so a refresh which is running while the controller is upgraded keeps draining and replacing the target.
+func (r *AWSNodeRefresherReconciler) migrateReplaceTarget(ctx context.Context, refresher *operatorv1alpha1.AWSNodeRefresher) error {
+	if refresher.Status.ReplaceTargetNode == nil {
+		return nil
+	}
+	refresher.Status.ReplaceTargetNodes = replaceTargets(refresher)
+	refresher.Status.ReplaceTargetNode = nil
+	refresher.Status.Revision += 1
+	if err := r.Client.Update(ctx, refresher); err != nil {
+		klog.Errorf(ctx, "failed to update refresher: %v", err)
+		return err
 	}
-	if target == nil {
+	klog.Infof(ctx, "Move replace target %s to replaceTargetNodes", strings.Join(nodeNames(refresher.Status.ReplaceTargetNodes), ", "))
+	return nil
+}
+
+// findDeleteTargets returns the oldest nodes up to size.
+func findDeleteTargets(nodes []operatorv1alpha1.AWSNode, size int) ([]operatorv1alpha1.AWSNode, error) {
+	if len(nodes) == 0 {
 		return nil, errors.New("No nodes are running")
 	}
-	return target, nil
+	return firstNodes(oldestFirst(nodes), size), nil
+}
+
+// nodesOlderThan returns nodes which are created before start, so nodes which are launched in the refresh are not replaced again.
+func nodesOlderThan(nodes []operatorv1alpha1.AWSNode, start *metav1.Time) []operatorv1alpha1.AWSNode {
+	if start == nil {
+		return nodes
+	}
+	var older []operatorv1alpha1.AWSNode
+	for _, node := range nodes {
+		if node.CreationTimestamp.Before(start) {
+			older = append(older, node)
+		}
+	}
+	return older
+}
+
+func nodeNames(nodes []operatorv1alpha1.AWSNode) []string {
+	var names []string
+	for _, node := range nodes {
+		names = append(names, node.Name)
+	}
+	return names
 }
 
 func (r *AWSNodeRefresherReconciler) refreshNextReplace(ctx context.Context, refresher *operatorv1alpha1.AWSNodeRefresher) error {
+	now := metav1.Now()
 	refresher.Status.Phase = operatorv1alpha1.AWSNodeRefresherUpdateIncreasing
+	refresher.Status.PhaseStartTime = &now
 	refresher.Status.Revision += 1
 	if err := r.Client.Update(ctx, refresher); err != nil {
 		klog.Errorf(ctx, "failed to update refresher: %v", err)
@@ -78,13 +129,12 @@ func (r *AWSNodeRefresherReconciler) retryReplace(ctx context.Context, refresher
 	if waitingReplace(refresher) {
 		return true, false, nil
 	}
-	if !r.shouldRetryReplace(ctx, refresher) {
+	targets := r.shouldRetryReplace(ctx, refresher)
+	if len(targets) == 0 {
 		return false, false, nil
 	}
-	target := refresher.Status.ReplaceTargetNode
 	refresher.Status.Phase = operatorv1alpha1.AWSNodeRefresherUpdateReplacing
 	refresher.Status.LastASGModifiedTime = &now
-	refresher.Status.ReplaceTargetNode = target
 	refresher.Status.Revision += 1
 	if err := r.Client.Update(ctx, refresher); err != nil {
 		klog.Errorf(ctx, "failed to update refresher: %v", err)
@@ -92,28 +142,33 @@ func (r *AWSNodeRefresherReconciler) retryReplace(ctx context.Context, refresher
 	}
 	r.Recorder.Event(refresher, corev1.EventTypeNormal, "Retry replace", "Retry to replace instance in ASG for refresh")
 
-	err := r.cloud.DeleteInstance(target)
+	err := r.terminateInstances(targets)
 	return false, true, err
 }
 
-func (r *AWSNodeRefresherReconciler) shouldRetryReplace(ctx context.Context, refresher *operatorv1alpha1.AWSNodeRefresher) bool {
+// shouldRetryReplace returns the replace targets whose instances are not terminated yet.
+func (r *AWSNodeRefresherReconciler) shouldRetryReplace(ctx context.Context, refresher *operatorv1alpha1.AWSNodeRefresher) []operatorv1alpha1.AWSNode {
 	if refresher.Status.Phase != operatorv1alpha1.AWSNodeRefresherUpdateReplacing {
 		klog.Warningf(ctx, "AWSNodeRefresher phase is not matched: %s, so should not retry to replace", refresher.Status.Phase)
-		return false
-	}
-
-	instance, err := r.cloud.DescribeInstance(refresher.Status.ReplaceTargetNode)
-	if err != nil {
-		klog.Warning(ctx, err)
-		return false
+		return nil
 	}
 
-	if *instance.State.Name != ec2.InstanceStateNameTerminated {
-		klog.Infof(ctx, "Instance %s state is %s, so retry to replace it", *instance.InstanceId, *instance.State.Name)
-		return true
+	var targets []operatorv1alpha1.AWSNode
+	candidates := replaceTargets(refresher)
+	for i := range candidates {
+		target := &candidates[i]
+		terminated, err := r.cloud.InstanceTerminated(target)
+		if err != nil {
+			klog.Warning(ctx, err)
+			continue
+		}
+		if !terminated {
+			klog.Infof(ctx, "Instance %s is not terminated yet, so retry to replace it", target.InstanceID)
+			targets = append(targets, *target)
+		}
 	}
 
-	return false
+	return targets
 }
 
 func waitingReplace(refresher *operatorv1alpha1.AWSNodeRefresher) bool {
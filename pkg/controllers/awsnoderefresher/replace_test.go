@@ -539,7 +539,7 @@ func TestRetryReplace(t *testing.T) {
 								},
 								Tags: []*ec2.Tag{
 									&ec2.Tag{
-										Key:   aws.String("Name"),
+										Key:   aws.String("aws:autoscaling:groupName"),
 										Value: aws.String("autoscaling-group-name"),
 									},
 								},
@@ -624,7 +624,7 @@ func TestRetryReplace(t *testing.T) {
 								},
 								Tags: []*ec2.Tag{
 									&ec2.Tag{
-										Key:   aws.String("Name"),
+										Key:   aws.String("aws:autoscaling:groupName"),
 										Value: aws.String("autoscaling-group-name"),
 									},
 								},
@@ -775,7 +775,7 @@ func TestShouldRetryReplace(t *testing.T) {
 								},
 								Tags: []*ec2.Tag{
 									&ec2.Tag{
-										Key:   aws.String("Name"),
+										Key:   aws.String("aws:autoscaling:groupName"),
 										Value: aws.String("autoscaling-group-name"),
 									},
 								},
@@ -844,7 +844,7 @@ func TestShouldRetryReplace(t *testing.T) {
 								},
 								Tags: []*ec2.Tag{
 									&ec2.Tag{
-										Key:   aws.String("Name"),
+										Key:   aws.String("aws:autoscaling:groupName"),
 										Value: aws.String("autoscaling-group-name"),
 									},
 								},
@@ -874,9 +874,149 @@ func TestShouldRetryReplace(t *testing.T) {
 			Client: &mockedClient{},
 		}
 
-		result := r.shouldRetryReplace(ctx, c.refresher)
+		result := len(r.shouldRetryReplace(ctx, c.refresher)) > 0
 		if result != c.expected {
 			t.Errorf("CASE: %s : result is not matched, expected %t, but returned %t", c.title, c.expected, result)
 		}
 	}
 }
+
+func TestFindDeleteTargets(t *testing.T) {
+	start := &metav1.Time{Time: time.Now().Add(-10 * time.Minute)}
+	nodes := []operatorv1alpha1.AWSNode{
+		{
+			Name:              "node-new",
+			CreationTimestamp: metav1.Time{Time: time.Now().Add(-1 * time.Minute)},
+		},
+		{
+			Name:              "node-2",
+			CreationTimestamp: metav1.Time{Time: time.Now().Add(-2 * time.Hour)},
+		},
+		{
+			Name:              "node-1",
+			CreationTimestamp: metav1.Time{Time: time.Now().Add(-3 * time.Hour)},
+		},
+		{
+			Name:              "node-3",
+			CreationTimestamp: metav1.Time{Time: time.Now().Add(-1 * time.Hour)},
+		},
+	}
+	cases := []struct {
+		title    string
+		size     int
+		expected []string
+	}{
+		{
+			title:    "One node",
+			size:     1,
+			expected: []string{"node-1"},
+		},
+		{
+			title:    "Oldest nodes in the batch",
+			size:     2,
+			expected: []string{"node-1", "node-2"},
+		},
+		{
+			title:    "Batch is larger than old nodes",
+			size:     5,
+			expected: []string{"node-1", "node-2", "node-3"},
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		targets, err := findDeleteTargets(nodesOlderThan(nodes, start), c.size)
+		if err != nil {
+			t.Errorf("CASE: %s : %v", c.title, err)
+			continue
+		}
+		names := nodeNames(targets)
+		if len(names) != len(c.expected) {
+			t.Errorf("CASE: %s : targets are not matched, expected %v, but returned %v", c.title, c.expected, names)
+			continue
+		}
+		for i := range names {
+			if names[i] != c.expected[i] {
+				t.Errorf("CASE: %s : targets are not matched, expected %v, but returned %v", c.title, c.expected, names)
+				break
+			}
+		}
+	}
+
+	if _, err := findDeleteTargets(nil, 1); err == nil {
+		t.Errorf("error should be returned when no nodes are running")
+	}
+}
+
+func TestMigrateReplaceTarget(t *testing.T) {
+	ctx := context.Background()
+	// The status is recorded by the controller which replaces nodes one by one.
+	refresher := &operatorv1alpha1.AWSNodeRefresher{
+		Spec: operatorv1alpha1.AWSNodeRefresherSpec{
+			Region: "us-east-1",
+			AutoScalingGroups: []operatorv1alpha1.AutoScalingGroup{
+				{
+					Name: "autoscaling-group-name",
+				},
+			},
+			Desired:                  1,
+			ASGModifyCoolTimeSeconds: 600,
+			Role:                     operatorv1alpha1.Worker,
+			Schedule:                 "* * * * *",
+			SurplusNodes:             1,
+			DrainGracePeriodSeconds:  300,
+		},
+		Status: operatorv1alpha1.AWSNodeRefresherStatus{
+			LastASGModifiedTime: &metav1.Time{
+				Time: time.Now().Add(-5 * time.Minute),
+			},
+			Phase: operatorv1alpha1.AWSNodeRefresherUpdateReplacing,
+			UpdateStartTime: &metav1.Time{
+				Time: time.Now().Add(-10 * time.Minute),
+			},
+			ReplaceTargetNode: &operatorv1alpha1.AWSNode{
+				Name:                 "node-1",
+				InstanceID:           "instanceId-1",
+				AutoScalingGroupName: "autoscaling-group-name",
+			},
+		},
+	}
+	mockedEC2 := &mockedEC2API{
+		DescribeInstancesResp: &ec2.DescribeInstancesOutput{
+			Reservations: []*ec2.Reservation{
+				{
+					Instances: []*ec2.Instance{
+						{
+							InstanceId: aws.String("instanceId-1"),
+							State: &ec2.InstanceState{
+								Name: aws.String(ec2.InstanceStateNameRunning),
+							},
+						},
+					},
+				},
+			},
+		},
+		TerminateInstancesResp: &ec2.TerminateInstancesOutput{},
+		terminateInstanceID:    aws.String("instanceId-1"),
+	}
+	r := &AWSNodeRefresherReconciler{
+		cloud: &cloudaws.AWS{
+			EC2: mockedEC2,
+		},
+		Client:   &mockedClient{},
+		Recorder: &mockedRecorder{},
+	}
+
+	if err := r.syncRefresher(ctx, refresher); err != nil {
+		t.Fatal(err)
+	}
+	if refresher.Status.ReplaceTargetNode != nil {
+		t.Errorf("deprecated replace target should be cleared, but returned %v", refresher.Status.ReplaceTargetNode)
+	}
+	if names := nodeNames(refresher.Status.ReplaceTargetNodes); len(names) != 1 || names[0] != "node-1" {
+		t.Errorf("replace target should be moved, but returned %v", names)
+	}
+	if !refresher.Status.LastASGModifiedTime.After(time.Now().Add(-1 * time.Minute)) {
+		t.Errorf("instance of the replace target should be terminated again")
+	}
+}
@@ -13,7 +13,25 @@ import (
 )
 
 func (r *AWSNodeRefresherReconciler) scheduleNext(ctx context.Context, refresher *operatorv1alpha1.AWSNodeRefresher) error {
-	next := metav1.NewTime(cronexpr.MustParse(refresher.Spec.Schedule).Next(time.Now()))
+	if refresher.Spec.Trigger == operatorv1alpha1.RefreshTriggerDrift || (refresher.Spec.Schedule == "" && refresher.Spec.MaintenanceWindow == nil) {
+		return r.waitTrigger(ctx, refresher)
+	}
+	var next metav1.Time
+	if refresher.Spec.MaintenanceWindow != nil {
+		t, err := nextWindow(refresher.Spec.MaintenanceWindow, time.Now())
+		if err != nil {
+			klog.Errorf(ctx, "failed to find next maintenance window: %v", err)
+			return err
+		}
+		next = metav1.NewTime(t)
+	} else {
+		expr, err := cronexpr.Parse(refresher.Spec.Schedule)
+		if err != nil {
+			klog.Errorf(ctx, "failed to parse schedule %q: %v", refresher.Spec.Schedule, err)
+			return err
+		}
+		next = metav1.NewTime(expr.Next(time.Now()))
+	}
 	refresher.Status.NextUpdateTime = &next
 	refresher.Status.Phase = operatorv1alpha1.AWSNodeRefresherScheduled
 	refresher.Status.Revision += 1
@@ -25,3 +43,31 @@ func (r *AWSNodeRefresherReconciler) scheduleNext(ctx context.Context, refresher
 	r.Recorder.Event(refresher, corev1.EventTypeNormal, "Schedule next", "Update refresh schedule for next refresh")
 	return nil
 }
+
+// waitTrigger waits for drifted nodes, a new AMI, expired nodes or the refresh-now annotation instead of the schedule.
+func (r *AWSNodeRefresherReconciler) waitTrigger(ctx context.Context, refresher *operatorv1alpha1.AWSNodeRefresher) error {
+	refresher.Status.NextUpdateTime = nil
+	refresher.Status.Phase = operatorv1alpha1.AWSNodeRefresherScheduled
+	refresher.Status.Revision += 1
+
+	if err := r.Client.Update(ctx, refresher); err != nil {
+		klog.Errorf(ctx, "failed to update AWSNodeRefresher %s/%s: %v", refresher.Namespace, refresher.Name, err)
+		return err
+	}
+	message := "Wait for the refresh-now annotation for next refresh"
+	if refresher.Spec.Trigger == operatorv1alpha1.RefreshTriggerDrift {
+		message = "Wait for drifted nodes for next refresh"
+	} else if refresher.Spec.AMISource != nil {
+		message = "Wait for a new AMI for next refresh"
+	} else if refresher.Spec.MaxNodeAge != nil {
+		message = "Wait for expired nodes for next refresh"
+	}
+	r.Recorder.Event(refresher, corev1.EventTypeNormal, "Schedule next", message)
+	return nil
+}
+
+// windowPassed returns whether the schedule has passed without anything to refresh, then the next schedule should be the window.
+// A plain schedule never passes, because the refresh is always due.
+func windowPassed(refresher *operatorv1alpha1.AWSNodeRefresher, now *metav1.Time) bool {
+	return refresher.Status.NextUpdateTime != nil && refresher.Status.NextUpdateTime.Before(now) && !refreshDue(refresher, now)
+}
@@ -85,3 +85,33 @@ func TestScheduleNext(t *testing.T) {
 		}
 	}
 }
+
+func TestScheduleNextInvalidSchedule(t *testing.T) {
+	refresher := &operatorv1alpha1.AWSNodeRefresher{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-refresher",
+		},
+		Spec: operatorv1alpha1.AWSNodeRefresherSpec{
+			Region:   "us-east-1",
+			Desired:  2,
+			Role:     operatorv1alpha1.Worker,
+			Schedule: "invalid schedule",
+		},
+		Status: operatorv1alpha1.AWSNodeRefresherStatus{
+			Phase: operatorv1alpha1.AWSNodeRefresherInit,
+		},
+	}
+	r := &AWSNodeRefresherReconciler{
+		cloud:    &cloudaws.AWS{},
+		Client:   &mockedClient{},
+		Recorder: &mockedRecorder{},
+	}
+
+	err := r.scheduleNext(context.Background(), refresher)
+	if err == nil {
+		t.Error("Error should be returned for invalid schedule")
+	}
+	if refresher.Status.Phase != operatorv1alpha1.AWSNodeRefresherInit {
+		t.Errorf("Phase should not be changed: %s", refresher.Status.Phase)
+	}
+}
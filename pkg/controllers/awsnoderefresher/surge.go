@@ -0,0 +1,45 @@
+package awsnoderefresher
+
+import (
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+)
+
+// surplusNodes returns how many nodes are added over desired during a refresh.
+// maxSurge is rounded up like Deployment, and surplusNodes is used when it is not specified.
+func surplusNodes(refresher *operatorv1alpha1.AWSNodeRefresher) int {
+	if refresher.Spec.MaxSurge == nil {
+		return int(refresher.Spec.SurplusNodes)
+	}
+	surge, err := intstr.GetScaledValueFromIntOrPercent(refresher.Spec.MaxSurge, int(refresher.Spec.Desired), true)
+	if err != nil {
+		return int(refresher.Spec.SurplusNodes)
+	}
+	return surge
+}
+
+// maxUnavailable returns how many nodes can be unavailable below desired during a refresh. It is rounded down.
+func maxUnavailable(refresher *operatorv1alpha1.AWSNodeRefresher) int {
+	if refresher.Spec.MaxUnavailable == nil {
+		return 0
+	}
+	unavailable, err := intstr.GetScaledValueFromIntOrPercent(refresher.Spec.MaxUnavailable, int(refresher.Spec.Desired), false)
+	if err != nil {
+		return 0
+	}
+	return unavailable
+}
+
+// batchSize returns how many nodes are drained and replaced at once.
+// Nodes are replaced one by one unless maxSurge or maxUnavailable is specified.
+func batchSize(refresher *operatorv1alpha1.AWSNodeRefresher) int {
+	if refresher.Spec.MaxSurge == nil && refresher.Spec.MaxUnavailable == nil {
+		return 1
+	}
+	size := surplusNodes(refresher) + maxUnavailable(refresher)
+	if size < 1 {
+		return 1
+	}
+	return size
+}
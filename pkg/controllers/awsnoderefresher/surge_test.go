@@ -0,0 +1,71 @@
+package awsnoderefresher
+
+import (
+	"log"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+)
+
+func TestBatchSize(t *testing.T) {
+	percent := intstr.FromString("25%")
+	two := intstr.FromInt32(2)
+	zero := intstr.FromInt32(0)
+	cases := []struct {
+		title           string
+		surplusNodes    int64
+		surge           *intstr.IntOrString
+		unavailable     *intstr.IntOrString
+		expectedSurplus int
+		expectedBatch   int
+	}{
+		{
+			title:           "Surge and unavailable are not specified",
+			surplusNodes:    2,
+			expectedSurplus: 2,
+			expectedBatch:   1,
+		},
+		{
+			title:           "Surge is a percentage",
+			surplusNodes:    1,
+			surge:           &percent,
+			expectedSurplus: 3,
+			expectedBatch:   3,
+		},
+		{
+			title:           "Unavailable is added to surplus nodes",
+			surplusNodes:    1,
+			unavailable:     &two,
+			expectedSurplus: 1,
+			expectedBatch:   3,
+		},
+		{
+			title:           "Surge is zero and unavailable is a percentage",
+			surplusNodes:    1,
+			surge:           &zero,
+			unavailable:     &percent,
+			expectedSurplus: 0,
+			expectedBatch:   2,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		refresher := &operatorv1alpha1.AWSNodeRefresher{
+			Spec: operatorv1alpha1.AWSNodeRefresherSpec{
+				Desired:        10,
+				SurplusNodes:   c.surplusNodes,
+				MaxSurge:       c.surge,
+				MaxUnavailable: c.unavailable,
+			},
+		}
+		if surplus := surplusNodes(refresher); surplus != c.expectedSurplus {
+			t.Errorf("CASE: %s : surplus nodes are not matched, expected %d, but returned %d", c.title, c.expectedSurplus, surplus)
+		}
+		if size := batchSize(refresher); size != c.expectedBatch {
+			t.Errorf("CASE: %s : batch size is not matched, expected %d, but returned %d", c.title, c.expectedBatch, size)
+		}
+	}
+}
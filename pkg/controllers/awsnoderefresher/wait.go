@@ -5,6 +5,7 @@ import (
 	"time"
 
 	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+	"github.com/h3poteto/node-manager/pkg/cloud"
 	"github.com/h3poteto/node-manager/pkg/util/klog"
 
 	corev1 "k8s.io/api/core/v1"
@@ -15,7 +16,9 @@ func (r *AWSNodeRefresherReconciler) refreshAWSWait(ctx context.Context, refresh
 	if !shouldAWSWait(ctx, refresher) {
 		return nil
 	}
+	now := metav1.Now()
 	refresher.Status.Phase = operatorv1alpha1.AWSNodeRefresherUpdateAWSWaiting
+	refresher.Status.PhaseStartTime = &now
 	refresher.Status.Revision += 1
 	if err := r.Client.Update(ctx, refresher); err != nil {
 		klog.Errorf(ctx, "failed to update refresher: %v", err)
@@ -30,8 +33,11 @@ func shouldAWSWait(ctx context.Context, refresher *operatorv1alpha1.AWSNodeRefre
 		klog.Warningf(ctx, "AWSNodeRefresher phase is not matched: %s, so should not aws wait", refresher.Status.Phase)
 		return false
 	}
-	if nodeStillLiving(refresher.Status.AWSNodes, refresher.Status.ReplaceTargetNode) {
-		return false
+	targets := replaceTargets(refresher)
+	for i := range targets {
+		if nodeStillLiving(refresher.Status.AWSNodes, &targets[i]) {
+			return false
+		}
 	}
 	return true
 }
@@ -56,14 +62,32 @@ func waiting(refresher *operatorv1alpha1.AWSNodeRefresher, now *metav1.Time) boo
 }
 
 func enoughInstances(ctx context.Context, refresher *operatorv1alpha1.AWSNodeRefresher) bool {
-	if len(refresher.Status.AWSNodes) < int(refresher.Spec.Desired)+int(refresher.Spec.SurplusNodes) {
-		klog.Infof(ctx, "Instance is not enough, current: %d, expected: %d + %d", len(refresher.Status.AWSNodes), refresher.Spec.Desired, refresher.Spec.SurplusNodes)
+	if len(refresher.Status.AWSNodes) < int(refresher.Spec.Desired)+surplusNodes(refresher) {
+		klog.Infof(ctx, "Instance is not enough, current: %d, expected: %d + %d", len(refresher.Status.AWSNodes), refresher.Spec.Desired, surplusNodes(refresher))
 		return false
 	}
 	return true
 }
 
+// restoreCapacity restores capacity of the node groups which was decreased by termination of instances,
+// so new instances are launched even when the restore in TerminateInstance failed.
+func (r *AWSNodeRefresherReconciler) restoreCapacity(ctx context.Context, refresher *operatorv1alpha1.AWSNodeRefresher) error {
+	restorer, ok := r.cloud.(cloud.CapacityRestorer)
+	if !ok {
+		return nil
+	}
+	if err := restorer.RestoreCapacity(refresher.Spec.AutoScalingGroups, int(refresher.Spec.Desired)+surplusNodes(refresher)); err != nil {
+		klog.Errorf(ctx, "failed to restore capacity: %v", err)
+		return err
+	}
+	return nil
+}
+
 func (r *AWSNodeRefresherReconciler) allReplaced(ctx context.Context, refresher *operatorv1alpha1.AWSNodeRefresher) bool {
+	if !refresher.Status.OnDemand && (refresher.Spec.Trigger == operatorv1alpha1.RefreshTriggerDrift || refresher.Spec.MaxNodeAge != nil) {
+		now := metav1.Now()
+		return len(replaceCandidates(refresher, &now)) == 0
+	}
 	return allNodesNewer(refresher.Status.AWSNodes, refresher.Status.UpdateStartTime)
 }
 
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+	"github.com/h3poteto/node-manager/pkg/cloud"
 	cloudaws "github.com/h3poteto/node-manager/pkg/cloud/aws"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -614,3 +615,87 @@ func TestAllReplaced(t *testing.T) {
 		}
 	}
 }
+
+// capacityRestorer is a provider which decreases capacity of node groups when instances are terminated.
+type capacityRestorer struct {
+	cloud.Provider
+	total int
+}
+
+func (c *capacityRestorer) RestoreCapacity(groups []operatorv1alpha1.AutoScalingGroup, total int) error {
+	c.total = total
+	return nil
+}
+
+func TestRestoreCapacity(t *testing.T) {
+	cases := []struct {
+		title    string
+		awsNodes []operatorv1alpha1.AWSNode
+		total    int
+	}{
+		{
+			title: "Instances are not enough",
+			awsNodes: []operatorv1alpha1.AWSNode{
+				{Name: "node-1", InstanceID: "vmss-a_1"},
+				{Name: "node-2", InstanceID: "vmss-a_2"},
+			},
+			total: 3,
+		},
+		{
+			title: "Instances are enough",
+			awsNodes: []operatorv1alpha1.AWSNode{
+				{Name: "node-1", InstanceID: "vmss-a_1"},
+				{Name: "node-2", InstanceID: "vmss-a_2"},
+				{Name: "node-3", InstanceID: "vmss-a_3"},
+			},
+			total: 0,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		ctx := context.Background()
+		refresher := &operatorv1alpha1.AWSNodeRefresher{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-refresher",
+			},
+			Spec: operatorv1alpha1.AWSNodeRefresherSpec{
+				CloudTarget: operatorv1alpha1.CloudTarget{
+					CloudProvider: operatorv1alpha1.CloudProviderAzure,
+				},
+				AutoScalingGroups: []operatorv1alpha1.AutoScalingGroup{
+					{
+						Name: "vmss-a",
+					},
+				},
+				Desired:                  2,
+				ASGModifyCoolTimeSeconds: 60,
+				Role:                     operatorv1alpha1.Worker,
+				SurplusNodes:             1,
+			},
+			Status: operatorv1alpha1.AWSNodeRefresherStatus{
+				AWSNodes: c.awsNodes,
+				LastASGModifiedTime: &metav1.Time{
+					Time: time.Now().Add(-10 * time.Minute),
+				},
+				Phase: operatorv1alpha1.AWSNodeRefresherUpdateAWSWaiting,
+				UpdateStartTime: &metav1.Time{
+					Time: time.Now().Add(-1 * time.Hour),
+				},
+			},
+		}
+		restorer := &capacityRestorer{}
+		r := &AWSNodeRefresherReconciler{
+			cloud:    restorer,
+			Client:   &mockedClient{},
+			Recorder: &mockedRecorder{},
+		}
+
+		if err := r.syncRefresher(ctx, refresher); err != nil {
+			t.Errorf("CASE: %s : Failed to sync refresher: %v", c.title, err)
+		}
+		if restorer.total != c.total {
+			t.Errorf("CASE: %s : Restored capacity is not matched, expected %d, returned %d", c.title, c.total, restorer.total)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package awsnoderefresher
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gorhill/cronexpr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+	"github.com/h3poteto/node-manager/pkg/util/klog"
+)
+
+// maxWindowLookahead is the number of windows to look for the next one which is not covered by blackouts.
+const maxWindowLookahead = 1000
+
+// parseMaintenanceWindow parses the schedule and the time zone of the window. Empty time zone is UTC.
+func parseMaintenanceWindow(window *operatorv1alpha1.MaintenanceWindow) (*cronexpr.Expression, *time.Location, error) {
+	expr, err := cronexpr.Parse(window.Schedule)
+	if err != nil {
+		return nil, nil, err
+	}
+	loc, err := time.LoadLocation(window.TimeZone)
+	if err != nil {
+		return nil, nil, err
+	}
+	return expr, loc, nil
+}
+
+// windowOpen returns whether nodes can be replaced at now. It is always open without maintenanceWindow.
+func windowOpen(refresher *operatorv1alpha1.AWSNodeRefresher, now *metav1.Time) bool {
+	window := refresher.Spec.MaintenanceWindow
+	if window == nil {
+		return true
+	}
+	expr, loc, err := parseMaintenanceWindow(window)
+	if err != nil {
+		return false
+	}
+	// The window which contains now opens in the duration before now.
+	start := expr.Next(now.In(loc).Add(-window.Duration.Duration))
+	if start.IsZero() || start.After(now.Time) {
+		return false
+	}
+	_, blackout := blackoutEnd(window.Blackouts, now.Time)
+	return !blackout
+}
+
+// nextWindow returns when the next window opens after now. The window opens at the end of blackouts when they cover the start,
+// and windows which are covered by blackouts entirely are skipped.
+func nextWindow(window *operatorv1alpha1.MaintenanceWindow, now time.Time) (time.Time, error) {
+	expr, loc, err := parseMaintenanceWindow(window)
+	if err != nil {
+		return time.Time{}, err
+	}
+	t := now.In(loc)
+	for i := 0; i < maxWindowLookahead; i++ {
+		start := expr.Next(t)
+		if start.IsZero() {
+			break
+		}
+		open := start
+		for {
+			end, ok := blackoutEnd(window.Blackouts, open)
+			if !ok {
+				break
+			}
+			open = end
+		}
+		if open.Before(start.Add(window.Duration.Duration)) {
+			return open, nil
+		}
+		t = start
+	}
+	return time.Time{}, fmt.Errorf("maintenance window %q does not open after %s", window.Schedule, now.Format(time.RFC3339))
+}
+
+// blackoutEnd returns the end of the blackout which contains t.
+func blackoutEnd(blackouts []operatorv1alpha1.BlackoutPeriod, t time.Time) (time.Time, bool) {
+	for i := range blackouts {
+		if !t.Before(blackouts[i].Start.Time) && t.Before(blackouts[i].End.Time) {
+			return blackouts[i].End.Time, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// pauseRefresh pauses the refresh between replacements until the refresher is resumed or the next window opens.
+func (r *AWSNodeRefresherReconciler) pauseRefresh(ctx context.Context, refresher *operatorv1alpha1.AWSNodeRefresher) error {
+	if refresher.Spec.Suspend {
+		refresher.Status.Phase = operatorv1alpha1.AWSNodeRefresherPaused
+		refresher.Status.Revision += 1
+		if err := r.Client.Update(ctx, refresher); err != nil {
+			klog.Errorf(ctx, "failed to update refresher: %v", err)
+			return err
+		}
+		r.Recorder.Event(refresher, corev1.EventTypeNormal, "Pause refresh", "Refresher is suspended, so pause refresh")
+		return nil
+	}
+	next, err := nextWindow(refresher.Spec.MaintenanceWindow, time.Now())
+	if err != nil {
+		klog.Errorf(ctx, "failed to find next maintenance window: %v", err)
+		return err
+	}
+	nextTime := metav1.NewTime(next)
+	refresher.Status.Phase = operatorv1alpha1.AWSNodeRefresherPaused
+	refresher.Status.NextUpdateTime = &nextTime
+	refresher.Status.Revision += 1
+	if err := r.Client.Update(ctx, refresher); err != nil {
+		klog.Errorf(ctx, "failed to update refresher: %v", err)
+		return err
+	}
+	r.Recorder.Eventf(refresher, corev1.EventTypeNormal, "Pause refresh", "Maintenance window is closed, so pause refresh until %s", nextTime.Format(time.RFC3339))
+	return nil
+}
@@ -0,0 +1,160 @@
+package awsnoderefresher
+
+import (
+	"log"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+)
+
+func mustParseTime(t *testing.T, value string) time.Time {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func TestWindowOpen(t *testing.T) {
+	cases := []struct {
+		title    string
+		window   *operatorv1alpha1.MaintenanceWindow
+		now      string
+		expected bool
+	}{
+		{
+			title:    "Maintenance window is not specified",
+			window:   nil,
+			now:      "2026-10-17T14:00:00Z",
+			expected: true,
+		},
+		{
+			title: "Window is open in the time zone",
+			window: &operatorv1alpha1.MaintenanceWindow{
+				Schedule: "0 22 * * *",
+				Duration: metav1.Duration{Duration: 4 * time.Hour},
+				TimeZone: "Asia/Tokyo",
+			},
+			now:      "2026-10-17T14:00:00Z",
+			expected: true,
+		},
+		{
+			title: "Window is closed in UTC",
+			window: &operatorv1alpha1.MaintenanceWindow{
+				Schedule: "0 22 * * *",
+				Duration: metav1.Duration{Duration: 4 * time.Hour},
+			},
+			now:      "2026-10-17T14:00:00Z",
+			expected: false,
+		},
+		{
+			title: "Window has been closed",
+			window: &operatorv1alpha1.MaintenanceWindow{
+				Schedule: "0 22 * * *",
+				Duration: metav1.Duration{Duration: 4 * time.Hour},
+				TimeZone: "Asia/Tokyo",
+			},
+			now:      "2026-10-17T17:00:00Z",
+			expected: false,
+		},
+		{
+			title: "Window is open over midnight",
+			window: &operatorv1alpha1.MaintenanceWindow{
+				Schedule: "0 23 * * *",
+				Duration: metav1.Duration{Duration: 2 * time.Hour},
+			},
+			now:      "2026-10-18T00:30:00Z",
+			expected: true,
+		},
+		{
+			title: "Window is in a blackout",
+			window: &operatorv1alpha1.MaintenanceWindow{
+				Schedule: "0 22 * * *",
+				Duration: metav1.Duration{Duration: 4 * time.Hour},
+				TimeZone: "Asia/Tokyo",
+				Blackouts: []operatorv1alpha1.BlackoutPeriod{
+					{
+						Start: metav1.NewTime(mustParseTime(t, "2026-10-17T00:00:00Z")),
+						End:   metav1.NewTime(mustParseTime(t, "2026-10-18T00:00:00Z")),
+					},
+				},
+			},
+			now:      "2026-10-17T14:00:00Z",
+			expected: false,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		refresher := &operatorv1alpha1.AWSNodeRefresher{
+			Spec: operatorv1alpha1.AWSNodeRefresherSpec{
+				MaintenanceWindow: c.window,
+			},
+		}
+		now := metav1.NewTime(mustParseTime(t, c.now))
+		result := windowOpen(refresher, &now)
+		if result != c.expected {
+			t.Errorf("CASE: %s : expected %v, but returned %v", c.title, c.expected, result)
+		}
+	}
+}
+
+func TestNextWindow(t *testing.T) {
+	cases := []struct {
+		title     string
+		blackouts []operatorv1alpha1.BlackoutPeriod
+		expected  string
+	}{
+		{
+			title:     "No blackouts",
+			blackouts: nil,
+			expected:  "2026-10-18T13:00:00Z",
+		},
+		{
+			title: "Blackout covers the whole window",
+			blackouts: []operatorv1alpha1.BlackoutPeriod{
+				{
+					Start:  metav1.NewTime(mustParseTime(t, "2026-10-18T00:00:00Z")),
+					End:    metav1.NewTime(mustParseTime(t, "2026-10-19T00:00:00Z")),
+					Reason: "holiday freeze",
+				},
+			},
+			expected: "2026-10-19T13:00:00Z",
+		},
+		{
+			title: "Blackouts cover the start of the window",
+			blackouts: []operatorv1alpha1.BlackoutPeriod{
+				{
+					Start: metav1.NewTime(mustParseTime(t, "2026-10-18T12:00:00Z")),
+					End:   metav1.NewTime(mustParseTime(t, "2026-10-18T14:00:00Z")),
+				},
+				{
+					Start: metav1.NewTime(mustParseTime(t, "2026-10-18T14:00:00Z")),
+					End:   metav1.NewTime(mustParseTime(t, "2026-10-18T15:00:00Z")),
+				},
+			},
+			expected: "2026-10-18T15:00:00Z",
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		window := &operatorv1alpha1.MaintenanceWindow{
+			Schedule:  "0 22 * * *",
+			Duration:  metav1.Duration{Duration: 4 * time.Hour},
+			TimeZone:  "Asia/Tokyo",
+			Blackouts: c.blackouts,
+		}
+		next, err := nextWindow(window, mustParseTime(t, "2026-10-17T14:00:00Z"))
+		if err != nil {
+			t.Errorf("CASE: %s : %v", c.title, err)
+			continue
+		}
+		if !next.Equal(mustParseTime(t, c.expected)) {
+			t.Errorf("CASE: %s : expected %s, but returned %s", c.title, c.expected, next.UTC().Format(time.RFC3339))
+		}
+	}
+}
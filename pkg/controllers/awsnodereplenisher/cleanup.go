@@ -22,13 +22,13 @@ func (r *AWSNodeReplenisherReconciler) syncNotJoinedAWSNodes(ctx context.Context
 		if err := r.updateStatusAWSUpdating(ctx, replenisher); err != nil {
 			return err
 		}
-		err := r.cloud.DetachInstanceFromASG(node.InstanceID, node.AutoScalingGroupName)
+		err := r.cloud.DetachInstance(&node)
 		if err != nil {
 			klog.Errorf(ctx, "failed to detach instance %s from ASG %s: %v", node.InstanceID, node.AutoScalingGroupName, err)
 			return err
 		}
 
-		err = r.cloud.DeleteInstance(&node)
+		err = r.cloud.TerminateInstance(&node)
 		if err != nil {
 			klog.Errorf(ctx, "failed to delete instance %s: %v", node.InstanceID, err)
 			return err
@@ -0,0 +1,54 @@
+package awsnodereplenisher
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+	"github.com/h3poteto/node-manager/pkg/util/conditions"
+	"github.com/h3poteto/node-manager/pkg/util/klog"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// updateConditions reflects the current phase and the result of sync to status conditions.
+func (r *AWSNodeReplenisherReconciler) updateConditions(ctx context.Context, replenisher *operatorv1alpha1.AWSNodeReplenisher, syncErr error) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := operatorv1alpha1.AWSNodeReplenisher{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: replenisher.Namespace, Name: replenisher.Name}, &current); err != nil {
+			klog.Errorf(ctx, "failed to get AWSNodeReplenisher %s/%s: %v", replenisher.Namespace, replenisher.Name, err)
+			return err
+		}
+		before := current.Status.DeepCopy()
+		setConditions(&current, syncErr)
+		if reflect.DeepEqual(before.Conditions, current.Status.Conditions) {
+			return nil
+		}
+		if err := r.Client.Update(ctx, &current); err != nil {
+			klog.Errorf(ctx, "failed to update AWSNodeReplenisher conditions %s/%s: %v", current.Namespace, current.Name, err)
+			return err
+		}
+		return nil
+	})
+}
+
+func setConditions(replenisher *operatorv1alpha1.AWSNodeReplenisher, syncErr error) {
+	generation := replenisher.Generation
+	c := &replenisher.Status.Conditions
+	switch replenisher.Status.Phase {
+	case "", operatorv1alpha1.AWSNodeReplenisherInit:
+		conditions.Set(c, generation, operatorv1alpha1.ConditionReady, metav1.ConditionFalse, operatorv1alpha1.ReasonInitializing, "Nodes are not synced yet")
+		conditions.Set(c, generation, operatorv1alpha1.ConditionReplenishing, metav1.ConditionFalse, operatorv1alpha1.ReasonIdle, "")
+	case operatorv1alpha1.AWSNodeReplenisherSynced:
+		message := fmt.Sprintf("%d nodes are running", len(replenisher.Status.AWSNodes))
+		conditions.Set(c, generation, operatorv1alpha1.ConditionReady, metav1.ConditionTrue, operatorv1alpha1.ReasonSynced, message)
+		conditions.Set(c, generation, operatorv1alpha1.ConditionReplenishing, metav1.ConditionFalse, operatorv1alpha1.ReasonIdle, "")
+	default:
+		message := fmt.Sprintf("Current nodes: %d, desired: %d, not joined instances: %d", len(replenisher.Status.AWSNodes), replenisher.Spec.Desired, len(replenisher.Status.NotJoinedAWSNodes))
+		conditions.Set(c, generation, operatorv1alpha1.ConditionReady, metav1.ConditionFalse, operatorv1alpha1.ReasonReplenishing, message)
+		conditions.Set(c, generation, operatorv1alpha1.ConditionReplenishing, metav1.ConditionTrue, conditions.Reason(string(replenisher.Status.Phase)), message)
+	}
+	conditions.SetSyncResult(c, generation, syncErr)
+}
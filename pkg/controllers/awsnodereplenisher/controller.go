@@ -20,7 +20,6 @@ import (
 	"context"
 	"time"
 
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -31,7 +30,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
-	cloudaws "github.com/h3poteto/node-manager/pkg/cloud/aws"
+	"github.com/h3poteto/node-manager/pkg/cloud"
 	pkgctx "github.com/h3poteto/node-manager/pkg/util/context"
 	"github.com/h3poteto/node-manager/pkg/util/externalevent"
 	"github.com/h3poteto/node-manager/pkg/util/klog"
@@ -45,10 +44,11 @@ const (
 // AWSNodeReplenisherReconciler reconciles a AWSNodeReplenisher object
 type AWSNodeReplenisherReconciler struct {
 	client.Client
-	Log      logr.Logger
-	Scheme   *runtime.Scheme
-	Recorder record.EventRecorder
-	cloud    *cloudaws.AWS
+	Log       logr.Logger
+	Scheme    *runtime.Scheme
+	Recorder  record.EventRecorder
+	Providers *cloud.Registry
+	cloud     cloud.Provider
 }
 
 // +kubebuilder:rbac:groups=operator.h3poteto.dev,resources=awsnodereplenishers,verbs=get;list;watch;create;update;patch;delete
@@ -71,19 +71,29 @@ func (r *AWSNodeReplenisherReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	// Generate aws client
-	sess := session.Must(session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-	}))
-	r.cloud = cloudaws.New(sess, replenisher.Spec.Region)
-
-	if err := r.syncReplenisher(ctx, &replenisher); err != nil {
-		klog.Errorf(ctx, "failed to sync AWSNodeReplenisher: %v", err)
-		r.Recorder.Eventf(&replenisher, corev1.EventTypeWarning, "Error", "Failed to sync: %v", err)
+	providerName, config := cloud.NewConfig(replenisher.Spec.CloudTarget, replenisher.Spec.Region)
+	provider, err := r.Providers.Provider(providerName, config)
+	if err != nil {
+		klog.Errorf(ctx, "failed to build cloud provider: %v", err)
 		return ctrl.Result{}, err
 	}
+	// Keep the provider in a copy, because the reconciler is shared with concurrent reconciles.
+	rc := *r
+	rc.cloud = provider
+
+	syncErr := rc.syncReplenisher(ctx, &replenisher)
+	if syncErr != nil {
+		klog.Errorf(ctx, "failed to sync AWSNodeReplenisher: %v", syncErr)
+		r.Recorder.Eventf(&replenisher, corev1.EventTypeWarning, "Error", "Failed to sync: %v", syncErr)
+	}
+	if err := r.updateConditions(ctx, &replenisher, syncErr); err != nil {
+		klog.Errorf(ctx, "failed to update conditions of AWSNodeReplenisher: %v", err)
+		if syncErr == nil {
+			return ctrl.Result{}, err
+		}
+	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{}, syncErr
 }
 
 func (r *AWSNodeReplenisherReconciler) SetupWithManager(mgr ctrl.Manager) error {
@@ -11,7 +11,7 @@ func (r *AWSNodeReplenisherReconciler) addNode(ctx context.Context, replenisher
 		return err
 	}
 
-	return r.cloud.AddInstancesToAutoScalingGroups(replenisher.Spec.AutoScalingGroups, int(replenisher.Spec.Desired), currentNodesCount)
+	return r.cloud.ScaleUpNodeGroups(replenisher.Spec.AutoScalingGroups, int(replenisher.Spec.Desired), currentNodesCount)
 }
 
 func (r *AWSNodeReplenisherReconciler) deleteNode(ctx context.Context, replenisher *operatorv1alpha1.AWSNodeReplenisher, currentNodesCount int) error {
@@ -19,5 +19,5 @@ func (r *AWSNodeReplenisherReconciler) deleteNode(ctx context.Context, replenish
 		return err
 	}
 
-	return r.cloud.DeleteInstancesToAutoScalingGroups(replenisher.Spec.AutoScalingGroups, int(replenisher.Spec.Desired), currentNodesCount)
+	return r.cloud.ScaleDownNodeGroups(replenisher.Spec.AutoScalingGroups, int(replenisher.Spec.Desired), currentNodesCount)
 }
@@ -0,0 +1,268 @@
+package controllers_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+	"github.com/h3poteto/node-manager/pkg/cloud/aws/fake"
+	"github.com/h3poteto/node-manager/pkg/controllers/nodemanager"
+)
+
+func TestNodeManagerCreatesChildren(t *testing.T) {
+	requireEnvtest(t)
+	ctx := context.Background()
+	nodeManager := createNodeManager(t, "creation", 2, 2)
+
+	awsNodeManager := &operatorv1alpha1.AWSNodeManager{}
+	eventually(t, "AWSNodeManager for workers", func() (bool, string) {
+		if err := k8sClient.Get(ctx, key(nodeManager.Name+"-worker"), awsNodeManager); err != nil {
+			return false, err.Error()
+		}
+		return true, ""
+	})
+	assertControllerRef(t, awsNodeManager, nodeManager)
+	if err := k8sClient.Get(ctx, key(nodeManager.Name+"-master"), &operatorv1alpha1.AWSNodeManager{}); !apierrors.IsNotFound(err) {
+		t.Errorf("AWSNodeManager for masters should not be created without spec.aws.masters, but returned %v", err)
+	}
+
+	replenisher := &operatorv1alpha1.AWSNodeReplenisher{}
+	refresher := &operatorv1alpha1.AWSNodeRefresher{}
+	eventually(t, "AWSNodeReplenisher and AWSNodeRefresher", func() (bool, string) {
+		if err := k8sClient.Get(ctx, key(awsNodeManager.Name), replenisher); err != nil {
+			return false, err.Error()
+		}
+		if err := k8sClient.Get(ctx, key(awsNodeManager.Name), refresher); err != nil {
+			return false, err.Error()
+		}
+		return true, ""
+	})
+	assertControllerRef(t, replenisher, awsNodeManager)
+	assertControllerRef(t, refresher, awsNodeManager)
+
+	eventually(t, "NodeManager to reflect worker nodes", func() (bool, string) {
+		current := &operatorv1alpha1.NodeManager{}
+		if err := k8sClient.Get(ctx, key(nodeManager.Name), current); err != nil {
+			return false, err.Error()
+		}
+		if current.Status.WorkerAWSNodeManager == nil || current.Status.WorkerAWSNodeManager.Name != awsNodeManager.Name {
+			return false, fmt.Sprintf("workerAWSNodeManager is %v", current.Status.WorkerAWSNodeManager)
+		}
+		return len(current.Status.WorkerNodes) == 2, fmt.Sprintf("workerNodes are %v", current.Status.WorkerNodes)
+	})
+}
+
+func TestPhaseTransitions(t *testing.T) {
+	requireEnvtest(t)
+	ctx := context.Background()
+	nodeManager := createNodeManager(t, "phases", 2, 2)
+	name := nodeManager.Name + "-worker"
+
+	eventually(t, "AWSNodeManager to be synced", func() (bool, string) {
+		current := &operatorv1alpha1.AWSNodeManager{}
+		if err := k8sClient.Get(ctx, key(name), current); err != nil {
+			return false, err.Error()
+		}
+		if current.Status.Phase != operatorv1alpha1.AWSNodeManagerSynced || len(current.Status.AWSNodes) != 2 {
+			return false, fmt.Sprintf("phase is %s with %d nodes", current.Status.Phase, len(current.Status.AWSNodes))
+		}
+		groups := current.Status.AutoScalingGroups
+		return len(groups) == 1 && groups[0].Name == name, fmt.Sprintf("AutoScalingGroups are %v", groups)
+	})
+	eventually(t, "AWSNodeReplenisher to be synced", func() (bool, string) {
+		current := &operatorv1alpha1.AWSNodeReplenisher{}
+		if err := k8sClient.Get(ctx, key(name), current); err != nil {
+			return false, err.Error()
+		}
+		return current.Status.Phase == operatorv1alpha1.AWSNodeReplenisherSynced, fmt.Sprintf("phase is %s", current.Status.Phase)
+	})
+	eventually(t, "AWSNodeRefresher to be scheduled", func() (bool, string) {
+		current := &operatorv1alpha1.AWSNodeRefresher{}
+		if err := k8sClient.Get(ctx, key(name), current); err != nil {
+			return false, err.Error()
+		}
+		return current.Status.Phase == operatorv1alpha1.AWSNodeRefresherScheduled, fmt.Sprintf("phase is %s", current.Status.Phase)
+	})
+}
+
+func TestSpecPropagation(t *testing.T) {
+	requireEnvtest(t)
+	ctx := context.Background()
+	nodeManager := createNodeManager(t, "propagation", 1, 1)
+	name := nodeManager.Name + "-worker"
+
+	eventually(t, "AWSNodeReplenisher", func() (bool, string) {
+		if err := k8sClient.Get(ctx, key(name), &operatorv1alpha1.AWSNodeReplenisher{}); err != nil {
+			return false, err.Error()
+		}
+		return true, ""
+	})
+	if err := k8sClient.Get(ctx, key(nodeManager.Name), nodeManager); err != nil {
+		t.Fatal(err)
+	}
+	nodeManager.Spec.Aws.Workers.Desired = 3
+	if err := k8sClient.Update(ctx, nodeManager); err != nil {
+		t.Fatalf("failed to update NodeManager: %v", err)
+	}
+
+	eventually(t, "desired to be propagated", func() (bool, string) {
+		awsNodeManager := &operatorv1alpha1.AWSNodeManager{}
+		if err := k8sClient.Get(ctx, key(name), awsNodeManager); err != nil {
+			return false, err.Error()
+		}
+		replenisher := &operatorv1alpha1.AWSNodeReplenisher{}
+		if err := k8sClient.Get(ctx, key(name), replenisher); err != nil {
+			return false, err.Error()
+		}
+		return awsNodeManager.Spec.Desired == 3 && replenisher.Spec.Desired == 3,
+			fmt.Sprintf("desired of AWSNodeManager is %d, AWSNodeReplenisher is %d", awsNodeManager.Spec.Desired, replenisher.Spec.Desired)
+	})
+	eventually(t, "AutoScalingGroup to be replenished", func() (bool, string) {
+		ids := fakeCloud.InstanceIDs(name)
+		return len(ids) == 3, fmt.Sprintf("instances are %v", ids)
+	})
+	eventually(t, "new nodes to join", func() (bool, string) {
+		awsNodeManager := &operatorv1alpha1.AWSNodeManager{}
+		if err := k8sClient.Get(ctx, key(name), awsNodeManager); err != nil {
+			return false, err.Error()
+		}
+		replenisher := &operatorv1alpha1.AWSNodeReplenisher{}
+		if err := k8sClient.Get(ctx, key(name), replenisher); err != nil {
+			return false, err.Error()
+		}
+		return len(awsNodeManager.Status.AWSNodes) == 3 && replenisher.Status.Phase == operatorv1alpha1.AWSNodeReplenisherSynced,
+			fmt.Sprintf("AWSNodeManager has %d nodes, AWSNodeReplenisher is %s", len(awsNodeManager.Status.AWSNodes), replenisher.Status.Phase)
+	})
+}
+
+// TestDeletion checks the chain of owner references instead of the cascading deletion itself,
+// because envtest does not run the garbage collector of kube-controller-manager which follows them.
+func TestDeletion(t *testing.T) {
+	requireEnvtest(t)
+	ctx := context.Background()
+	nodeManager := createNodeManager(t, "deletion", 1, 1)
+	name := nodeManager.Name + "-worker"
+
+	awsNodeManager := &operatorv1alpha1.AWSNodeManager{}
+	eventually(t, "children", func() (bool, string) {
+		if err := k8sClient.Get(ctx, key(name), awsNodeManager); err != nil {
+			return false, err.Error()
+		}
+		if err := k8sClient.Get(ctx, key(name), &operatorv1alpha1.AWSNodeReplenisher{}); err != nil {
+			return false, err.Error()
+		}
+		return true, ""
+	})
+	assertControllerRef(t, awsNodeManager, nodeManager)
+
+	// A child which is deleted alone is created again by its owner.
+	uid := awsNodeManager.UID
+	if err := k8sClient.Delete(ctx, awsNodeManager); err != nil {
+		t.Fatalf("failed to delete AWSNodeManager: %v", err)
+	}
+	eventually(t, "AWSNodeManager to be recreated", func() (bool, string) {
+		current := &operatorv1alpha1.AWSNodeManager{}
+		if err := k8sClient.Get(ctx, key(name), current); err != nil {
+			return false, err.Error()
+		}
+		return current.UID != uid, "AWSNodeManager is not deleted yet"
+	})
+
+	if err := k8sClient.Delete(ctx, nodeManager); err != nil {
+		t.Fatalf("failed to delete NodeManager: %v", err)
+	}
+	eventually(t, "NodeManager to be deleted", func() (bool, string) {
+		err := k8sClient.Get(ctx, key(nodeManager.Name), &operatorv1alpha1.NodeManager{})
+		return apierrors.IsNotFound(err), fmt.Sprintf("returned %v", err)
+	})
+}
+
+// createNodeManager creates an AutoScalingGroup in the simulator and a NodeManager for its workers.
+// They are removed with the children at the end of the test, because NodeManager must be unique in the cluster.
+func createNodeManager(t *testing.T, name string, capacity int64, desired int32) *operatorv1alpha1.NodeManager {
+	t.Helper()
+	ctx := context.Background()
+	groupName := name + "-worker"
+	if err := fakeCloud.AddAutoScalingGroup(fake.AutoScalingGroup{
+		Name:              groupName,
+		MinSize:           0,
+		MaxSize:           5,
+		DesiredCapacity:   capacity,
+		AvailabilityZones: []string{testRegion + "a", testRegion + "b"},
+		NodeLabels:        map[string]string{nodemanager.NodeWorkerLabel: ""},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	nodeManager := &operatorv1alpha1.NodeManager{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+		},
+		Spec: operatorv1alpha1.NodeManagerSpec{
+			CloudProvider: operatorv1alpha1.CloudProviderAWS,
+			Aws: &operatorv1alpha1.CloudAWS{
+				Region: testRegion,
+				Workers: &operatorv1alpha1.Nodes{
+					AutoScalingGroups: []operatorv1alpha1.AutoScalingGroup{
+						{Name: groupName},
+					},
+					Desired: desired,
+					RefreshOptions: operatorv1alpha1.RefreshOptions{
+						ASGModifyCoolTimeSeconds: 0,
+						RefreshSchedule:          "0 0 1 1 *",
+					},
+					EnableReplenish: true,
+				},
+			},
+		},
+	}
+	if err := k8sClient.Create(ctx, nodeManager); err != nil {
+		t.Fatalf("failed to create NodeManager: %v", err)
+	}
+	t.Cleanup(func() {
+		for _, obj := range []client.Object{
+			&operatorv1alpha1.NodeManager{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace}},
+			&operatorv1alpha1.AWSNodeReplenisher{ObjectMeta: metav1.ObjectMeta{Name: groupName, Namespace: testNamespace}},
+			&operatorv1alpha1.AWSNodeRefresher{ObjectMeta: metav1.ObjectMeta{Name: groupName, Namespace: testNamespace}},
+			&operatorv1alpha1.AWSNodeManager{ObjectMeta: metav1.ObjectMeta{Name: groupName, Namespace: testNamespace}},
+		} {
+			if err := k8sClient.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+				t.Errorf("failed to delete %s: %v", obj.GetName(), err)
+			}
+		}
+		// Terminating instances removes their nodes, so they are not counted by following tests.
+		if _, err := fakeCloud.AutoScaling().UpdateAutoScalingGroup(&autoscaling.UpdateAutoScalingGroupInput{
+			AutoScalingGroupName: aws.String(groupName),
+			DesiredCapacity:      aws.Int64(0),
+		}); err != nil {
+			t.Errorf("failed to scale in %s: %v", groupName, err)
+		}
+	})
+	return nodeManager
+}
+
+func assertControllerRef(t *testing.T, obj, owner client.Object) {
+	t.Helper()
+	ref := metav1.GetControllerOf(obj)
+	if ref == nil {
+		t.Fatalf("%s should have a controller reference", obj.GetName())
+	}
+	if ref.UID != owner.GetUID() || ref.Name != owner.GetName() {
+		t.Errorf("%s should be owned by %s(%s), but owned by %s(%s)", obj.GetName(), owner.GetName(), owner.GetUID(), ref.Name, ref.UID)
+	}
+	if ref.BlockOwnerDeletion == nil || !*ref.BlockOwnerDeletion {
+		t.Errorf("%s should block deletion of the owner until it is deleted", obj.GetName())
+	}
+}
+
+func key(name string) types.NamespacedName {
+	return types.NamespacedName{Namespace: testNamespace, Name: name}
+}
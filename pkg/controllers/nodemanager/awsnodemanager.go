@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 
 	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
 	"github.com/h3poteto/node-manager/pkg/util/klog"
@@ -15,13 +16,13 @@ import (
 func (r *NodeManagerReconciler) syncAWSNodeManager(ctx context.Context, nodeManager *operatorv1alpha1.NodeManager, masterNodes, workerNodes []*corev1.Node) (*operatorv1alpha1.AWSNodeManager, *operatorv1alpha1.AWSNodeManager, error) {
 	var masterNodeManager, workerNodeManager *operatorv1alpha1.AWSNodeManager
 	var err error
-	if nodeManager.Spec.Aws.Masters != nil {
+	if definesRole(nodeManager, operatorv1alpha1.Master) {
 		masterNodeManager, err = r.syncMasterAWSNodeManager(ctx, nodeManager, masterNodes)
 		if err != nil {
 			return nil, nil, err
 		}
 	}
-	if nodeManager.Spec.Aws.Workers != nil {
+	if definesRole(nodeManager, operatorv1alpha1.Worker) {
 		workerNodeManager, err = r.syncWorkerAWSNodeManager(ctx, nodeManager, workerNodes)
 		if err != nil {
 			return nil, nil, err
@@ -40,6 +41,7 @@ func (r *NodeManagerReconciler) createAWSNodeManager(ctx context.Context, nodeMa
 			node := nodes[i]
 			a := operatorv1alpha1.AWSNode{
 				Name:              node.Name,
+				ProviderID:        node.Spec.ProviderID,
 				CreationTimestamp: node.CreationTimestamp,
 			}
 			newMasterManager.Status.AWSNodes = append(newMasterManager.Status.AWSNodes, a)
@@ -57,6 +59,7 @@ func (r *NodeManagerReconciler) createAWSNodeManager(ctx context.Context, nodeMa
 			node := nodes[i]
 			a := operatorv1alpha1.AWSNode{
 				Name:              node.Name,
+				ProviderID:        node.Spec.ProviderID,
 				CreationTimestamp: node.CreationTimestamp,
 			}
 			newWorkerManager.Status.AWSNodes = append(newWorkerManager.Status.AWSNodes, a)
@@ -77,14 +80,17 @@ func (r *NodeManagerReconciler) updateAWSNodeManager(ctx context.Context, existi
 	switch role {
 	case operatorv1alpha1.Master:
 		newMasterManager := generateMasterAWSNodeManager(nodeManager)
-		var currentNames, nodeNames []string
+		var currentNames, nodeNames, currentProviderIDs, nodeProviderIDs []string
 		for _, node := range existing.Status.AWSNodes {
 			currentNames = append(currentNames, node.Name)
+			currentProviderIDs = append(currentProviderIDs, node.ProviderID)
 		}
 		for _, node := range nodes {
 			nodeNames = append(nodeNames, node.Name)
+			nodeProviderIDs = append(nodeProviderIDs, node.Spec.ProviderID)
 		}
-		if reflect.DeepEqual(existing.Spec, newMasterManager.Spec) && reflect.DeepEqual(currentNames, nodeNames) {
+		// ProviderID is set by the cloud controller manager after the node is registered, so it is compared as well as the name.
+		if reflect.DeepEqual(existing.Spec, newMasterManager.Spec) && reflect.DeepEqual(currentNames, nodeNames) && reflect.DeepEqual(currentProviderIDs, nodeProviderIDs) {
 			klog.Infof(ctx, "AWSNodeManager %s/%s is already synced", existing.Namespace, existing.Name)
 			return existing, nil
 		}
@@ -93,6 +99,7 @@ func (r *NodeManagerReconciler) updateAWSNodeManager(ctx context.Context, existi
 		for _, node := range nodes {
 			a := operatorv1alpha1.AWSNode{
 				Name:              node.Name,
+				ProviderID:        node.Spec.ProviderID,
 				CreationTimestamp: node.CreationTimestamp,
 			}
 			existing.Status.AWSNodes = append(existing.Status.AWSNodes, a)
@@ -107,14 +114,16 @@ func (r *NodeManagerReconciler) updateAWSNodeManager(ctx context.Context, existi
 		return existing, nil
 	case operatorv1alpha1.Worker:
 		newWorkerManager := generateWorkerAWSNodeManager(nodeManager)
-		var currentNames, nodeNames []string
+		var currentNames, nodeNames, currentProviderIDs, nodeProviderIDs []string
 		for _, node := range existing.Status.AWSNodes {
 			currentNames = append(currentNames, node.Name)
+			currentProviderIDs = append(currentProviderIDs, node.ProviderID)
 		}
 		for _, node := range nodes {
 			nodeNames = append(nodeNames, node.Name)
+			nodeProviderIDs = append(nodeProviderIDs, node.Spec.ProviderID)
 		}
-		if reflect.DeepEqual(existing.Spec, newWorkerManager.Spec) && reflect.DeepEqual(currentNames, nodeNames) {
+		if reflect.DeepEqual(existing.Spec, newWorkerManager.Spec) && reflect.DeepEqual(currentNames, nodeNames) && reflect.DeepEqual(currentProviderIDs, nodeProviderIDs) {
 			klog.Infof(ctx, "AWSNodeManager %s/%s is already synced", existing.Namespace, existing.Name)
 			return existing, nil
 		}
@@ -123,6 +132,7 @@ func (r *NodeManagerReconciler) updateAWSNodeManager(ctx context.Context, existi
 		for _, node := range nodes {
 			a := operatorv1alpha1.AWSNode{
 				Name:              node.Name,
+				ProviderID:        node.Spec.ProviderID,
 				CreationTimestamp: node.CreationTimestamp,
 			}
 			existing.Status.AWSNodes = append(existing.Status.AWSNodes, a)
@@ -151,17 +161,7 @@ func generateMasterAWSNodeManager(nodeManager *operatorv1alpha1.NodeManager) *op
 				*metav1.NewControllerRef(nodeManager, nodeManager.GroupVersionKind()),
 			},
 		},
-		Spec: operatorv1alpha1.AWSNodeManagerSpec{
-			Region:                   nodeManager.Spec.Aws.Region,
-			AutoScalingGroups:        nodeManager.Spec.Aws.Masters.AutoScalingGroups,
-			ASGModifyCoolTimeSeconds: nodeManager.Spec.Aws.Masters.ASGModifyCoolTimeSeconds,
-			DrainGracePeriodSeconds:  nodeManager.Spec.Aws.Masters.DrainGracePeriodSeconds,
-			Desired:                  nodeManager.Spec.Aws.Masters.Desired,
-			Role:                     operatorv1alpha1.Master,
-			EnableReplenish:          nodeManager.Spec.Aws.Masters.EnableReplenish,
-			RefreshSchedule:          nodeManager.Spec.Aws.Masters.RefreshSchedule,
-			SurplusNodes:             nodeManager.Spec.Aws.Masters.SurplusNodes,
-		},
+		Spec: generateAWSNodeManagerSpec(nodeManager, operatorv1alpha1.Master),
 		Status: operatorv1alpha1.AWSNodeManagerStatus{
 			Phase: operatorv1alpha1.AWSNodeManagerInit,
 		},
@@ -179,19 +179,167 @@ func generateWorkerAWSNodeManager(nodeManager *operatorv1alpha1.NodeManager) *op
 				*metav1.NewControllerRef(nodeManager, nodeManager.GroupVersionKind()),
 			},
 		},
-		Spec: operatorv1alpha1.AWSNodeManagerSpec{
-			Region:                   nodeManager.Spec.Aws.Region,
-			AutoScalingGroups:        nodeManager.Spec.Aws.Workers.AutoScalingGroups,
-			ASGModifyCoolTimeSeconds: nodeManager.Spec.Aws.Workers.ASGModifyCoolTimeSeconds,
-			DrainGracePeriodSeconds:  nodeManager.Spec.Aws.Workers.DrainGracePeriodSeconds,
-			Desired:                  nodeManager.Spec.Aws.Workers.Desired,
-			Role:                     operatorv1alpha1.Worker,
-			EnableReplenish:          nodeManager.Spec.Aws.Workers.EnableReplenish,
-			RefreshSchedule:          nodeManager.Spec.Aws.Workers.RefreshSchedule,
-			SurplusNodes:             nodeManager.Spec.Aws.Workers.SurplusNodes,
-		},
+		Spec: generateAWSNodeManagerSpec(nodeManager, operatorv1alpha1.Worker),
 		Status: operatorv1alpha1.AWSNodeManagerStatus{
 			Phase: operatorv1alpha1.AWSNodeManagerInit,
 		},
 	}
 }
+
+// definesRole returns true when the cloud provider block of NodeManager has node groups for the role.
+func definesRole(nodeManager *operatorv1alpha1.NodeManager, role operatorv1alpha1.NodeRole) bool {
+	switch nodeManager.Spec.CloudProvider {
+	case operatorv1alpha1.CloudProviderGCP:
+		if role == operatorv1alpha1.Master {
+			return nodeManager.Spec.GCP.Masters != nil
+		}
+		return nodeManager.Spec.GCP.Workers != nil
+	case operatorv1alpha1.CloudProviderAzure:
+		if role == operatorv1alpha1.Master {
+			return nodeManager.Spec.Azure.Masters != nil
+		}
+		return nodeManager.Spec.Azure.Workers != nil
+	case operatorv1alpha1.CloudProviderClusterAPI:
+		// Control plane machines are not owned by MachineDeployments.
+		if role == operatorv1alpha1.Master {
+			return false
+		}
+		return nodeManager.Spec.ClusterAPI.Workers != nil
+	default:
+		if role == operatorv1alpha1.Master {
+			return nodeManager.Spec.Aws.Masters != nil
+		}
+		return nodeManager.Spec.Aws.Workers != nil
+	}
+}
+
+// generateAWSNodeManagerSpec converts node groups for the role in the cloud provider block to AWSNodeManagerSpec.
+// AWSNodeManager handles node groups of all cloud providers, so the cloud provider is specified in CloudTarget.
+func generateAWSNodeManagerSpec(nodeManager *operatorv1alpha1.NodeManager, role operatorv1alpha1.NodeRole) operatorv1alpha1.AWSNodeManagerSpec {
+	var spec operatorv1alpha1.AWSNodeManagerSpec
+	var options operatorv1alpha1.RefreshOptions
+	switch nodeManager.Spec.CloudProvider {
+	case operatorv1alpha1.CloudProviderGCP:
+		gcp := nodeManager.Spec.GCP
+		nodes := gcp.Workers
+		if role == operatorv1alpha1.Master {
+			nodes = gcp.Masters
+		}
+		var groups []operatorv1alpha1.AutoScalingGroup
+		for _, group := range nodes.InstanceGroups {
+			groups = append(groups, operatorv1alpha1.AutoScalingGroup{Name: group.Name})
+		}
+		spec = operatorv1alpha1.AWSNodeManagerSpec{
+			CloudTarget: operatorv1alpha1.CloudTarget{
+				CloudProvider: operatorv1alpha1.CloudProviderGCP,
+				GCP: &operatorv1alpha1.GCPTarget{
+					Project: gcp.Project,
+					Zone:    gcp.Zone,
+				},
+			},
+			Region:            regionOfZone(gcp.Zone),
+			AutoScalingGroups: groups,
+			Desired:           nodes.Desired,
+			EnableReplenish:   nodes.EnableReplenish,
+		}
+		options = nodes.RefreshOptions
+	case operatorv1alpha1.CloudProviderAzure:
+		azure := nodeManager.Spec.Azure
+		nodes := azure.Workers
+		if role == operatorv1alpha1.Master {
+			nodes = azure.Masters
+		}
+		var groups []operatorv1alpha1.AutoScalingGroup
+		for _, scaleSet := range nodes.ScaleSets {
+			groups = append(groups, operatorv1alpha1.AutoScalingGroup{Name: scaleSet.Name})
+		}
+		spec = operatorv1alpha1.AWSNodeManagerSpec{
+			CloudTarget: operatorv1alpha1.CloudTarget{
+				CloudProvider: operatorv1alpha1.CloudProviderAzure,
+				Azure: &operatorv1alpha1.AzureTarget{
+					SubscriptionID: azure.SubscriptionID,
+					ResourceGroup:  azure.ResourceGroup,
+				},
+			},
+			AutoScalingGroups: groups,
+			Desired:           nodes.Desired,
+			EnableReplenish:   nodes.EnableReplenish,
+		}
+		options = nodes.RefreshOptions
+	case operatorv1alpha1.CloudProviderClusterAPI:
+		capi := nodeManager.Spec.ClusterAPI
+		nodes := capi.Workers
+		var groups []operatorv1alpha1.AutoScalingGroup
+		for _, md := range nodes.MachineDeployments {
+			groups = append(groups, operatorv1alpha1.AutoScalingGroup{Name: md.Name})
+		}
+		spec = operatorv1alpha1.AWSNodeManagerSpec{
+			CloudTarget: operatorv1alpha1.CloudTarget{
+				CloudProvider: operatorv1alpha1.CloudProviderClusterAPI,
+				ClusterAPI: &operatorv1alpha1.ClusterAPITarget{
+					Namespace: capi.Namespace,
+				},
+			},
+			AutoScalingGroups: groups,
+			Desired:           nodes.Desired,
+			EnableReplenish:   nodes.EnableReplenish,
+		}
+		options = nodes.RefreshOptions
+	default:
+		nodes := nodeManager.Spec.Aws.Workers
+		if role == operatorv1alpha1.Master {
+			nodes = nodeManager.Spec.Aws.Masters
+		}
+		target := operatorv1alpha1.CloudTarget{
+			CloudProvider: operatorv1alpha1.CloudProviderAWS,
+		}
+		if nodeManager.Spec.Aws.RoleARN != "" || nodeManager.Spec.Aws.Endpoint != "" {
+			target.AWS = &operatorv1alpha1.AWSTarget{
+				RoleARN:    nodeManager.Spec.Aws.RoleARN,
+				ExternalID: nodeManager.Spec.Aws.ExternalID,
+				Endpoint:   nodeManager.Spec.Aws.Endpoint,
+			}
+		}
+		spec = operatorv1alpha1.AWSNodeManagerSpec{
+			CloudTarget:       target,
+			Region:            nodeManager.Spec.Aws.Region,
+			AutoScalingGroups: nodes.AutoScalingGroups,
+			TagSelector:       nodes.TagSelector,
+			Desired:           nodes.Desired,
+			EnableReplenish:   nodes.EnableReplenish,
+			RefreshStrategy:   nodes.RefreshStrategy,
+			InstanceRefresh:   nodes.InstanceRefresh,
+			RefreshTrigger:    nodes.RefreshTrigger,
+			AMISource:         nodes.AMISource,
+		}
+		options = nodes.RefreshOptions
+	}
+	spec.Role = role
+	setRefreshOptions(&spec, &options)
+	return spec
+}
+
+// setRefreshOptions sets the options which are common to all cloud providers to the spec.
+func setRefreshOptions(spec *operatorv1alpha1.AWSNodeManagerSpec, options *operatorv1alpha1.RefreshOptions) {
+	spec.ASGModifyCoolTimeSeconds = options.ASGModifyCoolTimeSeconds
+	spec.RefreshSchedule = options.RefreshSchedule
+	spec.SurplusNodes = options.SurplusNodes
+	spec.DrainGracePeriodSeconds = options.DrainGracePeriodSeconds
+	spec.MaxNodeAge = options.MaxNodeAge
+	spec.MaintenanceWindow = options.MaintenanceWindow
+	spec.SuspendRefresh = options.SuspendRefresh
+	spec.RefreshNow = options.RefreshNow
+	spec.RefreshTimeouts = options.RefreshTimeouts
+	spec.MaxSurge = options.MaxSurge
+	spec.MaxUnavailable = options.MaxUnavailable
+	spec.ReplacementOrder = options.ReplacementOrder
+}
+
+// regionOfZone returns the region of the GCP zone, for example us-central1 of us-central1-a.
+func regionOfZone(zone string) string {
+	i := strings.LastIndex(zone, "-")
+	if i < 0 {
+		return zone
+	}
+	return zone[:i]
+}
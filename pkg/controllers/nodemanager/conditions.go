@@ -0,0 +1,101 @@
+package nodemanager
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+	"github.com/h3poteto/node-manager/pkg/util/conditions"
+	"github.com/h3poteto/node-manager/pkg/util/klog"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// updateConditions aggregates conditions of AWSNodeManagers and the result of sync to status conditions.
+func (r *NodeManagerReconciler) updateConditions(ctx context.Context, nodeManager *operatorv1alpha1.NodeManager, syncErr error) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := operatorv1alpha1.NodeManager{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: nodeManager.Namespace, Name: nodeManager.Name}, &current); err != nil {
+			klog.Errorf(ctx, "failed to get NodeManager %s/%s: %v", nodeManager.Namespace, nodeManager.Name, err)
+			return err
+		}
+		children, err := r.childAWSNodeManagers(ctx, &current)
+		if err != nil {
+			return err
+		}
+		before := current.Status.DeepCopy()
+		setConditions(&current, children, syncErr)
+		if reflect.DeepEqual(before.Conditions, current.Status.Conditions) {
+			return nil
+		}
+		if err := r.Client.Update(ctx, &current); err != nil {
+			klog.Errorf(ctx, "failed to update NodeManager conditions %s/%s: %v", current.Namespace, current.Name, err)
+			return err
+		}
+		return nil
+	})
+}
+
+func (r *NodeManagerReconciler) childAWSNodeManagers(ctx context.Context, nodeManager *operatorv1alpha1.NodeManager) ([]*operatorv1alpha1.AWSNodeManager, error) {
+	var children []*operatorv1alpha1.AWSNodeManager
+	for _, ref := range []*operatorv1alpha1.AWSNodeManagerRef{nodeManager.Status.MasterAWSNodeManager, nodeManager.Status.WorkerAWSNodeManager} {
+		if ref == nil {
+			continue
+		}
+		child := operatorv1alpha1.AWSNodeManager{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, &child); err != nil {
+			klog.Errorf(ctx, "failed to get AWSNodeManager %s/%s: %v", ref.Namespace, ref.Name, err)
+			return nil, client.IgnoreNotFound(err)
+		}
+		children = append(children, &child)
+	}
+	return children, nil
+}
+
+func setConditions(nodeManager *operatorv1alpha1.NodeManager, children []*operatorv1alpha1.AWSNodeManager, syncErr error) {
+	generation := nodeManager.Generation
+	c := &nodeManager.Status.Conditions
+
+	var notReady, refreshing, replenishing, degraded []string
+	for _, child := range children {
+		if !meta.IsStatusConditionTrue(child.Status.Conditions, operatorv1alpha1.ConditionReady) {
+			notReady = append(notReady, child.Name)
+		}
+		if meta.IsStatusConditionTrue(child.Status.Conditions, operatorv1alpha1.ConditionRefreshing) {
+			refreshing = append(refreshing, child.Name)
+		}
+		if meta.IsStatusConditionTrue(child.Status.Conditions, operatorv1alpha1.ConditionReplenishing) {
+			replenishing = append(replenishing, child.Name)
+		}
+		if meta.IsStatusConditionTrue(child.Status.Conditions, operatorv1alpha1.ConditionDegraded) {
+			degraded = append(degraded, child.Name)
+		}
+	}
+
+	switch {
+	case len(children) == 0:
+		conditions.Set(c, generation, operatorv1alpha1.ConditionReady, metav1.ConditionFalse, operatorv1alpha1.ReasonNotFound, "AWSNodeManagers are not created yet")
+	case len(notReady) > 0:
+		conditions.Set(c, generation, operatorv1alpha1.ConditionReady, metav1.ConditionFalse, "AWSNodeManagerNotReady", fmt.Sprintf("AWSNodeManagers are not ready: %s", strings.Join(notReady, ", ")))
+	default:
+		conditions.Set(c, generation, operatorv1alpha1.ConditionReady, metav1.ConditionTrue, operatorv1alpha1.ReasonSynced, "All AWSNodeManagers are ready")
+	}
+	if len(refreshing) > 0 {
+		conditions.Set(c, generation, operatorv1alpha1.ConditionRefreshing, metav1.ConditionTrue, operatorv1alpha1.ReasonRefreshing, fmt.Sprintf("AWSNodeManagers are refreshing: %s", strings.Join(refreshing, ", ")))
+	} else {
+		conditions.Set(c, generation, operatorv1alpha1.ConditionRefreshing, metav1.ConditionFalse, operatorv1alpha1.ReasonIdle, "")
+	}
+	if len(replenishing) > 0 {
+		conditions.Set(c, generation, operatorv1alpha1.ConditionReplenishing, metav1.ConditionTrue, operatorv1alpha1.ReasonReplenishing, fmt.Sprintf("AWSNodeManagers are replenishing: %s", strings.Join(replenishing, ", ")))
+	} else {
+		conditions.Set(c, generation, operatorv1alpha1.ConditionReplenishing, metav1.ConditionFalse, operatorv1alpha1.ReasonIdle, "")
+	}
+	conditions.SetSyncResult(c, generation, syncErr)
+	if syncErr == nil && len(degraded) > 0 {
+		conditions.Set(c, generation, operatorv1alpha1.ConditionDegraded, metav1.ConditionTrue, "AWSNodeManagerDegraded", fmt.Sprintf("AWSNodeManagers are degraded: %s", strings.Join(degraded, ", ")))
+	}
+}
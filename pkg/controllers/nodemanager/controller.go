@@ -69,11 +69,17 @@ func (r *NodeManagerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	// So, the request can contains both of them.
 	klog.Infof(ctx, "fetching NodeManager resources: %s", req.NamespacedName.Name)
 	if err := r.Client.Get(ctx, req.NamespacedName, &nodeManager); err == nil {
-		err := r.syncNodeManager(ctx, &nodeManager)
-		if err != nil {
-			r.Recorder.Eventf(&nodeManager, corev1.EventTypeWarning, "Error", "Failed to sync: %v", err)
+		syncErr := r.syncNodeManager(ctx, &nodeManager)
+		if syncErr != nil {
+			r.Recorder.Eventf(&nodeManager, corev1.EventTypeWarning, "Error", "Failed to sync: %v", syncErr)
 		}
-		return ctrl.Result{}, err
+		if err := r.updateConditions(ctx, &nodeManager, syncErr); err != nil {
+			klog.Errorf(ctx, "failed to update conditions of NodeManager: %v", err)
+			if syncErr == nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, syncErr
 	}
 	if err := r.syncNode(ctx, req.NamespacedName); err != nil {
 		return ctrl.Result{}, client.IgnoreNotFound(err)
@@ -117,44 +123,63 @@ func (r *NodeManagerReconciler) syncNodeManager(ctx context.Context, nodeManager
 	}
 
 	switch nodeManager.Spec.CloudProvider {
-	case "aws":
+	case operatorv1alpha1.CloudProviderAWS:
 		if nodeManager.Spec.Aws == nil {
 			err := errors.New("please specify spec.aws when cloudProvider is aws")
 			klog.Error(ctx, err)
 			return err
 		}
-		masterManager, workerManager, err := r.syncAWSNodeManager(ctx, nodeManager, masterNodes, workerNodes)
-		if err != nil {
+	case operatorv1alpha1.CloudProviderGCP:
+		if nodeManager.Spec.GCP == nil {
+			err := errors.New("please specify spec.gcp when cloudProvider is gcp")
+			klog.Error(ctx, err)
 			return err
 		}
-		newStatus := nodeManager.Status.DeepCopy()
-		if masterManager != nil {
-			newStatus.MasterAWSNodeManager = &operatorv1alpha1.AWSNodeManagerRef{
-				Namespace: masterManager.Namespace,
-				Name:      masterManager.Name,
-			}
-		}
-		if workerManager != nil {
-			newStatus.WorkerAWSNodeManager = &operatorv1alpha1.AWSNodeManagerRef{
-				Namespace: workerManager.Namespace,
-				Name:      workerManager.Name,
-			}
-		}
-
-		if reflect.DeepEqual(nodeManager.Status, newStatus) {
-			klog.Infof(ctx, "NodeManager %s/%s is already synced", nodeManager.Namespace, nodeManager.Name)
-			return nil
+	case operatorv1alpha1.CloudProviderAzure:
+		if nodeManager.Spec.Azure == nil {
+			err := errors.New("please specify spec.azure when cloudProvider is azure")
+			klog.Error(ctx, err)
+			return err
 		}
-		nodeManager.Status = *newStatus
-		if err := r.Client.Update(ctx, nodeManager); err != nil {
-			klog.Errorf(ctx, "failed to update nodeManager %q/%q: %v", nodeManager.Namespace, nodeManager.Name, err)
+	case operatorv1alpha1.CloudProviderClusterAPI:
+		if nodeManager.Spec.ClusterAPI == nil {
+			err := errors.New("please specify spec.clusterAPI when cloudProvider is clusterapi")
+			klog.Error(ctx, err)
 			return err
 		}
-		r.Recorder.Eventf(nodeManager, corev1.EventTypeNormal, "Updated", "Updated NodeManager %s/%s", nodeManager.Namespace, nodeManager.Name)
-		klog.Infof(ctx, "updated NodeManager status %q/%q", nodeManager.Namespace, nodeManager.Name)
-		return nil
 	default:
 		klog.Info(ctx, "could not find cloud provider in NodeManager resource")
 		return nil
 	}
+
+	masterManager, workerManager, err := r.syncAWSNodeManager(ctx, nodeManager, masterNodes, workerNodes)
+	if err != nil {
+		return err
+	}
+	newStatus := nodeManager.Status.DeepCopy()
+	if masterManager != nil {
+		newStatus.MasterAWSNodeManager = &operatorv1alpha1.AWSNodeManagerRef{
+			Namespace: masterManager.Namespace,
+			Name:      masterManager.Name,
+		}
+	}
+	if workerManager != nil {
+		newStatus.WorkerAWSNodeManager = &operatorv1alpha1.AWSNodeManagerRef{
+			Namespace: workerManager.Namespace,
+			Name:      workerManager.Name,
+		}
+	}
+
+	if reflect.DeepEqual(nodeManager.Status, newStatus) {
+		klog.Infof(ctx, "NodeManager %s/%s is already synced", nodeManager.Namespace, nodeManager.Name)
+		return nil
+	}
+	nodeManager.Status = *newStatus
+	if err := r.Client.Update(ctx, nodeManager); err != nil {
+		klog.Errorf(ctx, "failed to update nodeManager %q/%q: %v", nodeManager.Namespace, nodeManager.Name, err)
+		return err
+	}
+	r.Recorder.Eventf(nodeManager, corev1.EventTypeNormal, "Updated", "Updated NodeManager %s/%s", nodeManager.Namespace, nodeManager.Name)
+	klog.Infof(ctx, "updated NodeManager status %q/%q", nodeManager.Namespace, nodeManager.Name)
+	return nil
 }
@@ -0,0 +1,178 @@
+package controllers_test
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+	"github.com/h3poteto/node-manager/pkg/cloud"
+	cloudaws "github.com/h3poteto/node-manager/pkg/cloud/aws"
+	"github.com/h3poteto/node-manager/pkg/cloud/aws/fake"
+	"github.com/h3poteto/node-manager/pkg/controllers/awsnodemanager"
+	"github.com/h3poteto/node-manager/pkg/controllers/awsnoderefresher"
+	"github.com/h3poteto/node-manager/pkg/controllers/awsnodereplenisher"
+	"github.com/h3poteto/node-manager/pkg/controllers/nodemanager"
+)
+
+const (
+	testRegion    = "us-east-1"
+	testNamespace = "default"
+	// eventuallyTimeout is longer than the period of external event watchers, which resync AWSNodeManager every minute.
+	eventuallyTimeout = 2 * time.Minute
+	pollInterval      = 500 * time.Millisecond
+)
+
+var (
+	// k8sClient and fakeCloud are nil when envtest binaries are not installed, and then tests are skipped.
+	k8sClient client.Client
+	fakeCloud *fake.Cloud
+)
+
+// TestMain starts kube-apiserver and etcd with envtest, and runs all reconcilers like main.go against the AWS simulator.
+// It requires binaries of envtest, so tests are skipped when KUBEBUILDER_ASSETS is not set.
+// In CI they must not be skipped silently, so it fails instead.
+func TestMain(m *testing.M) {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		if os.Getenv("CI") != "" {
+			log.Fatal("KUBEBUILDER_ASSETS is not set in CI, so integration tests can not run. Install envtest binaries with setup-envtest")
+		}
+		log.Print("WARNING: KUBEBUILDER_ASSETS is not set, so integration tests are skipped. Run make test to run them")
+		os.Exit(m.Run())
+	}
+	os.Exit(runSuite(m))
+}
+
+func runSuite(m *testing.M) int {
+	testEnv := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "config", "crd", "bases")},
+		ErrorIfCRDPathMissing: true,
+	}
+	cfg, err := testEnv.Start()
+	if err != nil {
+		log.Printf("failed to start envtest: %v", err)
+		return 1
+	}
+	defer func() {
+		if err := testEnv.Stop(); err != nil {
+			log.Printf("failed to stop envtest: %v", err)
+		}
+	}()
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = operatorv1alpha1.AddToScheme(scheme)
+
+	k8sClient, err = client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		log.Printf("failed to create client: %v", err)
+		return 1
+	}
+	fakeCloud = fake.New(testRegion)
+	fakeCloud.RegisterNodes(k8sClient)
+
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme: scheme,
+		Metrics: server.Options{
+			BindAddress: "0",
+		},
+	})
+	if err != nil {
+		log.Printf("failed to create manager: %v", err)
+		return 1
+	}
+	if err := setupReconcilers(mgr); err != nil {
+		log.Printf("failed to setup reconcilers: %v", err)
+		return 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- mgr.Start(ctx)
+	}()
+	code := m.Run()
+	cancel()
+	if err := <-done; err != nil {
+		log.Printf("problem running manager: %v", err)
+	}
+	return code
+}
+
+func setupReconcilers(mgr ctrl.Manager) error {
+	providers := cloud.NewRegistry()
+	providers.Register(operatorv1alpha1.CloudProviderAWS, func(config cloud.Config) (cloud.Provider, error) {
+		return &cloudaws.AWS{
+			EC2:         fakeCloud.EC2(),
+			Autoscaling: fakeCloud.AutoScaling(),
+			SSM:         fakeCloud.SSM(),
+		}, nil
+	})
+
+	if err := (&nodemanager.NodeManagerReconciler{
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("NodeManager"),
+		Recorder: mgr.GetEventRecorderFor("node-manager"),
+		Scheme:   mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		return err
+	}
+	if err := (&awsnodemanager.AWSNodeManagerReconciler{
+		Client:    mgr.GetClient(),
+		Log:       ctrl.Log.WithName("controllers").WithName("AWSNodeManager"),
+		Recorder:  mgr.GetEventRecorderFor("aws-node-manager"),
+		Scheme:    mgr.GetScheme(),
+		Providers: providers,
+	}).SetupWithManager(mgr); err != nil {
+		return err
+	}
+	if err := (&awsnodereplenisher.AWSNodeReplenisherReconciler{
+		Client:    mgr.GetClient(),
+		Log:       ctrl.Log.WithName("controllers").WithName("AWSNodeReplenisher"),
+		Recorder:  mgr.GetEventRecorderFor("aws-node-replenisher"),
+		Scheme:    mgr.GetScheme(),
+		Providers: providers,
+	}).SetupWithManager(mgr); err != nil {
+		return err
+	}
+	return (&awsnoderefresher.AWSNodeRefresherReconciler{
+		Client:    mgr.GetClient(),
+		Log:       ctrl.Log.WithName("controllers").WithName("AWSNodeRefresher"),
+		Recorder:  mgr.GetEventRecorderFor("aws-node-refresher"),
+		Scheme:    mgr.GetScheme(),
+		Providers: providers,
+	}).SetupWithManager(mgr)
+}
+
+func requireEnvtest(t *testing.T) {
+	t.Helper()
+	if k8sClient == nil {
+		t.Skip("KUBEBUILDER_ASSETS is not set")
+	}
+}
+
+// eventually polls the condition until it returns true, and fails the test with the last message on timeout.
+func eventually(t *testing.T, description string, condition func() (bool, string)) {
+	t.Helper()
+	deadline := time.Now().Add(eventuallyTimeout)
+	for {
+		ok, message := condition()
+		if ok {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s: %s", description, message)
+		}
+		time.Sleep(pollInterval)
+	}
+}
@@ -0,0 +1,69 @@
+package conditions
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+)
+
+// Set sets the condition with the given type, status, reason and message.
+func Set(conditions *[]metav1.Condition, generation int64, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// cloudAPIError is an error returned by cloud provider APIs, like awserr.Error.
+type cloudAPIError interface {
+	error
+	Code() string
+	Message() string
+}
+
+// SetSyncResult sets Degraded and CloudAPIError conditions according to the result of sync.
+// Ready condition is also set to false when the sync failed.
+func SetSyncResult(conditions *[]metav1.Condition, generation int64, err error) {
+	if err == nil {
+		Set(conditions, generation, operatorv1alpha1.ConditionDegraded, metav1.ConditionFalse, operatorv1alpha1.ReasonSynced, "")
+		Set(conditions, generation, operatorv1alpha1.ConditionCloudAPIError, metav1.ConditionFalse, operatorv1alpha1.ReasonSucceeded, "")
+		return
+	}
+	Set(conditions, generation, operatorv1alpha1.ConditionReady, metav1.ConditionFalse, operatorv1alpha1.ReasonSyncFailed, err.Error())
+	Set(conditions, generation, operatorv1alpha1.ConditionDegraded, metav1.ConditionTrue, operatorv1alpha1.ReasonSyncFailed, err.Error())
+	var apiErr cloudAPIError
+	if errors.As(err, &apiErr) {
+		Set(conditions, generation, operatorv1alpha1.ConditionCloudAPIError, metav1.ConditionTrue, Reason(apiErr.Code()), apiErr.Message())
+		return
+	}
+	Set(conditions, generation, operatorv1alpha1.ConditionCloudAPIError, metav1.ConditionFalse, operatorv1alpha1.ReasonSucceeded, "")
+}
+
+// Reason converts the value, like a phase or an error code, to a CamelCase condition reason.
+func Reason(value string) string {
+	var b strings.Builder
+	upper := true
+	for _, c := range value {
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+			upper = true
+			continue
+		}
+		if upper {
+			c = unicode.ToUpper(c)
+			upper = false
+		}
+		b.WriteRune(c)
+	}
+	if b.Len() == 0 {
+		return "Unknown"
+	}
+	return b.String()
+}
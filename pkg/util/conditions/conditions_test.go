@@ -0,0 +1,77 @@
+package conditions
+
+import (
+	"errors"
+	"log"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+)
+
+func TestSetSyncResult(t *testing.T) {
+	cases := []struct {
+		title            string
+		err              error
+		expectedDegraded metav1.ConditionStatus
+		expectedCloud    metav1.ConditionStatus
+		expectedReason   string
+	}{
+		{
+			title:            "Sync succeeded",
+			err:              nil,
+			expectedDegraded: metav1.ConditionFalse,
+			expectedCloud:    metav1.ConditionFalse,
+			expectedReason:   operatorv1alpha1.ReasonSucceeded,
+		},
+		{
+			title:            "Sync failed",
+			err:              errors.New("failed to update"),
+			expectedDegraded: metav1.ConditionTrue,
+			expectedCloud:    metav1.ConditionFalse,
+			expectedReason:   operatorv1alpha1.ReasonSucceeded,
+		},
+		{
+			title:            "Cloud API failed",
+			err:              awserr.New("Throttling", "Rate exceeded", nil),
+			expectedDegraded: metav1.ConditionTrue,
+			expectedCloud:    metav1.ConditionTrue,
+			expectedReason:   "Throttling",
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+
+		var conditions []metav1.Condition
+		SetSyncResult(&conditions, 3, c.err)
+		degraded := meta.FindStatusCondition(conditions, operatorv1alpha1.ConditionDegraded)
+		if degraded == nil || degraded.Status != c.expectedDegraded {
+			t.Errorf("CASE: %s : Degraded condition is not matched, expected %s, but returned %v", c.title, c.expectedDegraded, degraded)
+		}
+		cloud := meta.FindStatusCondition(conditions, operatorv1alpha1.ConditionCloudAPIError)
+		if cloud == nil || cloud.Status != c.expectedCloud || cloud.Reason != c.expectedReason {
+			t.Errorf("CASE: %s : CloudAPIError condition is not matched, expected %s/%s, but returned %v", c.title, c.expectedCloud, c.expectedReason, cloud)
+		}
+		if cloud != nil && cloud.ObservedGeneration != 3 {
+			t.Errorf("CASE: %s : ObservedGeneration is not matched: %d", c.title, cloud.ObservedGeneration)
+		}
+	}
+}
+
+func TestReason(t *testing.T) {
+	cases := map[string]string{
+		"awsWaiting":                 "AwsWaiting",
+		"increasing":                 "Increasing",
+		"InvalidInstanceID.NotFound": "InvalidInstanceIDNotFound",
+		"":                           "Unknown",
+	}
+	for value, expected := range cases {
+		if result := Reason(value); result != expected {
+			t.Errorf("Reason of %q is not matched, expected %s, but returned %s", value, expected, result)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package webhooks
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+)
+
+// +kubebuilder:webhook:path=/validate-operator-h3poteto-dev-v1alpha1-awsnodemanager,mutating=false,failurePolicy=fail,sideEffects=None,groups=operator.h3poteto.dev,resources=awsnodemanagers,verbs=create;update,versions=v1alpha1,name=vawsnodemanager.operator.h3poteto.dev,admissionReviewVersions=v1
+
+// AWSNodeManagerValidator validates AWSNodeManager resources.
+type AWSNodeManagerValidator struct{}
+
+var _ admission.Validator[*operatorv1alpha1.AWSNodeManager] = &AWSNodeManagerValidator{}
+
+func SetupAWSNodeManagerWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &operatorv1alpha1.AWSNodeManager{}).
+		WithValidator(&AWSNodeManagerValidator{}).
+		Complete()
+}
+
+func (v *AWSNodeManagerValidator) ValidateCreate(ctx context.Context, obj *operatorv1alpha1.AWSNodeManager) (admission.Warnings, error) {
+	return desiredWarnings(field.NewPath("spec", "desired"), obj.Spec.Desired), validateAWSNodeManager(obj)
+}
+
+func (v *AWSNodeManagerValidator) ValidateUpdate(ctx context.Context, oldObj, newObj *operatorv1alpha1.AWSNodeManager) (admission.Warnings, error) {
+	var warnings admission.Warnings
+	if oldObj.Spec.Desired != newObj.Spec.Desired {
+		warnings = desiredWarnings(field.NewPath("spec", "desired"), newObj.Spec.Desired)
+	}
+	return warnings, validateAWSNodeManager(newObj)
+}
+
+func (v *AWSNodeManagerValidator) ValidateDelete(ctx context.Context, obj *operatorv1alpha1.AWSNodeManager) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateAWSNodeManager(manager *operatorv1alpha1.AWSNodeManager) error {
+	var errs field.ErrorList
+	spec := field.NewPath("spec")
+	errs = append(errs, validateCloudTarget(spec, manager.Spec.CloudTarget, manager.Spec.Region)...)
+	if manager.Spec.CloudProvider == "" || manager.Spec.CloudProvider == operatorv1alpha1.CloudProviderAWS {
+		errs = append(errs, validateAutoScalingGroupsOrTagSelector(spec, manager.Spec.AutoScalingGroups, manager.Spec.TagSelector)...)
+	} else {
+		if len(manager.Spec.TagSelector) > 0 {
+			errs = append(errs, field.Forbidden(spec.Child("tagSelector"), "tagSelector is supported only when cloudProvider is aws"))
+		}
+		errs = append(errs, validateAutoScalingGroups(spec.Child("autoScalingGroups"), manager.Spec.AutoScalingGroups)...)
+	}
+	errs = append(errs, validateNonNegative(spec.Child("desired"), int64(manager.Spec.Desired))...)
+	errs = append(errs, validateNonNegative(spec.Child("asgModifyCoolTimeSeconds"), manager.Spec.ASGModifyCoolTimeSeconds)...)
+	errs = append(errs, validateSchedule(spec.Child("refreshSchedule"), manager.Spec.RefreshSchedule, false)...)
+	errs = append(errs, validateNonNegative(spec.Child("surplusNodes"), manager.Spec.SurplusNodes)...)
+	errs = append(errs, validateNonNegative(spec.Child("drainGracePeriodSeconds"), manager.Spec.DrainGracePeriodSeconds)...)
+	errs = append(errs, validateRefreshStrategy(spec.Child("refreshStrategy"), spec.Child("instanceRefresh"), manager.Spec.RefreshStrategy, manager.Spec.InstanceRefresh, manager.Spec.CloudProvider)...)
+	errs = append(errs, validateRefreshTrigger(spec.Child("refreshTrigger"), manager.Spec.RefreshTrigger, manager.Spec.CloudProvider)...)
+	errs = append(errs, validateAMISource(spec.Child("amiSource"), manager.Spec.AMISource, manager.Spec.RefreshTrigger, manager.Spec.CloudProvider)...)
+	errs = append(errs, validateMaxNodeAge(spec.Child("maxNodeAge"), manager.Spec.MaxNodeAge, manager.Spec.RefreshTrigger, manager.Spec.AMISource, manager.Spec.RefreshStrategy)...)
+	errs = append(errs, validateMaintenanceWindow(spec.Child("maintenanceWindow"), manager.Spec.MaintenanceWindow, manager.Spec.RefreshSchedule)...)
+	errs = append(errs, validatePhaseTimeouts(spec.Child("refreshTimeouts"), manager.Spec.RefreshTimeouts)...)
+	errs = append(errs, validateSurge(spec, manager.Spec.MaxSurge, manager.Spec.MaxUnavailable, manager.Spec.SurplusNodes, manager.Spec.RefreshStrategy)...)
+	errs = append(errs, validateReplacementOrder(spec.Child("replacementOrder"), manager.Spec.ReplacementOrder, manager.Spec.CloudProvider, manager.Spec.RefreshStrategy)...)
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(operatorv1alpha1.GroupVersion.WithKind("AWSNodeManager").GroupKind(), manager.Name, errs)
+}
@@ -0,0 +1,72 @@
+package webhooks
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+)
+
+func TestAWSNodeManagerValidateUpdateWarnings(t *testing.T) {
+	cases := []struct {
+		title      string
+		oldDesired int32
+		newDesired int32
+		expected   int
+	}{
+		{
+			title:      "Desired is changed",
+			oldDesired: 3,
+			newDesired: 5,
+			expected:   1,
+		},
+		{
+			title:      "Desired is not changed",
+			oldDesired: 3,
+			newDesired: 3,
+			expected:   0,
+		},
+		{
+			title:      "Desired is changed to 0",
+			oldDesired: 3,
+			newDesired: 0,
+			expected:   0,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+
+		newManager := func(desired int32) *operatorv1alpha1.AWSNodeManager {
+			return &operatorv1alpha1.AWSNodeManager{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-manager",
+				},
+				Spec: operatorv1alpha1.AWSNodeManagerSpec{
+					Region: "us-east-1",
+					AutoScalingGroups: []operatorv1alpha1.AutoScalingGroup{
+						{
+							Name: "asg-a",
+						},
+					},
+					Desired:                  desired,
+					ASGModifyCoolTimeSeconds: 600,
+					Role:                     operatorv1alpha1.Worker,
+					SurplusNodes:             1,
+					DrainGracePeriodSeconds:  300,
+				},
+			}
+		}
+		v := &AWSNodeManagerValidator{}
+		warnings, err := v.ValidateUpdate(context.Background(), newManager(c.oldDesired), newManager(c.newDesired))
+		if err != nil {
+			t.Errorf("CASE: %s : manager should be valid, but returned %v", c.title, err)
+		}
+		if len(warnings) != c.expected {
+			t.Errorf("CASE: %s : warnings count is not matched, expected %d, but returned %v", c.title, c.expected, warnings)
+		}
+	}
+}
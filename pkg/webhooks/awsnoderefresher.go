@@ -0,0 +1,61 @@
+package webhooks
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+)
+
+// +kubebuilder:webhook:path=/validate-operator-h3poteto-dev-v1alpha1-awsnoderefresher,mutating=false,failurePolicy=fail,sideEffects=None,groups=operator.h3poteto.dev,resources=awsnoderefreshers,verbs=create;update,versions=v1alpha1,name=vawsnoderefresher.operator.h3poteto.dev,admissionReviewVersions=v1
+
+// AWSNodeRefresherValidator validates AWSNodeRefresher resources.
+type AWSNodeRefresherValidator struct{}
+
+var _ admission.Validator[*operatorv1alpha1.AWSNodeRefresher] = &AWSNodeRefresherValidator{}
+
+func SetupAWSNodeRefresherWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &operatorv1alpha1.AWSNodeRefresher{}).
+		WithValidator(&AWSNodeRefresherValidator{}).
+		Complete()
+}
+
+func (v *AWSNodeRefresherValidator) ValidateCreate(ctx context.Context, obj *operatorv1alpha1.AWSNodeRefresher) (admission.Warnings, error) {
+	return nil, validateAWSNodeRefresher(obj)
+}
+
+func (v *AWSNodeRefresherValidator) ValidateUpdate(ctx context.Context, oldObj, newObj *operatorv1alpha1.AWSNodeRefresher) (admission.Warnings, error) {
+	return nil, validateAWSNodeRefresher(newObj)
+}
+
+func (v *AWSNodeRefresherValidator) ValidateDelete(ctx context.Context, obj *operatorv1alpha1.AWSNodeRefresher) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateAWSNodeRefresher(refresher *operatorv1alpha1.AWSNodeRefresher) error {
+	var errs field.ErrorList
+	spec := field.NewPath("spec")
+	errs = append(errs, validateCloudTarget(spec, refresher.Spec.CloudTarget, refresher.Spec.Region)...)
+	errs = append(errs, validateAutoScalingGroups(spec.Child("autoScalingGroups"), refresher.Spec.AutoScalingGroups)...)
+	errs = append(errs, validateNonNegative(spec.Child("desired"), int64(refresher.Spec.Desired))...)
+	errs = append(errs, validateNonNegative(spec.Child("asgModifyCoolTimeSeconds"), refresher.Spec.ASGModifyCoolTimeSeconds)...)
+	errs = append(errs, validateSchedule(spec.Child("schedule"), refresher.Spec.Schedule, refresher.Spec.Trigger != operatorv1alpha1.RefreshTriggerDrift && refresher.Spec.AMISource == nil && refresher.Spec.MaxNodeAge == nil && refresher.Spec.MaintenanceWindow == nil && refresher.GetAnnotations()[operatorv1alpha1.RefreshNowAnnotation] == "")...)
+	errs = append(errs, validateNonNegative(spec.Child("surplusNodes"), refresher.Spec.SurplusNodes)...)
+	errs = append(errs, validateNonNegative(spec.Child("drainGracePeriodSeconds"), refresher.Spec.DrainGracePeriodSeconds)...)
+	errs = append(errs, validateRefreshStrategy(spec.Child("strategy"), spec.Child("instanceRefresh"), refresher.Spec.Strategy, refresher.Spec.InstanceRefresh, refresher.Spec.CloudProvider)...)
+	errs = append(errs, validateRefreshTrigger(spec.Child("trigger"), refresher.Spec.Trigger, refresher.Spec.CloudProvider)...)
+	errs = append(errs, validateAMISource(spec.Child("amiSource"), refresher.Spec.AMISource, refresher.Spec.Trigger, refresher.Spec.CloudProvider)...)
+	errs = append(errs, validateMaxNodeAge(spec.Child("maxNodeAge"), refresher.Spec.MaxNodeAge, refresher.Spec.Trigger, refresher.Spec.AMISource, refresher.Spec.Strategy)...)
+	errs = append(errs, validateMaintenanceWindow(spec.Child("maintenanceWindow"), refresher.Spec.MaintenanceWindow, refresher.Spec.Schedule)...)
+	errs = append(errs, validatePhaseTimeouts(spec.Child("timeouts"), refresher.Spec.Timeouts)...)
+	errs = append(errs, validateSurge(spec, refresher.Spec.MaxSurge, refresher.Spec.MaxUnavailable, refresher.Spec.SurplusNodes, refresher.Spec.Strategy)...)
+	errs = append(errs, validateReplacementOrder(spec.Child("replacementOrder"), refresher.Spec.ReplacementOrder, refresher.Spec.CloudProvider, refresher.Spec.Strategy)...)
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(operatorv1alpha1.GroupVersion.WithKind("AWSNodeRefresher").GroupKind(), refresher.Name, errs)
+}
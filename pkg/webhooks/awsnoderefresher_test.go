@@ -0,0 +1,216 @@
+package webhooks
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+)
+
+func TestAWSNodeRefresherValidateCreate(t *testing.T) {
+	cases := []struct {
+		title       string
+		annotations map[string]string
+		spec        operatorv1alpha1.AWSNodeRefresherSpec
+		expected    bool
+	}{
+		{
+			title: "Valid refresher",
+			spec: operatorv1alpha1.AWSNodeRefresherSpec{
+				Region: "us-east-1",
+				AutoScalingGroups: []operatorv1alpha1.AutoScalingGroup{
+					{
+						Name: "asg-a",
+					},
+				},
+				Desired:                  3,
+				ASGModifyCoolTimeSeconds: 600,
+				Role:                     operatorv1alpha1.Worker,
+				Schedule:                 "3 10 * * *",
+				SurplusNodes:             1,
+				DrainGracePeriodSeconds:  300,
+			},
+			expected: true,
+		},
+		{
+			title: "Invalid schedule",
+			spec: operatorv1alpha1.AWSNodeRefresherSpec{
+				Region: "us-east-1",
+				AutoScalingGroups: []operatorv1alpha1.AutoScalingGroup{
+					{
+						Name: "asg-a",
+					},
+				},
+				Desired:                  3,
+				ASGModifyCoolTimeSeconds: 600,
+				Role:                     operatorv1alpha1.Worker,
+				Schedule:                 "61 * * * *",
+				SurplusNodes:             1,
+				DrainGracePeriodSeconds:  300,
+			},
+			expected: false,
+		},
+		{
+			title: "Refresher without schedule",
+			spec: operatorv1alpha1.AWSNodeRefresherSpec{
+				Region: "us-east-1",
+				AutoScalingGroups: []operatorv1alpha1.AutoScalingGroup{
+					{
+						Name: "asg-a",
+					},
+				},
+				Desired:                  3,
+				ASGModifyCoolTimeSeconds: 600,
+				Role:                     operatorv1alpha1.Worker,
+				SurplusNodes:             1,
+				DrainGracePeriodSeconds:  300,
+			},
+			expected: false,
+		},
+		{
+			title: "Refresher only with refresh-now annotation",
+			annotations: map[string]string{
+				operatorv1alpha1.RefreshNowAnnotation: "2026-10-17T10:00:00Z",
+			},
+			spec: operatorv1alpha1.AWSNodeRefresherSpec{
+				Region: "us-east-1",
+				AutoScalingGroups: []operatorv1alpha1.AutoScalingGroup{
+					{
+						Name: "asg-a",
+					},
+				},
+				Desired:                  3,
+				ASGModifyCoolTimeSeconds: 600,
+				Role:                     operatorv1alpha1.Worker,
+				SurplusNodes:             1,
+				DrainGracePeriodSeconds:  300,
+			},
+			expected: true,
+		},
+		{
+			title: "Negative surplus nodes",
+			spec: operatorv1alpha1.AWSNodeRefresherSpec{
+				Region: "us-east-1",
+				AutoScalingGroups: []operatorv1alpha1.AutoScalingGroup{
+					{
+						Name: "asg-a",
+					},
+				},
+				Desired:                  3,
+				ASGModifyCoolTimeSeconds: 600,
+				Role:                     operatorv1alpha1.Worker,
+				Schedule:                 "3 10 * * *",
+				SurplusNodes:             -1,
+				DrainGracePeriodSeconds:  300,
+			},
+			expected: false,
+		},
+		{
+			title: "Negative drain grace period",
+			spec: operatorv1alpha1.AWSNodeRefresherSpec{
+				Region: "us-east-1",
+				AutoScalingGroups: []operatorv1alpha1.AutoScalingGroup{
+					{
+						Name: "asg-a",
+					},
+				},
+				Desired:                  3,
+				ASGModifyCoolTimeSeconds: 600,
+				Role:                     operatorv1alpha1.Worker,
+				Schedule:                 "3 10 * * *",
+				SurplusNodes:             1,
+				DrainGracePeriodSeconds:  -300,
+			},
+			expected: false,
+		},
+		{
+			title: "GCP refresher without region",
+			spec: operatorv1alpha1.AWSNodeRefresherSpec{
+				CloudTarget: operatorv1alpha1.CloudTarget{
+					CloudProvider: "gcp",
+					GCP: &operatorv1alpha1.GCPTarget{
+						Project: "my-project",
+						Zone:    "us-central1-a",
+					},
+				},
+				AutoScalingGroups: []operatorv1alpha1.AutoScalingGroup{
+					{
+						Name: "mig-a",
+					},
+				},
+				Desired:                  3,
+				ASGModifyCoolTimeSeconds: 600,
+				Role:                     operatorv1alpha1.Worker,
+				Schedule:                 "3 10 * * *",
+				SurplusNodes:             1,
+				DrainGracePeriodSeconds:  300,
+			},
+			expected: true,
+		},
+		{
+			title: "GCP refresher without gcp target",
+			spec: operatorv1alpha1.AWSNodeRefresherSpec{
+				CloudTarget: operatorv1alpha1.CloudTarget{
+					CloudProvider: "gcp",
+				},
+				AutoScalingGroups: []operatorv1alpha1.AutoScalingGroup{
+					{
+						Name: "mig-a",
+					},
+				},
+				Desired:                  3,
+				ASGModifyCoolTimeSeconds: 600,
+				Role:                     operatorv1alpha1.Worker,
+				Schedule:                 "3 10 * * *",
+				SurplusNodes:             1,
+				DrainGracePeriodSeconds:  300,
+			},
+			expected: false,
+		},
+		{
+			title: "Cluster API refresher without namespace",
+			spec: operatorv1alpha1.AWSNodeRefresherSpec{
+				CloudTarget: operatorv1alpha1.CloudTarget{
+					CloudProvider: "clusterapi",
+					ClusterAPI:    &operatorv1alpha1.ClusterAPITarget{},
+				},
+				AutoScalingGroups: []operatorv1alpha1.AutoScalingGroup{
+					{
+						Name: "md-a",
+					},
+				},
+				Desired:                  3,
+				ASGModifyCoolTimeSeconds: 600,
+				Role:                     operatorv1alpha1.Worker,
+				Schedule:                 "3 10 * * *",
+				SurplusNodes:             1,
+				DrainGracePeriodSeconds:  300,
+			},
+			expected: false,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+
+		refresher := &operatorv1alpha1.AWSNodeRefresher{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "test-refresher",
+				Annotations: c.annotations,
+			},
+			Spec: c.spec,
+		}
+		v := &AWSNodeRefresherValidator{}
+		_, err := v.ValidateCreate(context.Background(), refresher)
+		if c.expected && err != nil {
+			t.Errorf("CASE: %s : refresher should be valid, but returned %v", c.title, err)
+		}
+		if !c.expected && !apierrors.IsInvalid(err) {
+			t.Errorf("CASE: %s : refresher should be invalid, but returned %v", c.title, err)
+		}
+	}
+}
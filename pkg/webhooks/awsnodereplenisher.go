@@ -0,0 +1,50 @@
+package webhooks
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+)
+
+// +kubebuilder:webhook:path=/validate-operator-h3poteto-dev-v1alpha1-awsnodereplenisher,mutating=false,failurePolicy=fail,sideEffects=None,groups=operator.h3poteto.dev,resources=awsnodereplenishers,verbs=create;update,versions=v1alpha1,name=vawsnodereplenisher.operator.h3poteto.dev,admissionReviewVersions=v1
+
+// AWSNodeReplenisherValidator validates AWSNodeReplenisher resources.
+type AWSNodeReplenisherValidator struct{}
+
+var _ admission.Validator[*operatorv1alpha1.AWSNodeReplenisher] = &AWSNodeReplenisherValidator{}
+
+func SetupAWSNodeReplenisherWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &operatorv1alpha1.AWSNodeReplenisher{}).
+		WithValidator(&AWSNodeReplenisherValidator{}).
+		Complete()
+}
+
+func (v *AWSNodeReplenisherValidator) ValidateCreate(ctx context.Context, obj *operatorv1alpha1.AWSNodeReplenisher) (admission.Warnings, error) {
+	return nil, validateAWSNodeReplenisher(obj)
+}
+
+func (v *AWSNodeReplenisherValidator) ValidateUpdate(ctx context.Context, oldObj, newObj *operatorv1alpha1.AWSNodeReplenisher) (admission.Warnings, error) {
+	return nil, validateAWSNodeReplenisher(newObj)
+}
+
+func (v *AWSNodeReplenisherValidator) ValidateDelete(ctx context.Context, obj *operatorv1alpha1.AWSNodeReplenisher) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateAWSNodeReplenisher(replenisher *operatorv1alpha1.AWSNodeReplenisher) error {
+	var errs field.ErrorList
+	spec := field.NewPath("spec")
+	errs = append(errs, validateCloudTarget(spec, replenisher.Spec.CloudTarget, replenisher.Spec.Region)...)
+	errs = append(errs, validateAutoScalingGroups(spec.Child("autoScalingGroups"), replenisher.Spec.AutoScalingGroups)...)
+	errs = append(errs, validateNonNegative(spec.Child("desired"), int64(replenisher.Spec.Desired))...)
+	errs = append(errs, validateNonNegative(spec.Child("asgModifyCoolTimeSeconds"), replenisher.Spec.ASGModifyCoolTimeSeconds)...)
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(operatorv1alpha1.GroupVersion.WithKind("AWSNodeReplenisher").GroupKind(), replenisher.Name, errs)
+}
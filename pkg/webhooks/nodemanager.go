@@ -0,0 +1,99 @@
+package webhooks
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+)
+
+// +kubebuilder:webhook:path=/validate-operator-h3poteto-dev-v1alpha1-nodemanager,mutating=false,failurePolicy=fail,sideEffects=None,groups=operator.h3poteto.dev,resources=nodemanagers,verbs=create;update,versions=v1alpha1,name=vnodemanager.operator.h3poteto.dev,admissionReviewVersions=v1
+
+// NodeManagerValidator validates NodeManager resources.
+type NodeManagerValidator struct{}
+
+var _ admission.Validator[*operatorv1alpha1.NodeManager] = &NodeManagerValidator{}
+
+func SetupNodeManagerWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &operatorv1alpha1.NodeManager{}).
+		WithValidator(&NodeManagerValidator{}).
+		Complete()
+}
+
+func (v *NodeManagerValidator) ValidateCreate(ctx context.Context, obj *operatorv1alpha1.NodeManager) (admission.Warnings, error) {
+	return nil, validateNodeManager(obj)
+}
+
+func (v *NodeManagerValidator) ValidateUpdate(ctx context.Context, oldObj, newObj *operatorv1alpha1.NodeManager) (admission.Warnings, error) {
+	return nil, validateNodeManager(newObj)
+}
+
+func (v *NodeManagerValidator) ValidateDelete(ctx context.Context, obj *operatorv1alpha1.NodeManager) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateNodeManager(nodeManager *operatorv1alpha1.NodeManager) error {
+	var errs field.ErrorList
+	spec := field.NewPath("spec")
+	switch nodeManager.Spec.CloudProvider {
+	case operatorv1alpha1.CloudProviderAWS:
+		if nodeManager.Spec.Aws == nil {
+			errs = append(errs, field.Required(spec.Child("aws"), "aws must be specified when cloudProvider is aws"))
+			break
+		}
+		if nodeManager.Spec.Aws.Region == "" {
+			errs = append(errs, field.Required(spec.Child("aws", "region"), "region must be specified"))
+		}
+		errs = append(errs, validateAssumeRole(spec.Child("aws"), nodeManager.Spec.Aws.RoleARN, nodeManager.Spec.Aws.ExternalID)...)
+		errs = append(errs, validateEndpoint(spec.Child("aws", "endpoint"), nodeManager.Spec.Aws.Endpoint)...)
+		if nodeManager.Spec.Aws.Masters != nil {
+			errs = append(errs, validateNodes(spec.Child("aws", "masters"), nodeManager.Spec.Aws.Masters)...)
+		}
+		if nodeManager.Spec.Aws.Workers != nil {
+			errs = append(errs, validateNodes(spec.Child("aws", "workers"), nodeManager.Spec.Aws.Workers)...)
+		}
+	case operatorv1alpha1.CloudProviderGCP:
+		if nodeManager.Spec.GCP == nil {
+			errs = append(errs, field.Required(spec.Child("gcp"), "gcp must be specified when cloudProvider is gcp"))
+			break
+		}
+		errs = append(errs, validateGCPLocation(spec.Child("gcp"), nodeManager.Spec.GCP.Project, nodeManager.Spec.GCP.Zone)...)
+		if nodeManager.Spec.GCP.Masters != nil {
+			errs = append(errs, validateGCPNodes(spec.Child("gcp", "masters"), nodeManager.Spec.GCP.Masters)...)
+		}
+		if nodeManager.Spec.GCP.Workers != nil {
+			errs = append(errs, validateGCPNodes(spec.Child("gcp", "workers"), nodeManager.Spec.GCP.Workers)...)
+		}
+	case operatorv1alpha1.CloudProviderAzure:
+		if nodeManager.Spec.Azure == nil {
+			errs = append(errs, field.Required(spec.Child("azure"), "azure must be specified when cloudProvider is azure"))
+			break
+		}
+		errs = append(errs, validateAzureLocation(spec.Child("azure"), nodeManager.Spec.Azure.SubscriptionID, nodeManager.Spec.Azure.ResourceGroup)...)
+		if nodeManager.Spec.Azure.Masters != nil {
+			errs = append(errs, validateAzureNodes(spec.Child("azure", "masters"), nodeManager.Spec.Azure.Masters)...)
+		}
+		if nodeManager.Spec.Azure.Workers != nil {
+			errs = append(errs, validateAzureNodes(spec.Child("azure", "workers"), nodeManager.Spec.Azure.Workers)...)
+		}
+	case operatorv1alpha1.CloudProviderClusterAPI:
+		if nodeManager.Spec.ClusterAPI == nil {
+			errs = append(errs, field.Required(spec.Child("clusterAPI"), "clusterAPI must be specified when cloudProvider is clusterapi"))
+			break
+		}
+		errs = append(errs, validateClusterAPINamespace(spec.Child("clusterAPI", "namespace"), nodeManager.Spec.ClusterAPI.Namespace)...)
+		if nodeManager.Spec.ClusterAPI.Workers != nil {
+			errs = append(errs, validateClusterAPINodes(spec.Child("clusterAPI", "workers"), nodeManager.Spec.ClusterAPI.Workers)...)
+		}
+	default:
+		errs = append(errs, field.NotSupported(spec.Child("cloudProvider"), nodeManager.Spec.CloudProvider, supportedCloudProviders))
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(operatorv1alpha1.GroupVersion.WithKind("NodeManager").GroupKind(), nodeManager.Name, errs)
+}
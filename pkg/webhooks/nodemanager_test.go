@@ -0,0 +1,365 @@
+package webhooks
+
+import (
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+)
+
+func TestNodeManagerValidateCreate(t *testing.T) {
+	cases := []struct {
+		title    string
+		spec     operatorv1alpha1.NodeManagerSpec
+		expected bool
+	}{
+		{
+			title: "Valid NodeManager",
+			spec: operatorv1alpha1.NodeManagerSpec{
+				CloudProvider: "aws",
+				Aws: &operatorv1alpha1.CloudAWS{
+					Region: "us-east-1",
+					Workers: &operatorv1alpha1.Nodes{
+						AutoScalingGroups: []operatorv1alpha1.AutoScalingGroup{
+							{
+								Name: "asg-a",
+							},
+							{
+								Name: "asg-c",
+							},
+						},
+						Desired: 3,
+						RefreshOptions: operatorv1alpha1.RefreshOptions{
+							ASGModifyCoolTimeSeconds: 600,
+							RefreshSchedule:          "3 10 * * *",
+							SurplusNodes:             1,
+							DrainGracePeriodSeconds:  300,
+						},
+						EnableReplenish: true,
+					},
+				},
+			},
+			expected: true,
+		},
+		{
+			title: "aws is not specified",
+			spec: operatorv1alpha1.NodeManagerSpec{
+				CloudProvider: "aws",
+			},
+			expected: false,
+		},
+		{
+			title: "Duplicated AutoScalingGroups in masters",
+			spec: operatorv1alpha1.NodeManagerSpec{
+				CloudProvider: "aws",
+				Aws: &operatorv1alpha1.CloudAWS{
+					Region: "us-east-1",
+					Masters: &operatorv1alpha1.Nodes{
+						AutoScalingGroups: []operatorv1alpha1.AutoScalingGroup{
+							{
+								Name: "asg-a",
+							},
+							{
+								Name: "asg-a",
+							},
+						},
+						Desired: 3,
+						RefreshOptions: operatorv1alpha1.RefreshOptions{
+							ASGModifyCoolTimeSeconds: 600,
+							SurplusNodes:             1,
+							DrainGracePeriodSeconds:  300,
+						},
+						EnableReplenish: true,
+					},
+				},
+			},
+			expected: false,
+		},
+		{
+			title: "Invalid refresh schedule in workers",
+			spec: operatorv1alpha1.NodeManagerSpec{
+				CloudProvider: "aws",
+				Aws: &operatorv1alpha1.CloudAWS{
+					Region: "us-east-1",
+					Workers: &operatorv1alpha1.Nodes{
+						AutoScalingGroups: []operatorv1alpha1.AutoScalingGroup{
+							{
+								Name: "asg-a",
+							},
+						},
+						Desired: 3,
+						RefreshOptions: operatorv1alpha1.RefreshOptions{
+							ASGModifyCoolTimeSeconds: 600,
+							RefreshSchedule:          "at midnight",
+							SurplusNodes:             1,
+							DrainGracePeriodSeconds:  300,
+						},
+						EnableReplenish: true,
+					},
+				},
+			},
+			expected: false,
+		},
+		{
+			title: "Valid GCP NodeManager",
+			spec: operatorv1alpha1.NodeManagerSpec{
+				CloudProvider: "gcp",
+				GCP: &operatorv1alpha1.CloudGCP{
+					Project: "my-project",
+					Zone:    "us-central1-a",
+					Workers: &operatorv1alpha1.GCPNodes{
+						InstanceGroups: []operatorv1alpha1.InstanceGroup{
+							{
+								Name: "mig-a",
+							},
+						},
+						Desired: 3,
+						RefreshOptions: operatorv1alpha1.RefreshOptions{
+							ASGModifyCoolTimeSeconds: 600,
+							RefreshSchedule:          "3 10 * * *",
+							SurplusNodes:             1,
+							DrainGracePeriodSeconds:  300,
+						},
+						EnableReplenish: true,
+					},
+				},
+			},
+			expected: true,
+		},
+		{
+			title: "GCP NodeManager with maxNodeAge",
+			spec: operatorv1alpha1.NodeManagerSpec{
+				CloudProvider: "gcp",
+				GCP: &operatorv1alpha1.CloudGCP{
+					Project: "my-project",
+					Zone:    "us-central1-a",
+					Workers: &operatorv1alpha1.GCPNodes{
+						InstanceGroups: []operatorv1alpha1.InstanceGroup{
+							{
+								Name: "mig-a",
+							},
+						},
+						Desired: 3,
+						RefreshOptions: operatorv1alpha1.RefreshOptions{
+							ASGModifyCoolTimeSeconds: 600,
+							DrainGracePeriodSeconds:  300,
+							MaxNodeAge:               &metav1.Duration{Duration: 168 * time.Hour},
+						},
+					},
+				},
+			},
+			expected: true,
+		},
+		{
+			title: "GCP zone is not specified",
+			spec: operatorv1alpha1.NodeManagerSpec{
+				CloudProvider: "gcp",
+				GCP: &operatorv1alpha1.CloudGCP{
+					Project: "my-project",
+					Workers: &operatorv1alpha1.GCPNodes{
+						InstanceGroups: []operatorv1alpha1.InstanceGroup{
+							{
+								Name: "mig-a",
+							},
+						},
+						Desired: 3,
+						RefreshOptions: operatorv1alpha1.RefreshOptions{
+							ASGModifyCoolTimeSeconds: 600,
+							DrainGracePeriodSeconds:  300,
+						},
+					},
+				},
+			},
+			expected: false,
+		},
+		{
+			title: "GCP NodeManager with instanceTypeMismatch order",
+			spec: operatorv1alpha1.NodeManagerSpec{
+				CloudProvider: "gcp",
+				GCP: &operatorv1alpha1.CloudGCP{
+					Project: "my-project",
+					Zone:    "us-central1-a",
+					Workers: &operatorv1alpha1.GCPNodes{
+						InstanceGroups: []operatorv1alpha1.InstanceGroup{
+							{
+								Name: "mig-a",
+							},
+						},
+						Desired: 3,
+						RefreshOptions: operatorv1alpha1.RefreshOptions{
+							ASGModifyCoolTimeSeconds: 600,
+							DrainGracePeriodSeconds:  300,
+							ReplacementOrder:         operatorv1alpha1.ReplacementOrderInstanceTypeMismatch,
+						},
+					},
+				},
+			},
+			expected: false,
+		},
+		{
+			title: "Valid Cluster API NodeManager",
+			spec: operatorv1alpha1.NodeManagerSpec{
+				CloudProvider: "clusterapi",
+				ClusterAPI: &operatorv1alpha1.CloudClusterAPI{
+					Namespace: "default",
+					Workers: &operatorv1alpha1.ClusterAPINodes{
+						MachineDeployments: []operatorv1alpha1.MachineDeployment{
+							{
+								Name: "md-a",
+							},
+						},
+						Desired: 3,
+						RefreshOptions: operatorv1alpha1.RefreshOptions{
+							ASGModifyCoolTimeSeconds: 600,
+							RefreshSchedule:          "3 10 * * *",
+							SurplusNodes:             1,
+							DrainGracePeriodSeconds:  300,
+						},
+						EnableReplenish: true,
+					},
+				},
+			},
+			expected: true,
+		},
+		{
+			title: "Cluster API NodeManager without MachineDeployments",
+			spec: operatorv1alpha1.NodeManagerSpec{
+				CloudProvider: "clusterapi",
+				ClusterAPI: &operatorv1alpha1.CloudClusterAPI{
+					Namespace: "default",
+					Workers: &operatorv1alpha1.ClusterAPINodes{
+						Desired: 3,
+						RefreshOptions: operatorv1alpha1.RefreshOptions{
+							ASGModifyCoolTimeSeconds: 600,
+							DrainGracePeriodSeconds:  300,
+						},
+					},
+				},
+			},
+			expected: false,
+		},
+		{
+			title: "Valid Azure NodeManager",
+			spec: operatorv1alpha1.NodeManagerSpec{
+				CloudProvider: "azure",
+				Azure: &operatorv1alpha1.CloudAzure{
+					SubscriptionID: "00000000-0000-0000-0000-000000000000",
+					ResourceGroup:  "my-rg",
+					Workers: &operatorv1alpha1.AzureNodes{
+						ScaleSets: []operatorv1alpha1.ScaleSet{
+							{
+								Name: "vmss-a",
+							},
+						},
+						Desired: 3,
+						RefreshOptions: operatorv1alpha1.RefreshOptions{
+							ASGModifyCoolTimeSeconds: 600,
+							RefreshSchedule:          "3 10 * * *",
+							SurplusNodes:             1,
+							DrainGracePeriodSeconds:  300,
+						},
+						EnableReplenish: true,
+					},
+				},
+			},
+			expected: true,
+		},
+		{
+			title: "Duplicated Azure scale sets",
+			spec: operatorv1alpha1.NodeManagerSpec{
+				CloudProvider: "azure",
+				Azure: &operatorv1alpha1.CloudAzure{
+					SubscriptionID: "00000000-0000-0000-0000-000000000000",
+					ResourceGroup:  "my-rg",
+					Workers: &operatorv1alpha1.AzureNodes{
+						ScaleSets: []operatorv1alpha1.ScaleSet{
+							{
+								Name: "vmss-a",
+							},
+							{
+								Name: "vmss-a",
+							},
+						},
+						Desired: 3,
+						RefreshOptions: operatorv1alpha1.RefreshOptions{
+							ASGModifyCoolTimeSeconds: 600,
+							DrainGracePeriodSeconds:  300,
+						},
+					},
+				},
+			},
+			expected: false,
+		},
+		{
+			title: "Azure NodeManager with instanceTypeMismatch order",
+			spec: operatorv1alpha1.NodeManagerSpec{
+				CloudProvider: "azure",
+				Azure: &operatorv1alpha1.CloudAzure{
+					SubscriptionID: "00000000-0000-0000-0000-000000000000",
+					ResourceGroup:  "my-rg",
+					Workers: &operatorv1alpha1.AzureNodes{
+						ScaleSets: []operatorv1alpha1.ScaleSet{
+							{
+								Name: "vmss-a",
+							},
+						},
+						Desired: 3,
+						RefreshOptions: operatorv1alpha1.RefreshOptions{
+							ASGModifyCoolTimeSeconds: 600,
+							DrainGracePeriodSeconds:  300,
+							ReplacementOrder:         operatorv1alpha1.ReplacementOrderInstanceTypeMismatch,
+						},
+					},
+				},
+			},
+			expected: false,
+		},
+		{
+			title: "Cluster API NodeManager with instanceTypeMismatch order",
+			spec: operatorv1alpha1.NodeManagerSpec{
+				CloudProvider: "clusterapi",
+				ClusterAPI: &operatorv1alpha1.CloudClusterAPI{
+					Namespace: "default",
+					Workers: &operatorv1alpha1.ClusterAPINodes{
+						MachineDeployments: []operatorv1alpha1.MachineDeployment{
+							{
+								Name: "md-a",
+							},
+						},
+						Desired: 3,
+						RefreshOptions: operatorv1alpha1.RefreshOptions{
+							ASGModifyCoolTimeSeconds: 600,
+							DrainGracePeriodSeconds:  300,
+							ReplacementOrder:         operatorv1alpha1.ReplacementOrderInstanceTypeMismatch,
+						},
+					},
+				},
+			},
+			expected: false,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+
+		nodeManager := &operatorv1alpha1.NodeManager{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-manager",
+			},
+			Spec: c.spec,
+		}
+		v := &NodeManagerValidator{}
+		_, err := v.ValidateCreate(context.Background(), nodeManager)
+		if c.expected && err != nil {
+			t.Errorf("CASE: %s : NodeManager should be valid, but returned %v", c.title, err)
+		}
+		if !c.expected && !apierrors.IsInvalid(err) {
+			t.Errorf("CASE: %s : NodeManager should be invalid, but returned %v", c.title, err)
+		}
+	}
+}
@@ -0,0 +1,494 @@
+package webhooks
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/gorhill/cronexpr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+)
+
+// validateSchedule checks that schedule is a valid cron expression.
+func validateSchedule(path *field.Path, schedule string, required bool) field.ErrorList {
+	var errs field.ErrorList
+	if schedule == "" {
+		if required {
+			errs = append(errs, field.Required(path, "schedule must be specified"))
+		}
+		return errs
+	}
+	if _, err := cronexpr.Parse(schedule); err != nil {
+		errs = append(errs, field.Invalid(path, schedule, "must be a valid cron expression: "+err.Error()))
+	}
+	return errs
+}
+
+var supportedCloudProviders = []string{
+	operatorv1alpha1.CloudProviderAWS,
+	operatorv1alpha1.CloudProviderGCP,
+	operatorv1alpha1.CloudProviderAzure,
+	operatorv1alpha1.CloudProviderClusterAPI,
+}
+
+// validateAutoScalingGroups checks that at least one AutoScalingGroup is specified and all names are unique.
+func validateAutoScalingGroups(path *field.Path, groups []operatorv1alpha1.AutoScalingGroup) field.ErrorList {
+	var names []string
+	for i := range groups {
+		names = append(names, groups[i].Name)
+	}
+	return validateGroupNames(path, names, "AutoScalingGroup")
+}
+
+// validateAutoScalingGroupsOrTagSelector checks AutoScalingGroups when they are specified, because they can be discovered by TagSelector instead.
+func validateAutoScalingGroupsOrTagSelector(path *field.Path, groups []operatorv1alpha1.AutoScalingGroup, tagSelector map[string]string) field.ErrorList {
+	var errs field.ErrorList
+	if len(groups) == 0 && len(tagSelector) == 0 {
+		errs = append(errs, field.Required(path.Child("autoScalingGroups"), "at least one AutoScalingGroup or tagSelector must be specified"))
+		return errs
+	}
+	if len(groups) > 0 {
+		errs = append(errs, validateAutoScalingGroups(path.Child("autoScalingGroups"), groups)...)
+	}
+	for key := range tagSelector {
+		if key == "" {
+			errs = append(errs, field.Invalid(path.Child("tagSelector"), tagSelector, "tag key must not be empty"))
+		}
+	}
+	return errs
+}
+
+// validateInstanceGroups checks that at least one InstanceGroup is specified and all names are unique.
+func validateInstanceGroups(path *field.Path, groups []operatorv1alpha1.InstanceGroup) field.ErrorList {
+	var names []string
+	for i := range groups {
+		names = append(names, groups[i].Name)
+	}
+	return validateGroupNames(path, names, "InstanceGroup")
+}
+
+// validateScaleSets checks that at least one ScaleSet is specified and all names are unique.
+func validateScaleSets(path *field.Path, scaleSets []operatorv1alpha1.ScaleSet) field.ErrorList {
+	var names []string
+	for i := range scaleSets {
+		names = append(names, scaleSets[i].Name)
+	}
+	return validateGroupNames(path, names, "ScaleSet")
+}
+
+// validateMachineDeployments checks that at least one MachineDeployment is specified and all names are unique.
+func validateMachineDeployments(path *field.Path, deployments []operatorv1alpha1.MachineDeployment) field.ErrorList {
+	var names []string
+	for i := range deployments {
+		names = append(names, deployments[i].Name)
+	}
+	return validateGroupNames(path, names, "MachineDeployment")
+}
+
+func validateGroupNames(path *field.Path, names []string, kind string) field.ErrorList {
+	var errs field.ErrorList
+	if len(names) == 0 {
+		errs = append(errs, field.Required(path, "at least one "+kind+" must be specified"))
+		return errs
+	}
+	found := map[string]bool{}
+	for i, name := range names {
+		if name == "" {
+			errs = append(errs, field.Required(path.Index(i).Child("name"), kind+" name must be specified"))
+			continue
+		}
+		if found[name] {
+			errs = append(errs, field.Duplicate(path.Index(i).Child("name"), name))
+			continue
+		}
+		found[name] = true
+	}
+	return errs
+}
+
+// validateCloudTarget checks that parameters for the cloud provider of node groups are specified.
+func validateCloudTarget(spec *field.Path, target operatorv1alpha1.CloudTarget, region string) field.ErrorList {
+	var errs field.ErrorList
+	switch target.CloudProvider {
+	case "", operatorv1alpha1.CloudProviderAWS:
+		if region == "" {
+			errs = append(errs, field.Required(spec.Child("region"), "region must be specified"))
+		}
+		if target.AWS != nil {
+			errs = append(errs, validateAssumeRole(spec.Child("aws"), target.AWS.RoleARN, target.AWS.ExternalID)...)
+			errs = append(errs, validateEndpoint(spec.Child("aws", "endpoint"), target.AWS.Endpoint)...)
+		}
+	case operatorv1alpha1.CloudProviderGCP:
+		if target.GCP == nil {
+			errs = append(errs, field.Required(spec.Child("gcp"), "gcp must be specified when cloudProvider is gcp"))
+			break
+		}
+		errs = append(errs, validateGCPLocation(spec.Child("gcp"), target.GCP.Project, target.GCP.Zone)...)
+	case operatorv1alpha1.CloudProviderAzure:
+		if target.Azure == nil {
+			errs = append(errs, field.Required(spec.Child("azure"), "azure must be specified when cloudProvider is azure"))
+			break
+		}
+		errs = append(errs, validateAzureLocation(spec.Child("azure"), target.Azure.SubscriptionID, target.Azure.ResourceGroup)...)
+	case operatorv1alpha1.CloudProviderClusterAPI:
+		if target.ClusterAPI == nil {
+			errs = append(errs, field.Required(spec.Child("clusterAPI"), "clusterAPI must be specified when cloudProvider is clusterapi"))
+			break
+		}
+		errs = append(errs, validateClusterAPINamespace(spec.Child("clusterAPI", "namespace"), target.ClusterAPI.Namespace)...)
+	default:
+		errs = append(errs, field.NotSupported(spec.Child("cloudProvider"), target.CloudProvider, supportedCloudProviders))
+	}
+	return errs
+}
+
+// validateAssumeRole checks that roleARN is an ARN of an IAM role, and externalID is used with roleARN.
+func validateAssumeRole(path *field.Path, roleARN, externalID string) field.ErrorList {
+	var errs field.ErrorList
+	if roleARN == "" {
+		if externalID != "" {
+			errs = append(errs, field.Required(path.Child("roleARN"), "roleARN must be specified with externalID"))
+		}
+		return errs
+	}
+	parsed, err := arn.Parse(roleARN)
+	if err != nil || parsed.Service != "iam" || !strings.HasPrefix(parsed.Resource, "role/") {
+		errs = append(errs, field.Invalid(path.Child("roleARN"), roleARN, "must be an ARN of an IAM role"))
+	}
+	return errs
+}
+
+// validateEndpoint checks that the endpoint is an absolute HTTP URL when it is specified.
+func validateEndpoint(path *field.Path, endpoint string) field.ErrorList {
+	var errs field.ErrorList
+	if endpoint == "" {
+		return errs
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, field.Invalid(path, endpoint, "must be an http or https URL"))
+	}
+	return errs
+}
+
+var supportedRefreshStrategies = []string{
+	string(operatorv1alpha1.RefreshStrategyRolling),
+	string(operatorv1alpha1.RefreshStrategyInstanceRefresh),
+}
+
+// validateRefreshStrategy checks the strategy, and that preferences of Instance Refresh are used only with instanceRefresh strategy of AWS.
+func validateRefreshStrategy(strategyPath, preferencesPath *field.Path, strategy operatorv1alpha1.RefreshStrategy, preferences *operatorv1alpha1.InstanceRefreshPreferences, cloudProvider string) field.ErrorList {
+	var errs field.ErrorList
+	switch strategy {
+	case "", operatorv1alpha1.RefreshStrategyRolling:
+		if preferences != nil {
+			errs = append(errs, field.Forbidden(preferencesPath, "instanceRefresh can be specified only with instanceRefresh strategy"))
+		}
+		return errs
+	case operatorv1alpha1.RefreshStrategyInstanceRefresh:
+		if cloudProvider != "" && cloudProvider != operatorv1alpha1.CloudProviderAWS {
+			errs = append(errs, field.Forbidden(strategyPath, "instanceRefresh strategy is supported only when cloudProvider is aws"))
+		}
+	default:
+		errs = append(errs, field.NotSupported(strategyPath, strategy, supportedRefreshStrategies))
+		return errs
+	}
+	if preferences == nil {
+		return errs
+	}
+	if p := preferences.MinHealthyPercentage; p != nil && (*p < 0 || *p > 100) {
+		errs = append(errs, field.Invalid(preferencesPath.Child("minHealthyPercentage"), *p, "must be between 0 and 100"))
+	}
+	if preferences.InstanceWarmupSeconds != nil {
+		errs = append(errs, validateNonNegative(preferencesPath.Child("instanceWarmupSeconds"), *preferences.InstanceWarmupSeconds)...)
+	}
+	if preferences.CheckpointDelaySeconds != nil {
+		errs = append(errs, validateNonNegative(preferencesPath.Child("checkpointDelaySeconds"), *preferences.CheckpointDelaySeconds)...)
+	}
+	// EC2 Auto Scaling requires checkpoints to be ascending and to end at 100.
+	checkpoints := preferences.CheckpointPercentages
+	previous := int64(0)
+	for i, p := range checkpoints {
+		if p <= previous || p > 100 {
+			errs = append(errs, field.Invalid(preferencesPath.Child("checkpointPercentages").Index(i), p, "must be ascending between 1 and 100"))
+		}
+		previous = p
+	}
+	if len(checkpoints) > 0 && checkpoints[len(checkpoints)-1] != 100 {
+		errs = append(errs, field.Invalid(preferencesPath.Child("checkpointPercentages"), checkpoints, "the last checkpoint must be 100"))
+	}
+	return errs
+}
+
+var supportedRefreshTriggers = []string{
+	string(operatorv1alpha1.RefreshTriggerSchedule),
+	string(operatorv1alpha1.RefreshTriggerDrift),
+}
+
+// validateRefreshTrigger checks the trigger, and that drift is used only for AWS, because drift is detected from launch templates.
+func validateRefreshTrigger(path *field.Path, trigger operatorv1alpha1.RefreshTrigger, cloudProvider string) field.ErrorList {
+	var errs field.ErrorList
+	switch trigger {
+	case "", operatorv1alpha1.RefreshTriggerSchedule:
+	case operatorv1alpha1.RefreshTriggerDrift:
+		if cloudProvider != "" && cloudProvider != operatorv1alpha1.CloudProviderAWS {
+			errs = append(errs, field.Forbidden(path, "drift trigger is supported only when cloudProvider is aws"))
+		}
+	default:
+		errs = append(errs, field.NotSupported(path, trigger, supportedRefreshTriggers))
+	}
+	return errs
+}
+
+// validateAMISource checks the parameter, and that amiSource is used only for AWS without drift trigger, because drift trigger already detects new AMIs.
+func validateAMISource(path *field.Path, source *operatorv1alpha1.AMISource, trigger operatorv1alpha1.RefreshTrigger, cloudProvider string) field.ErrorList {
+	var errs field.ErrorList
+	if source == nil {
+		return errs
+	}
+	if cloudProvider != "" && cloudProvider != operatorv1alpha1.CloudProviderAWS {
+		errs = append(errs, field.Forbidden(path, "amiSource is supported only when cloudProvider is aws"))
+	}
+	if trigger == operatorv1alpha1.RefreshTriggerDrift {
+		errs = append(errs, field.Forbidden(path, "amiSource can not be used with drift trigger"))
+	}
+	if source.SSMParameter == "" {
+		errs = append(errs, field.Required(path.Child("ssmParameter"), "ssmParameter must be specified"))
+	}
+	return errs
+}
+
+// validateMaxNodeAge checks maxNodeAge, and that it is not combined with other ways to choose nodes to replace.
+// EC2 Auto Scaling has maximum instance lifetime for instanceRefresh strategy instead.
+func validateMaxNodeAge(path *field.Path, age *metav1.Duration, trigger operatorv1alpha1.RefreshTrigger, source *operatorv1alpha1.AMISource, strategy operatorv1alpha1.RefreshStrategy) field.ErrorList {
+	var errs field.ErrorList
+	if age == nil {
+		return errs
+	}
+	if age.Duration <= 0 {
+		errs = append(errs, field.Invalid(path, age.Duration.String(), "must be greater than 0"))
+	}
+	if trigger == operatorv1alpha1.RefreshTriggerDrift {
+		errs = append(errs, field.Forbidden(path, "maxNodeAge can not be used with drift trigger"))
+	}
+	if source != nil {
+		errs = append(errs, field.Forbidden(path, "maxNodeAge can not be used with amiSource"))
+	}
+	if strategy == operatorv1alpha1.RefreshStrategyInstanceRefresh {
+		errs = append(errs, field.Forbidden(path, "maxNodeAge can not be used with instanceRefresh strategy"))
+	}
+	return errs
+}
+
+// validateMaintenanceWindow checks the window, and that it is not combined with schedule, because the window is used instead of it.
+func validateMaintenanceWindow(path *field.Path, window *operatorv1alpha1.MaintenanceWindow, schedule string) field.ErrorList {
+	var errs field.ErrorList
+	if window == nil {
+		return errs
+	}
+	if schedule != "" {
+		errs = append(errs, field.Forbidden(path, "maintenanceWindow can not be used with schedule"))
+	}
+	errs = append(errs, validateSchedule(path.Child("schedule"), window.Schedule, true)...)
+	if window.Duration.Duration <= 0 {
+		errs = append(errs, field.Invalid(path.Child("duration"), window.Duration.Duration.String(), "must be greater than 0"))
+	}
+	if _, err := time.LoadLocation(window.TimeZone); err != nil {
+		errs = append(errs, field.Invalid(path.Child("timeZone"), window.TimeZone, "must be a valid IANA time zone: "+err.Error()))
+	}
+	for i, blackout := range window.Blackouts {
+		if !blackout.End.After(blackout.Start.Time) {
+			errs = append(errs, field.Invalid(path.Child("blackouts").Index(i).Child("end"), blackout.End.String(), "must be after start"))
+		}
+	}
+	return errs
+}
+
+// validatePhaseTimeouts checks that the deadlines are positive.
+func validatePhaseTimeouts(path *field.Path, timeouts *operatorv1alpha1.PhaseTimeouts) field.ErrorList {
+	var errs field.ErrorList
+	if timeouts == nil {
+		return errs
+	}
+	for name, timeout := range map[string]*metav1.Duration{
+		"increase": timeouts.Increase,
+		"replace":  timeouts.Replace,
+		"awsWait":  timeouts.AWSWait,
+		"decrease": timeouts.Decrease,
+	} {
+		if timeout != nil && timeout.Duration <= 0 {
+			errs = append(errs, field.Invalid(path.Child(name), timeout.Duration.String(), "must be greater than 0"))
+		}
+	}
+	return errs
+}
+
+// validateSurge checks maxSurge and maxUnavailable like Deployment, so that at least one node is replaced at once.
+// surplusNodes is used instead of maxSurge when it is not specified.
+func validateSurge(path *field.Path, surge, unavailable *intstr.IntOrString, surplusNodes int64, strategy operatorv1alpha1.RefreshStrategy) field.ErrorList {
+	var errs field.ErrorList
+	if surge == nil && unavailable == nil {
+		return errs
+	}
+	if strategy == operatorv1alpha1.RefreshStrategyInstanceRefresh {
+		errs = append(errs, field.Forbidden(path, "maxSurge and maxUnavailable can not be used with instanceRefresh strategy"))
+	}
+	surgeValue, surgeErrs := validateIntOrPercent(path.Child("maxSurge"), surge)
+	errs = append(errs, surgeErrs...)
+	if surge == nil {
+		surgeValue = int(surplusNodes)
+	}
+	unavailableValue, unavailableErrs := validateIntOrPercent(path.Child("maxUnavailable"), unavailable)
+	errs = append(errs, unavailableErrs...)
+	if unavailable != nil && unavailable.Type == intstr.String && unavailableValue > 100 {
+		errs = append(errs, field.Invalid(path.Child("maxUnavailable"), unavailable.String(), "must not be greater than 100%"))
+	}
+	if len(surgeErrs) == 0 && len(unavailableErrs) == 0 && surgeValue == 0 && unavailableValue == 0 {
+		errs = append(errs, field.Forbidden(path, "maxSurge, or surplusNodes when it is not specified, and maxUnavailable may not be 0 at the same time"))
+	}
+	return errs
+}
+
+// validateReplacementOrder checks that the order is supported by the cloud provider and the strategy.
+// Instance Refresh of EC2 Auto Scaling chooses instances to replace by itself.
+func validateReplacementOrder(path *field.Path, order operatorv1alpha1.ReplacementOrder, cloudProvider string, strategy operatorv1alpha1.RefreshStrategy) field.ErrorList {
+	var errs field.ErrorList
+	if order == "" {
+		return errs
+	}
+	if strategy == operatorv1alpha1.RefreshStrategyInstanceRefresh {
+		errs = append(errs, field.Forbidden(path, "replacementOrder can not be used with instanceRefresh strategy"))
+	}
+	if order == operatorv1alpha1.ReplacementOrderInstanceTypeMismatch && cloudProvider != "" && cloudProvider != operatorv1alpha1.CloudProviderAWS {
+		errs = append(errs, field.Forbidden(path, "instanceTypeMismatch is supported only when cloudProvider is aws"))
+	}
+	return errs
+}
+
+// validateIntOrPercent checks that the value is a non-negative integer or percentage, and returns the integer or the percentage.
+func validateIntOrPercent(path *field.Path, value *intstr.IntOrString) (int, field.ErrorList) {
+	var errs field.ErrorList
+	if value == nil {
+		return 0, errs
+	}
+	if value.Type == intstr.String && !strings.HasSuffix(value.StrVal, "%") {
+		errs = append(errs, field.Invalid(path, value.StrVal, "must be an integer or a percentage, for example 25%"))
+		return 0, errs
+	}
+	v, err := intstr.GetScaledValueFromIntOrPercent(value, 100, true)
+	if err != nil {
+		errs = append(errs, field.Invalid(path, value.String(), "must be an integer or a percentage, for example 25%"))
+		return 0, errs
+	}
+	if v < 0 {
+		errs = append(errs, field.Invalid(path, value.String(), "must be greater than or equal to 0"))
+	}
+	return v, errs
+}
+
+func validateAzureLocation(path *field.Path, subscriptionID, resourceGroup string) field.ErrorList {
+	var errs field.ErrorList
+	if subscriptionID == "" {
+		errs = append(errs, field.Required(path.Child("subscriptionID"), "subscriptionID must be specified"))
+	}
+	if resourceGroup == "" {
+		errs = append(errs, field.Required(path.Child("resourceGroup"), "resourceGroup must be specified"))
+	}
+	return errs
+}
+
+func validateClusterAPINamespace(path *field.Path, namespace string) field.ErrorList {
+	var errs field.ErrorList
+	if namespace == "" {
+		errs = append(errs, field.Required(path, "namespace of MachineDeployments must be specified"))
+	}
+	return errs
+}
+
+func validateGCPLocation(path *field.Path, project, zone string) field.ErrorList {
+	var errs field.ErrorList
+	if project == "" {
+		errs = append(errs, field.Required(path.Child("project"), "project must be specified"))
+	}
+	if zone == "" {
+		errs = append(errs, field.Required(path.Child("zone"), "zone must be specified"))
+	}
+	return errs
+}
+
+// desiredWarnings warns that desired is not checked against MaxSize of the node groups.
+// The webhook does not call cloud APIs, so MaxSize is not known here, and node groups are never scaled over it.
+func desiredWarnings(path *field.Path, desired int32) admission.Warnings {
+	if desired <= 0 {
+		return nil
+	}
+	return admission.Warnings{
+		fmt.Sprintf("%s: %d is not checked against MaxSize of the node groups, because the webhook does not call cloud APIs. Node groups are not scaled over MaxSize, so the sum of MaxSize must be %d or more", path.String(), desired, desired),
+	}
+}
+
+func validateNonNegative(path *field.Path, value int64) field.ErrorList {
+	var errs field.ErrorList
+	if value < 0 {
+		errs = append(errs, field.Invalid(path, value, "must be greater than or equal to 0"))
+	}
+	return errs
+}
+
+func validateNodes(path *field.Path, nodes *operatorv1alpha1.Nodes) field.ErrorList {
+	var errs field.ErrorList
+	errs = append(errs, validateAutoScalingGroupsOrTagSelector(path, nodes.AutoScalingGroups, nodes.TagSelector)...)
+	errs = append(errs, validateNonNegative(path.Child("desired"), int64(nodes.Desired))...)
+	errs = append(errs, validateRefreshStrategy(path.Child("refreshStrategy"), path.Child("instanceRefresh"), nodes.RefreshStrategy, nodes.InstanceRefresh, operatorv1alpha1.CloudProviderAWS)...)
+	errs = append(errs, validateRefreshTrigger(path.Child("refreshTrigger"), nodes.RefreshTrigger, operatorv1alpha1.CloudProviderAWS)...)
+	errs = append(errs, validateAMISource(path.Child("amiSource"), nodes.AMISource, nodes.RefreshTrigger, operatorv1alpha1.CloudProviderAWS)...)
+	errs = append(errs, validateRefreshOptions(path, &nodes.RefreshOptions, operatorv1alpha1.CloudProviderAWS, nodes.RefreshTrigger, nodes.AMISource, nodes.RefreshStrategy)...)
+	return errs
+}
+
+func validateGCPNodes(path *field.Path, nodes *operatorv1alpha1.GCPNodes) field.ErrorList {
+	var errs field.ErrorList
+	errs = append(errs, validateInstanceGroups(path.Child("instanceGroups"), nodes.InstanceGroups)...)
+	errs = append(errs, validateNonNegative(path.Child("desired"), int64(nodes.Desired))...)
+	errs = append(errs, validateRefreshOptions(path, &nodes.RefreshOptions, operatorv1alpha1.CloudProviderGCP, "", nil, "")...)
+	return errs
+}
+
+func validateAzureNodes(path *field.Path, nodes *operatorv1alpha1.AzureNodes) field.ErrorList {
+	var errs field.ErrorList
+	errs = append(errs, validateScaleSets(path.Child("scaleSets"), nodes.ScaleSets)...)
+	errs = append(errs, validateNonNegative(path.Child("desired"), int64(nodes.Desired))...)
+	errs = append(errs, validateRefreshOptions(path, &nodes.RefreshOptions, operatorv1alpha1.CloudProviderAzure, "", nil, "")...)
+	return errs
+}
+
+func validateClusterAPINodes(path *field.Path, nodes *operatorv1alpha1.ClusterAPINodes) field.ErrorList {
+	var errs field.ErrorList
+	errs = append(errs, validateMachineDeployments(path.Child("machineDeployments"), nodes.MachineDeployments)...)
+	errs = append(errs, validateNonNegative(path.Child("desired"), int64(nodes.Desired))...)
+	errs = append(errs, validateRefreshOptions(path, &nodes.RefreshOptions, operatorv1alpha1.CloudProviderClusterAPI, "", nil, "")...)
+	return errs
+}
+
+// validateRefreshOptions checks the options which are common to all cloud providers.
+// trigger, source and strategy are options only on AWS, and they are empty in other cloud providers.
+func validateRefreshOptions(path *field.Path, options *operatorv1alpha1.RefreshOptions, cloudProvider string, trigger operatorv1alpha1.RefreshTrigger, source *operatorv1alpha1.AMISource, strategy operatorv1alpha1.RefreshStrategy) field.ErrorList {
+	var errs field.ErrorList
+	errs = append(errs, validateNonNegative(path.Child("asgModifyCoolTimeSeconds"), options.ASGModifyCoolTimeSeconds)...)
+	errs = append(errs, validateSchedule(path.Child("refreshSchedule"), options.RefreshSchedule, false)...)
+	errs = append(errs, validateNonNegative(path.Child("surplusNodes"), options.SurplusNodes)...)
+	errs = append(errs, validateNonNegative(path.Child("drainGracePeriodSeconds"), options.DrainGracePeriodSeconds)...)
+	errs = append(errs, validateMaxNodeAge(path.Child("maxNodeAge"), options.MaxNodeAge, trigger, source, strategy)...)
+	errs = append(errs, validateMaintenanceWindow(path.Child("maintenanceWindow"), options.MaintenanceWindow, options.RefreshSchedule)...)
+	errs = append(errs, validatePhaseTimeouts(path.Child("refreshTimeouts"), options.RefreshTimeouts)...)
+	errs = append(errs, validateSurge(path, options.MaxSurge, options.MaxUnavailable, options.SurplusNodes, strategy)...)
+	errs = append(errs, validateReplacementOrder(path.Child("replacementOrder"), options.ReplacementOrder, cloudProvider, strategy)...)
+	return errs
+}
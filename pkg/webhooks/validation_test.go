@@ -0,0 +1,757 @@
+package webhooks
+
+import (
+	"log"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	utilpointer "k8s.io/utils/pointer"
+
+	operatorv1alpha1 "github.com/h3poteto/node-manager/api/v1alpha1"
+)
+
+func TestValidateSchedule(t *testing.T) {
+	cases := []struct {
+		title    string
+		schedule string
+		required bool
+		expected int
+	}{
+		{
+			title:    "Valid cron expression",
+			schedule: "3 10 * * *",
+			required: true,
+			expected: 0,
+		},
+		{
+			title:    "Invalid cron expression",
+			schedule: "every day",
+			required: false,
+			expected: 1,
+		},
+		{
+			title:    "Empty schedule is optional",
+			schedule: "",
+			required: false,
+			expected: 0,
+		},
+		{
+			title:    "Empty schedule is required",
+			schedule: "",
+			required: true,
+			expected: 1,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+
+		errs := validateSchedule(field.NewPath("spec", "schedule"), c.schedule, c.required)
+		if len(errs) != c.expected {
+			t.Errorf("CASE: %s : errors count is not matched, expected %d, but returned %v", c.title, c.expected, errs)
+		}
+	}
+}
+
+func TestValidateAutoScalingGroups(t *testing.T) {
+	cases := []struct {
+		title    string
+		groups   []operatorv1alpha1.AutoScalingGroup
+		expected field.ErrorList
+	}{
+		{
+			title: "Valid AutoScalingGroups",
+			groups: []operatorv1alpha1.AutoScalingGroup{
+				{
+					Name: "asg-a",
+				},
+				{
+					Name: "asg-c",
+				},
+			},
+			expected: nil,
+		},
+		{
+			title:  "Empty AutoScalingGroups",
+			groups: []operatorv1alpha1.AutoScalingGroup{},
+			expected: field.ErrorList{
+				field.Required(field.NewPath("spec", "autoScalingGroups"), ""),
+			},
+		},
+		{
+			title: "Duplicated AutoScalingGroups",
+			groups: []operatorv1alpha1.AutoScalingGroup{
+				{
+					Name: "asg-a",
+				},
+				{
+					Name: "asg-c",
+				},
+				{
+					Name: "asg-a",
+				},
+			},
+			expected: field.ErrorList{
+				field.Duplicate(field.NewPath("spec", "autoScalingGroups").Index(2).Child("name"), "asg-a"),
+			},
+		},
+		{
+			title: "Empty AutoScalingGroup name",
+			groups: []operatorv1alpha1.AutoScalingGroup{
+				{
+					Name: "",
+				},
+			},
+			expected: field.ErrorList{
+				field.Required(field.NewPath("spec", "autoScalingGroups").Index(0).Child("name"), ""),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+
+		errs := validateAutoScalingGroups(field.NewPath("spec", "autoScalingGroups"), c.groups)
+		if len(errs) != len(c.expected) {
+			t.Errorf("CASE: %s : errors count is not matched, expected %v, but returned %v", c.title, c.expected, errs)
+			continue
+		}
+		for i := range errs {
+			if errs[i].Type != c.expected[i].Type || errs[i].Field != c.expected[i].Field {
+				t.Errorf("CASE: %s : error is not matched, expected %v, but returned %v", c.title, c.expected[i], errs[i])
+			}
+		}
+	}
+}
+
+func TestValidateAutoScalingGroupsOrTagSelector(t *testing.T) {
+	cases := []struct {
+		title       string
+		groups      []operatorv1alpha1.AutoScalingGroup
+		tagSelector map[string]string
+		expected    field.ErrorList
+	}{
+		{
+			title: "Only AutoScalingGroups",
+			groups: []operatorv1alpha1.AutoScalingGroup{
+				{
+					Name: "asg-a",
+				},
+			},
+			expected: nil,
+		},
+		{
+			title: "Only tagSelector",
+			tagSelector: map[string]string{
+				"k8s.io/cluster-autoscaler/enabled": "",
+			},
+			expected: nil,
+		},
+		{
+			title:    "Neither AutoScalingGroups nor tagSelector",
+			expected: field.ErrorList{field.Required(field.NewPath("spec", "autoScalingGroups"), "")},
+		},
+		{
+			title: "Empty tag key",
+			tagSelector: map[string]string{
+				"": "owned",
+			},
+			expected: field.ErrorList{field.Invalid(field.NewPath("spec", "tagSelector"), nil, "")},
+		},
+		{
+			title: "Duplicated AutoScalingGroups with tagSelector",
+			groups: []operatorv1alpha1.AutoScalingGroup{
+				{
+					Name: "asg-a",
+				},
+				{
+					Name: "asg-a",
+				},
+			},
+			tagSelector: map[string]string{
+				"role": "worker",
+			},
+			expected: field.ErrorList{field.Duplicate(field.NewPath("spec", "autoScalingGroups").Index(1).Child("name"), "asg-a")},
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+
+		errs := validateAutoScalingGroupsOrTagSelector(field.NewPath("spec"), c.groups, c.tagSelector)
+		if len(errs) != len(c.expected) {
+			t.Errorf("CASE: %s : errors count is not matched, expected %v, but returned %v", c.title, c.expected, errs)
+			continue
+		}
+		for i := range errs {
+			if errs[i].Type != c.expected[i].Type || errs[i].Field != c.expected[i].Field {
+				t.Errorf("CASE: %s : error is not matched, expected %v, but returned %v", c.title, c.expected[i], errs[i])
+			}
+		}
+	}
+}
+
+func TestValidateAssumeRole(t *testing.T) {
+	cases := []struct {
+		title      string
+		roleARN    string
+		externalID string
+		expected   int
+	}{
+		{
+			title:    "Role is not specified",
+			expected: 0,
+		},
+		{
+			title:      "Role with external ID",
+			roleARN:    "arn:aws:iam::123456789012:role/node-manager",
+			externalID: "external",
+			expected:   0,
+		},
+		{
+			title:    "Role in other partition",
+			roleARN:  "arn:aws-cn:iam::123456789012:role/path/node-manager",
+			expected: 0,
+		},
+		{
+			title:    "Not an ARN",
+			roleARN:  "node-manager",
+			expected: 1,
+		},
+		{
+			title:    "ARN of a user",
+			roleARN:  "arn:aws:iam::123456789012:user/node-manager",
+			expected: 1,
+		},
+		{
+			title:      "External ID without role",
+			externalID: "external",
+			expected:   1,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		errs := validateAssumeRole(field.NewPath("spec", "aws"), c.roleARN, c.externalID)
+		if len(errs) != c.expected {
+			t.Errorf("CASE: %s : errors count is not matched, expected %d, but returned %v", c.title, c.expected, errs)
+		}
+	}
+}
+
+func TestValidateEndpoint(t *testing.T) {
+	cases := []struct {
+		title    string
+		endpoint string
+		expected int
+	}{
+		{
+			title:    "Endpoint is not specified",
+			endpoint: "",
+			expected: 0,
+		},
+		{
+			title:    "LocalStack endpoint",
+			endpoint: "http://localstack.localstack.svc:4566",
+			expected: 0,
+		},
+		{
+			title:    "Endpoint without scheme",
+			endpoint: "localhost:4566",
+			expected: 1,
+		},
+		{
+			title:    "Endpoint with unsupported scheme",
+			endpoint: "ftp://localhost:4566",
+			expected: 1,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		errs := validateEndpoint(field.NewPath("spec", "aws", "endpoint"), c.endpoint)
+		if len(errs) != c.expected {
+			t.Errorf("CASE: %s : errors count is not matched, expected %d, but returned %v", c.title, c.expected, errs)
+		}
+	}
+}
+
+func TestValidateRefreshStrategy(t *testing.T) {
+	cases := []struct {
+		title         string
+		strategy      operatorv1alpha1.RefreshStrategy
+		preferences   *operatorv1alpha1.InstanceRefreshPreferences
+		cloudProvider string
+		expected      int
+	}{
+		{
+			title:         "Strategy is not specified",
+			strategy:      "",
+			preferences:   nil,
+			cloudProvider: operatorv1alpha1.CloudProviderAWS,
+			expected:      0,
+		},
+		{
+			title:         "Rolling strategy with preferences",
+			strategy:      operatorv1alpha1.RefreshStrategyRolling,
+			preferences:   &operatorv1alpha1.InstanceRefreshPreferences{},
+			cloudProvider: operatorv1alpha1.CloudProviderAWS,
+			expected:      1,
+		},
+		{
+			title:         "Unknown strategy",
+			strategy:      "blueGreen",
+			preferences:   nil,
+			cloudProvider: operatorv1alpha1.CloudProviderAWS,
+			expected:      1,
+		},
+		{
+			title:         "InstanceRefresh strategy on Azure",
+			strategy:      operatorv1alpha1.RefreshStrategyInstanceRefresh,
+			preferences:   nil,
+			cloudProvider: operatorv1alpha1.CloudProviderAzure,
+			expected:      1,
+		},
+		{
+			title:    "Valid preferences",
+			strategy: operatorv1alpha1.RefreshStrategyInstanceRefresh,
+			preferences: &operatorv1alpha1.InstanceRefreshPreferences{
+				MinHealthyPercentage:   utilpointer.Int64Ptr(90),
+				InstanceWarmupSeconds:  utilpointer.Int64Ptr(300),
+				CheckpointPercentages:  []int64{20, 50, 100},
+				CheckpointDelaySeconds: utilpointer.Int64Ptr(600),
+				SkipMatching:           true,
+				LifecycleHookName:      "drain",
+			},
+			cloudProvider: operatorv1alpha1.CloudProviderAWS,
+			expected:      0,
+		},
+		{
+			title:    "MinHealthyPercentage is out of range",
+			strategy: operatorv1alpha1.RefreshStrategyInstanceRefresh,
+			preferences: &operatorv1alpha1.InstanceRefreshPreferences{
+				MinHealthyPercentage:  utilpointer.Int64Ptr(110),
+				InstanceWarmupSeconds: utilpointer.Int64Ptr(-1),
+			},
+			cloudProvider: operatorv1alpha1.CloudProviderAWS,
+			expected:      2,
+		},
+		{
+			title:    "Checkpoints are not ascending",
+			strategy: operatorv1alpha1.RefreshStrategyInstanceRefresh,
+			preferences: &operatorv1alpha1.InstanceRefreshPreferences{
+				CheckpointPercentages: []int64{50, 20, 100},
+			},
+			cloudProvider: operatorv1alpha1.CloudProviderAWS,
+			expected:      1,
+		},
+		{
+			title:    "Last checkpoint is not 100",
+			strategy: operatorv1alpha1.RefreshStrategyInstanceRefresh,
+			preferences: &operatorv1alpha1.InstanceRefreshPreferences{
+				CheckpointPercentages: []int64{20, 50},
+			},
+			cloudProvider: operatorv1alpha1.CloudProviderAWS,
+			expected:      1,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		spec := field.NewPath("spec")
+		errs := validateRefreshStrategy(spec.Child("strategy"), spec.Child("instanceRefresh"), c.strategy, c.preferences, c.cloudProvider)
+		if len(errs) != c.expected {
+			t.Errorf("CASE: %s : errors count is not matched, expected %d, but returned %v", c.title, c.expected, errs)
+		}
+	}
+}
+
+func TestValidateRefreshTrigger(t *testing.T) {
+	cases := []struct {
+		title         string
+		trigger       operatorv1alpha1.RefreshTrigger
+		cloudProvider string
+		expected      int
+	}{
+		{
+			title:         "Trigger is not specified",
+			trigger:       "",
+			cloudProvider: operatorv1alpha1.CloudProviderGCP,
+			expected:      0,
+		},
+		{
+			title:         "Drift trigger on AWS",
+			trigger:       operatorv1alpha1.RefreshTriggerDrift,
+			cloudProvider: operatorv1alpha1.CloudProviderAWS,
+			expected:      0,
+		},
+		{
+			title:         "Drift trigger on GCP",
+			trigger:       operatorv1alpha1.RefreshTriggerDrift,
+			cloudProvider: operatorv1alpha1.CloudProviderGCP,
+			expected:      1,
+		},
+		{
+			title:         "Unknown trigger",
+			trigger:       "manual",
+			cloudProvider: operatorv1alpha1.CloudProviderAWS,
+			expected:      1,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		errs := validateRefreshTrigger(field.NewPath("spec", "trigger"), c.trigger, c.cloudProvider)
+		if len(errs) != c.expected {
+			t.Errorf("CASE: %s : errors count is not matched, expected %d, but returned %v", c.title, c.expected, errs)
+		}
+	}
+}
+
+func TestValidateAMISource(t *testing.T) {
+	cases := []struct {
+		title         string
+		source        *operatorv1alpha1.AMISource
+		trigger       operatorv1alpha1.RefreshTrigger
+		cloudProvider string
+		expected      int
+	}{
+		{
+			title:         "AMI source is not specified",
+			source:        nil,
+			trigger:       operatorv1alpha1.RefreshTriggerDrift,
+			cloudProvider: operatorv1alpha1.CloudProviderGCP,
+			expected:      0,
+		},
+		{
+			title:         "AMI source on AWS",
+			source:        &operatorv1alpha1.AMISource{SSMParameter: "/aws/service/bottlerocket/aws-k8s-1.30/x86_64/latest/image_id"},
+			trigger:       "",
+			cloudProvider: operatorv1alpha1.CloudProviderAWS,
+			expected:      0,
+		},
+		{
+			title:         "Parameter is empty",
+			source:        &operatorv1alpha1.AMISource{},
+			trigger:       "",
+			cloudProvider: operatorv1alpha1.CloudProviderAWS,
+			expected:      1,
+		},
+		{
+			title:         "AMI source with drift trigger",
+			source:        &operatorv1alpha1.AMISource{SSMParameter: "ami"},
+			trigger:       operatorv1alpha1.RefreshTriggerDrift,
+			cloudProvider: operatorv1alpha1.CloudProviderAWS,
+			expected:      1,
+		},
+		{
+			title:         "AMI source on GCP",
+			source:        &operatorv1alpha1.AMISource{SSMParameter: "ami"},
+			trigger:       "",
+			cloudProvider: operatorv1alpha1.CloudProviderGCP,
+			expected:      1,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		errs := validateAMISource(field.NewPath("spec", "amiSource"), c.source, c.trigger, c.cloudProvider)
+		if len(errs) != c.expected {
+			t.Errorf("CASE: %s : errors count is not matched, expected %d, but returned %v", c.title, c.expected, errs)
+		}
+	}
+}
+
+func TestValidateMaxNodeAge(t *testing.T) {
+	cases := []struct {
+		title    string
+		age      *metav1.Duration
+		trigger  operatorv1alpha1.RefreshTrigger
+		source   *operatorv1alpha1.AMISource
+		strategy operatorv1alpha1.RefreshStrategy
+		expected int
+	}{
+		{
+			title:    "Max node age is not specified",
+			age:      nil,
+			trigger:  operatorv1alpha1.RefreshTriggerDrift,
+			strategy: operatorv1alpha1.RefreshStrategyInstanceRefresh,
+			expected: 0,
+		},
+		{
+			title:    "Max node age is valid",
+			age:      &metav1.Duration{Duration: 168 * time.Hour},
+			expected: 0,
+		},
+		{
+			title:    "Max node age is zero",
+			age:      &metav1.Duration{Duration: 0},
+			expected: 1,
+		},
+		{
+			title:    "Max node age with drift trigger",
+			age:      &metav1.Duration{Duration: 168 * time.Hour},
+			trigger:  operatorv1alpha1.RefreshTriggerDrift,
+			expected: 1,
+		},
+		{
+			title:    "Max node age with AMI source",
+			age:      &metav1.Duration{Duration: 168 * time.Hour},
+			source:   &operatorv1alpha1.AMISource{SSMParameter: "ami"},
+			expected: 1,
+		},
+		{
+			title:    "Max node age with instanceRefresh strategy",
+			age:      &metav1.Duration{Duration: 168 * time.Hour},
+			strategy: operatorv1alpha1.RefreshStrategyInstanceRefresh,
+			expected: 1,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		errs := validateMaxNodeAge(field.NewPath("spec", "maxNodeAge"), c.age, c.trigger, c.source, c.strategy)
+		if len(errs) != c.expected {
+			t.Errorf("CASE: %s : errors count is not matched, expected %d, but returned %v", c.title, c.expected, errs)
+		}
+	}
+}
+
+func TestValidateMaintenanceWindow(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		title    string
+		window   *operatorv1alpha1.MaintenanceWindow
+		schedule string
+		expected int
+	}{
+		{
+			title:    "Maintenance window is not specified",
+			window:   nil,
+			schedule: "0 3 * * *",
+			expected: 0,
+		},
+		{
+			title: "Maintenance window is valid",
+			window: &operatorv1alpha1.MaintenanceWindow{
+				Schedule: "0 22 * * 1-5",
+				Duration: metav1.Duration{Duration: 4 * time.Hour},
+				TimeZone: "Asia/Tokyo",
+				Blackouts: []operatorv1alpha1.BlackoutPeriod{
+					{
+						Start: metav1.NewTime(now),
+						End:   metav1.NewTime(now.Add(24 * time.Hour)),
+					},
+				},
+			},
+			expected: 0,
+		},
+		{
+			title: "Maintenance window with schedule",
+			window: &operatorv1alpha1.MaintenanceWindow{
+				Schedule: "0 22 * * *",
+				Duration: metav1.Duration{Duration: 4 * time.Hour},
+			},
+			schedule: "0 3 * * *",
+			expected: 1,
+		},
+		{
+			title: "Schedule, duration and time zone are invalid",
+			window: &operatorv1alpha1.MaintenanceWindow{
+				Schedule: "invalid",
+				Duration: metav1.Duration{Duration: 0},
+				TimeZone: "Mars/Olympus",
+			},
+			expected: 3,
+		},
+		{
+			title: "Blackout ends before start",
+			window: &operatorv1alpha1.MaintenanceWindow{
+				Schedule: "0 22 * * *",
+				Duration: metav1.Duration{Duration: 4 * time.Hour},
+				Blackouts: []operatorv1alpha1.BlackoutPeriod{
+					{
+						Start: metav1.NewTime(now),
+						End:   metav1.NewTime(now.Add(-1 * time.Hour)),
+					},
+				},
+			},
+			expected: 1,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		errs := validateMaintenanceWindow(field.NewPath("spec", "maintenanceWindow"), c.window, c.schedule)
+		if len(errs) != c.expected {
+			t.Errorf("CASE: %s : errors count is not matched, expected %d, but returned %v", c.title, c.expected, errs)
+		}
+	}
+}
+
+func TestValidatePhaseTimeouts(t *testing.T) {
+	cases := []struct {
+		title    string
+		timeouts *operatorv1alpha1.PhaseTimeouts
+		expected int
+	}{
+		{
+			title:    "Timeouts are not specified",
+			timeouts: nil,
+			expected: 0,
+		},
+		{
+			title: "Timeouts are valid",
+			timeouts: &operatorv1alpha1.PhaseTimeouts{
+				Increase: &metav1.Duration{Duration: 30 * time.Minute},
+				AWSWait:  &metav1.Duration{Duration: 30 * time.Minute},
+			},
+			expected: 0,
+		},
+		{
+			title: "Timeouts are not positive",
+			timeouts: &operatorv1alpha1.PhaseTimeouts{
+				Replace:  &metav1.Duration{Duration: 0},
+				Decrease: &metav1.Duration{Duration: -1 * time.Minute},
+			},
+			expected: 2,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		errs := validatePhaseTimeouts(field.NewPath("spec", "timeouts"), c.timeouts)
+		if len(errs) != c.expected {
+			t.Errorf("CASE: %s : errors count is not matched, expected %d, but returned %v", c.title, c.expected, errs)
+		}
+	}
+}
+
+func TestValidateSurge(t *testing.T) {
+	percent := intstr.FromString("25%")
+	one := intstr.FromInt32(1)
+	zero := intstr.FromInt32(0)
+	negative := intstr.FromInt32(-1)
+	invalid := intstr.FromString("25")
+	tooMany := intstr.FromString("150%")
+	cases := []struct {
+		title        string
+		surge        *intstr.IntOrString
+		unavailable  *intstr.IntOrString
+		surplusNodes int64
+		strategy     operatorv1alpha1.RefreshStrategy
+		expected     int
+	}{
+		{
+			title:    "Surge is not specified",
+			expected: 0,
+		},
+		{
+			title:       "Surge and unavailable are valid",
+			surge:       &percent,
+			unavailable: &one,
+			expected:    0,
+		},
+		{
+			title:        "Unavailable is zero with surplus nodes",
+			unavailable:  &zero,
+			surplusNodes: 1,
+			expected:     0,
+		},
+		{
+			title:       "Surge and unavailable are zero",
+			surge:       &zero,
+			unavailable: &zero,
+			expected:    1,
+		},
+		{
+			title:    "Surge is zero without unavailable",
+			surge:    &zero,
+			expected: 1,
+		},
+		{
+			title:       "Surge is negative",
+			surge:       &negative,
+			unavailable: &one,
+			expected:    1,
+		},
+		{
+			title:       "Surge is not a percentage",
+			surge:       &invalid,
+			unavailable: &one,
+			expected:    1,
+		},
+		{
+			title:       "Unavailable is over 100%",
+			surge:       &one,
+			unavailable: &tooMany,
+			expected:    1,
+		},
+		{
+			title:    "Instance refresh strategy",
+			surge:    &one,
+			strategy: operatorv1alpha1.RefreshStrategyInstanceRefresh,
+			expected: 1,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		errs := validateSurge(field.NewPath("spec"), c.surge, c.unavailable, c.surplusNodes, c.strategy)
+		if len(errs) != c.expected {
+			t.Errorf("CASE: %s : errors count is not matched, expected %d, but returned %v", c.title, c.expected, errs)
+		}
+	}
+}
+
+func TestValidateReplacementOrder(t *testing.T) {
+	cases := []struct {
+		title         string
+		order         operatorv1alpha1.ReplacementOrder
+		cloudProvider string
+		strategy      operatorv1alpha1.RefreshStrategy
+		expected      int
+	}{
+		{
+			title:    "Order is not specified",
+			order:    "",
+			strategy: operatorv1alpha1.RefreshStrategyInstanceRefresh,
+			expected: 0,
+		},
+		{
+			title:         "AZ balanced on GCP",
+			order:         operatorv1alpha1.ReplacementOrderAZBalanced,
+			cloudProvider: operatorv1alpha1.CloudProviderGCP,
+			expected:      0,
+		},
+		{
+			title:         "Instance type mismatch on AWS",
+			order:         operatorv1alpha1.ReplacementOrderInstanceTypeMismatch,
+			cloudProvider: operatorv1alpha1.CloudProviderAWS,
+			expected:      0,
+		},
+		{
+			title:         "Instance type mismatch on GCP",
+			order:         operatorv1alpha1.ReplacementOrderInstanceTypeMismatch,
+			cloudProvider: operatorv1alpha1.CloudProviderGCP,
+			expected:      1,
+		},
+		{
+			title:    "Instance refresh strategy",
+			order:    operatorv1alpha1.ReplacementOrderLeastPods,
+			strategy: operatorv1alpha1.RefreshStrategyInstanceRefresh,
+			expected: 1,
+		},
+	}
+
+	for _, c := range cases {
+		log.Printf("Running CASE: %s", c.title)
+		errs := validateReplacementOrder(field.NewPath("spec", "replacementOrder"), c.order, c.cloudProvider, c.strategy)
+		if len(errs) != c.expected {
+			t.Errorf("CASE: %s : errors count is not matched, expected %d, but returned %v", c.title, c.expected, errs)
+		}
+	}
+}